@@ -0,0 +1,27 @@
+package main
+
+import (
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/secrets"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// loadConfig reads the config from env and CONFIG_FILE and resolves
+// secrets. Callers validate the parts they need.
+func loadConfig(ctx context.Context, logger *logrus.Logger) (*config.Store, *secrets.Manager, error) {
+	configPath := os.Getenv("CONFIG_FILE")
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	configStore := config.NewStore(configPath, cfg)
+	secretManager := secrets.NewManager(configStore, secrets.Providers(cfg), logger)
+	if _, err := secretManager.Refresh(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+	return configStore, secretManager, nil
+}
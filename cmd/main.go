@@ -3,16 +3,24 @@ package main
 import (
     "VKR_gateway_service/internal/app"
     "VKR_gateway_service/internal/config"
+    "VKR_gateway_service/internal/events"
     "VKR_gateway_service/internal/repository/postgres"
     "VKR_gateway_service/internal/transport/http"
     rpctransport "VKR_gateway_service/internal/transport/rpc"
     "VKR_gateway_service/pkg/logger"
     "VKR_gateway_service/pkg/storage"
+    "VKR_gateway_service/pkg/lifecycle"
     "context"
+    "errors"
+    "fmt"
     "os"
     "os/signal"
+    "strings"
     "syscall"
     "time"
+
+    "github.com/redis/go-redis/v9"
+    "github.com/sirupsen/logrus"
 )
 
 // @title ALib API
@@ -21,58 +29,183 @@ import (
 // @host localhost:8080
 // @BasePath /api
 func main() {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
 	// ! Init logger
 	logger := logger.LoggerSetup(true)
-	// ! Parse config from env
-	cfg, err := config.MustLoadConfig()
+
+	command, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+	switch command {
+	case "serve":
+		if err := serve(logger); err != nil {
+			logger.Fatal(err)
+		}
+	case "migrate":
+		os.Exit(runMigrate(logger, args))
+	case "version":
+		printVersion()
+	case "help", "-h", "--help":
+		usage(os.Stdout)
+	default:
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+}
+
+// serve runs the gateway until SIGINT or SIGTERM. Everything opened during
+// startup is registered with the lifecycle manager, so it is released on a
+// failed start as well as on shutdown.
+func serve(logger *logrus.Logger) (err error) {
+	loadCtx, cancelLoad := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelLoad()
+	// ! Parse config from env, CONFIG_FILE and secret providers
+	configStore, secretManager, err := loadConfig(loadCtx, logger)
 	if err != nil {
-		logger.Fatalf("Failed to load config with error: %v", err)
-		return
+		return fmt.Errorf("failed to load config: %w", err)
 	}
+	cfg := configStore.Config()
+	warnings, err := cfg.Check()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, warning := range warnings {
+		logger.Warnf("Config problem tolerated in development: %s", warning)
+	}
+
+	lc := lifecycle.New(logger)
+	defer func() {
+		if err != nil {
+			stopCtx, cancel := context.WithTimeout(context.Background(), cfg.LifecycleConfig.ShutdownTimeout)
+			defer cancel()
+			_ = lc.Shutdown(stopCtx, 0)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.LifecycleConfig.StartTimeout)
+	defer cancel()
+
 	// ! Init repoisitory
 	// ! Init postgres
-	pgPool, err := storage.PostgresConnect(ctx, cfg.PostgresConfig)
+	// Retries until DB_STARTUP_TIMEOUT while Postgres is starting
+	db, err := storage.PostgresConnect(ctx, cfg.PostgresConfig, func() config.PostgresConfig {
+		return configStore.Config().PostgresConfig
+	}, logger)
 	if err != nil {
-		logger.Fatalf("Failed to create pool conection to postgres with error: %v", err)
-		return
+		return fmt.Errorf("failed to create pool conection to postgres: %w", err)
 	}
+	lc.OnStop("postgres pool", func(context.Context) error {
+		db.Close()
+		return nil
+	})
 
-    UserRepo := postgres.NewUserRepository(pgPool)
+    if cfg.PostgresConfig.AutoMigrate {
+        if err := autoMigrate(db, logger); err != nil {
+            return fmt.Errorf("failed to migrate database: %w", err)
+        }
+    }
+
+    UserRepo := postgres.NewUserRepository(db)
+    CollectionRepo := postgres.NewCollectionRepository(db)
+    ChatShareRepo := postgres.NewChatShareRepository(db)
+    AuditRepo := postgres.NewAuditRepository(db)
+    APIKeyRepo := postgres.NewAPIKeyRepository(db)
 
     // Init gRPC client to external AI service
     aiClient, aiConn, err := rpctransport.NewAIClient(ctx, cfg.AIServiceAddress, cfg.GRPCTimeout)
     if err != nil {
-        logger.Fatalf("Failed to connect to AI gRPC service: %v", err)
-        return
+        return fmt.Errorf("failed to connect to AI gRPC service: %w", err)
+    }
+    lc.OnStop("AI gRPC connection", func(context.Context) error { return aiConn.Close() })
+
+    // Init Redis for event fan-out between replicas (optional)
+    var rdb *redis.Client
+    if cfg.RedisConfig.Host != "" {
+        rdb, err = storage.RedisConnect(ctx, cfg.RedisConfig, func() config.RedisConfig {
+            return configStore.Config().RedisConfig
+        })
+        if err != nil {
+            return fmt.Errorf("failed to connect to Redis: %w", err)
+        }
+        lc.OnStop("redis", func(context.Context) error { return rdb.Close() })
+    } else {
+        logger.Warn("REDIS_HOST is not set, live events are delivered within this instance only")
+    }
+    // Background workers stop after HTTP, before the connections they use
+    lc.OnStop("workers", lc.StopWorkers)
+    hub := events.NewHub(rdb, logger)
+    lc.Go(hub.Run)
+
+    // Rotated DB credentials apply to new connections; idle ones are
+    // replaced now, those running queries when they are released
+    resetPoolOnRotation := func(changed []string) {
+        for _, key := range changed {
+            if strings.HasPrefix(key, "postgres.") {
+                logger.Info("Postgres credentials rotated, recycling pool connections")
+                db.Reset()
+                return
+            }
+        }
     }
-    defer aiConn.Close()
+    secretManager.OnChange(func(_ *config.Config, changed []string) { resetPoolOnRotation(changed) })
+    lc.Go(secretManager.Run)
 
-    usecase := app.NewApp(cfg, UserRepo, logger, aiClient)
+    // Reload safe settings on SIGHUP or config file change
+    hup := make(chan os.Signal, 1)
+    signal.Notify(hup, syscall.SIGHUP)
+    lc.Go(func(ctx context.Context) {
+        configStore.Watch(ctx, hup, func(result config.ReloadResult, err error) {
+            if err != nil {
+                logger.Errorf("Config reload rejected, keeping current config: %v", err)
+                return
+            }
+            for _, warning := range result.Warnings {
+                logger.Warnf("Config problem tolerated in development: %s", warning)
+            }
+            if len(result.Applied) > 0 {
+                logger.Infof("Config reloaded, applied: %s", strings.Join(result.Applied, ", "))
+                resetPoolOnRotation(result.Applied)
+            }
+            if len(result.RestartRequired) > 0 {
+                logger.Warnf("Config changes require a restart: %s", strings.Join(result.RestartRequired, ", "))
+            }
+        })
+    })
+
+    usecase := app.NewApp(configStore, db, UserRepo, CollectionRepo, ChatShareRepo, AuditRepo, APIKeyRepo, logger, aiClient, hub, lc)
     // ! Init REST
-	// ! Graceful shutdown
-	server := http.NewHTTPServer(cfg, usecase)
+	server, err := http.NewHTTPServer(cfg, usecase)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP server: %w", err)
+	}
 	logger.Info("Start HTTP server")
+	listenErr := make(chan error, 1)
 	go func() {
-		err = server.Listen()
-		if err != nil {
-			logger.Fatalf("Failed to start HTTP server: %v", err)
-		}
+		listenErr <- server.Listen()
 	}()
-	//Wait for interrupt signal to shutdown server
+	// Requests in flight finish first, then WebSockets and SSE streams,
+	// which Shutdown does not track, are waited for
+	lc.OnStop("HTTP server", func(ctx context.Context) error {
+		return errors.Join(server.Stop(ctx), lc.WaitStreams(ctx))
+	})
+	lc.SetReady()
+
+	// ! Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	logger.Info("Shutdown HTTP Server ...")
-	err = server.Stop(ctx)
-	if err != nil {
-		logger.Fatal("Server Shutdown:", err)
-	}
 	select {
-	case <-ctx.Done():
-		logger.Info("Timeout stop server")
-	default:
-		logger.Info("Server exiting")
+	case sig := <-quit:
+		logger.Infof("Received %s, shutting down", sig)
+	case err := <-listenErr:
+		return fmt.Errorf("failed to start HTTP server: %w", err)
+	}
+	// A second signal terminates the process without draining
+	signal.Stop(quit)
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), cfg.LifecycleConfig.ShutdownTimeout)
+	defer stopCancel()
+	if err := lc.Shutdown(stopCtx, cfg.LifecycleConfig.DrainDelay); err != nil {
+		return fmt.Errorf("shutdown finished with errors: %w", err)
 	}
+	logger.Info("Server exiting")
+	return nil
 }
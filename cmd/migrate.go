@@ -0,0 +1,148 @@
+package main
+
+import (
+	migrations "VKR_gateway_service/db"
+	"VKR_gateway_service/pkg/migrate"
+	"VKR_gateway_service/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// autoMigrate applies pending migrations at startup. The advisory lock
+// makes replicas starting together wait for the first one.
+func autoMigrate(db *storage.DB, logger *logrus.Logger) error {
+	m, err := migrate.New(db.Primary, migrations.Migrations, "migrations", logger)
+	if err != nil {
+		return err
+	}
+	err = m.Up(context.Background(), 0)
+	if errors.Is(err, migrate.ErrNoChange) {
+		logger.Info("Database schema is up to date")
+		return nil
+	}
+	return err
+}
+
+// runMigrate implements the migrate subcommand and returns the exit code.
+func runMigrate(logger *logrus.Logger, args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return 2
+	}
+	action, args := args[0], args[1:]
+	number := func(def int64) (int64, error) {
+		if len(args) == 0 {
+			return def, nil
+		}
+		if len(args) > 1 {
+			return 0, fmt.Errorf("unexpected arguments %v", args[1:])
+		}
+		return strconv.ParseInt(args[0], 10, 64)
+	}
+
+	ctx := context.Background()
+	loadCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	configStore, _, err := loadConfig(loadCtx, logger)
+	cancel()
+	if err != nil {
+		logger.Errorf("Failed to load config with error: %v", err)
+		return 1
+	}
+	cfg := configStore.Config().PostgresConfig
+	if err := cfg.Validate(); err != nil {
+		logger.Errorf("Failed to load config with error: %v", err)
+		return 1
+	}
+	db, err := storage.PostgresConnect(ctx, cfg, nil, logger)
+	if err != nil {
+		logger.Errorf("Failed to connect to postgres: %v", err)
+		return 1
+	}
+	defer db.Close()
+	m, err := migrate.New(db.Primary, migrations.Migrations, "migrations", logger)
+	if err != nil {
+		logger.Error(err)
+		return 1
+	}
+
+	switch action {
+	case "up":
+		var n int64
+		if n, err = number(0); err == nil {
+			err = m.Up(ctx, int(n))
+		}
+	case "down":
+		var n int64
+		if n, err = number(1); err == nil {
+			err = m.Down(ctx, int(n))
+		}
+	case "to", "force":
+		if len(args) == 0 {
+			err = fmt.Errorf("migrate %s needs a version", action)
+			break
+		}
+		var v int64
+		if v, err = number(0); err != nil {
+			break
+		}
+		if action == "to" {
+			err = m.To(ctx, v)
+		} else {
+			err = m.Force(ctx, v)
+		}
+	case "status":
+		err = printStatus(ctx, m)
+	default:
+		usage(os.Stderr)
+		return 2
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		logger.Info("No migrations to apply")
+		return 0
+	}
+	if err != nil {
+		logger.Errorf("migrate %s: %v", action, err)
+		return 1
+	}
+	if action != "status" {
+		version, dirty, err := m.Version(ctx)
+		if err != nil {
+			logger.Error(err)
+			return 1
+		}
+		logger.WithFields(logrus.Fields{"version": version, "dirty": dirty}).Info("Schema version")
+	}
+	return 0
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) error {
+	statuses, version, dirty, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE")
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied"
+		}
+		if dirty && s.Version == version {
+			state = "dirty"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, state)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\nCurrent version: %d (latest %d)\n", version, m.Latest())
+	return nil
+}
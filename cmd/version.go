@@ -0,0 +1,50 @@
+package main
+
+import (
+	migrations "VKR_gateway_service/db"
+	"VKR_gateway_service/pkg/migrate"
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: gateway [command]
+
+Commands:
+  serve                  run the HTTP server (default)
+  migrate up [N]         apply all or N pending migrations
+  migrate down [N]       revert N applied migrations (default 1)
+  migrate to VERSION     migrate up or down to VERSION, -1 reverts all
+  migrate status         list migrations and the applied version
+  migrate force VERSION  mark VERSION as applied after fixing a failed migration
+  version                print build and schema versions
+`)
+}
+
+func printVersion() {
+	commit, modified := "unknown", false
+	goVersion := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		goVersion = info.GoVersion
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				commit = s.Value
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+	}
+	if modified {
+		commit += "-dirty"
+	}
+	schema := "none"
+	if m, err := migrate.New(nil, migrations.Migrations, "migrations", nil); err == nil && m.Latest() != migrate.NilVersion {
+		schema = fmt.Sprint(m.Latest())
+	}
+	fmt.Printf("gateway %s (commit %s, %s, schema version %s)\n", version, commit, goVersion, schema)
+}
@@ -0,0 +1,10 @@
+// Package db embeds the SQL migrations so the gateway binary can apply them
+// without the files on disk.
+package db
+
+import "embed"
+
+// Migrations holds migrations/<version>_<name>.up.sql and .down.sql files.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
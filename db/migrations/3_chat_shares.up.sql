@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS chat_shares (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    token_hash BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    CONSTRAINT chat_shares_token_hash_key UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS chat_shares_user_chat_idx ON chat_shares (user_id, chat_id);
@@ -0,0 +1,23 @@
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+DROP TRIGGER IF EXISTS audit_log_no_modify ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+DROP INDEX IF EXISTS audit_log_created_idx;
+DROP INDEX IF EXISTS audit_log_action_idx;
+
+ALTER TABLE audit_log
+    DROP COLUMN hash,
+    DROP COLUMN prev_hash,
+    DROP COLUMN status_code,
+    DROP COLUMN outcome,
+    DROP COLUMN client_ip,
+    DROP COLUMN request_id;
+
+ALTER TABLE audit_log ADD COLUMN target_id TEXT NOT NULL DEFAULT '';
+UPDATE audit_log SET target_id = target_ids[1] WHERE cardinality(target_ids) > 0;
+ALTER TABLE audit_log DROP COLUMN target_ids;
+
+ALTER TABLE audit_log ALTER COLUMN payload DROP DEFAULT;
+ALTER TABLE audit_log ALTER COLUMN payload TYPE JSONB USING COALESCE(NULLIF(payload, ''), '{}')::jsonb;
+ALTER TABLE audit_log ALTER COLUMN payload SET DEFAULT '{}';
+ALTER TABLE audit_log RENAME COLUMN payload TO details;
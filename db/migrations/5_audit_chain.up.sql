@@ -0,0 +1,34 @@
+ALTER TABLE audit_log RENAME COLUMN details TO payload;
+ALTER TABLE audit_log ALTER COLUMN payload DROP DEFAULT;
+ALTER TABLE audit_log ALTER COLUMN payload TYPE TEXT USING payload::text;
+ALTER TABLE audit_log ALTER COLUMN payload SET DEFAULT '';
+
+ALTER TABLE audit_log ADD COLUMN target_ids TEXT[] NOT NULL DEFAULT '{}';
+UPDATE audit_log SET target_ids = ARRAY[target_id] WHERE target_id <> '';
+ALTER TABLE audit_log DROP COLUMN target_id;
+
+-- Rows written before hash chaining keep NULL hashes, the chain starts after them.
+ALTER TABLE audit_log
+    ADD COLUMN request_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN client_ip TEXT NOT NULL DEFAULT '',
+    ADD COLUMN outcome TEXT NOT NULL DEFAULT '',
+    ADD COLUMN status_code INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN prev_hash BYTEA,
+    ADD COLUMN hash BYTEA;
+
+CREATE INDEX IF NOT EXISTS audit_log_action_idx ON audit_log (action, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_created_idx ON audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_modify
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
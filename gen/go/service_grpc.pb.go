@@ -31,6 +31,8 @@ const (
 	SemanticService_GetAuthorPapers_FullMethodName = "/semantic.SemanticService/GetAuthorPapers"
 	SemanticService_SearchPaper_FullMethodName     = "/semantic.SemanticService/SearchPaper"
 	SemanticService_AddPaper_FullMethodName        = "/semantic.SemanticService/AddPaper"
+	SemanticService_GetPaper_FullMethodName        = "/semantic.SemanticService/GetPaper"
+	SemanticService_GetPapers_FullMethodName       = "/semantic.SemanticService/GetPapers"
 )
 
 // SemanticServiceClient is the client API for SemanticService service.
@@ -49,6 +51,10 @@ type SemanticServiceClient interface {
 	GetAuthorPapers(ctx context.Context, in *AuthorPaperReq, opts ...grpc.CallOption) (*PapersResponse, error)
 	SearchPaper(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*PapersResponse, error)
 	AddPaper(ctx context.Context, in *AddRequest, opts ...grpc.CallOption) (*ErrorResponse, error)
+	// NOT_FOUND if the paper is not in the index
+	GetPaper(ctx context.Context, in *PaperReq, opts ...grpc.CallOption) (*PaperDetail, error)
+	// Papers missing from the index are left out of the response
+	GetPapers(ctx context.Context, in *PapersReq, opts ...grpc.CallOption) (*PaperDetails, error)
 }
 
 type semanticServiceClient struct {
@@ -179,6 +185,26 @@ func (c *semanticServiceClient) AddPaper(ctx context.Context, in *AddRequest, op
 	return out, nil
 }
 
+func (c *semanticServiceClient) GetPaper(ctx context.Context, in *PaperReq, opts ...grpc.CallOption) (*PaperDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaperDetail)
+	err := c.cc.Invoke(ctx, SemanticService_GetPaper_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *semanticServiceClient) GetPapers(ctx context.Context, in *PapersReq, opts ...grpc.CallOption) (*PaperDetails, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PaperDetails)
+	err := c.cc.Invoke(ctx, SemanticService_GetPapers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SemanticServiceServer is the server API for SemanticService service.
 // All implementations must embed UnimplementedSemanticServiceServer
 // for forward compatibility.
@@ -195,6 +221,10 @@ type SemanticServiceServer interface {
 	GetAuthorPapers(context.Context, *AuthorPaperReq) (*PapersResponse, error)
 	SearchPaper(context.Context, *SearchRequest) (*PapersResponse, error)
 	AddPaper(context.Context, *AddRequest) (*ErrorResponse, error)
+	// NOT_FOUND if the paper is not in the index
+	GetPaper(context.Context, *PaperReq) (*PaperDetail, error)
+	// Papers missing from the index are left out of the response
+	GetPapers(context.Context, *PapersReq) (*PaperDetails, error)
 	mustEmbedUnimplementedSemanticServiceServer()
 }
 
@@ -241,6 +271,12 @@ func (UnimplementedSemanticServiceServer) SearchPaper(context.Context, *SearchRe
 func (UnimplementedSemanticServiceServer) AddPaper(context.Context, *AddRequest) (*ErrorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddPaper not implemented")
 }
+func (UnimplementedSemanticServiceServer) GetPaper(context.Context, *PaperReq) (*PaperDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPaper not implemented")
+}
+func (UnimplementedSemanticServiceServer) GetPapers(context.Context, *PapersReq) (*PaperDetails, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPapers not implemented")
+}
 func (UnimplementedSemanticServiceServer) mustEmbedUnimplementedSemanticServiceServer() {}
 func (UnimplementedSemanticServiceServer) testEmbeddedByValue()                         {}
 
@@ -478,6 +514,42 @@ func _SemanticService_AddPaper_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SemanticService_GetPaper_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaperReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SemanticServiceServer).GetPaper(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SemanticService_GetPaper_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SemanticServiceServer).GetPaper(ctx, req.(*PaperReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SemanticService_GetPapers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PapersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SemanticServiceServer).GetPapers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SemanticService_GetPapers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SemanticServiceServer).GetPapers(ctx, req.(*PapersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SemanticService_ServiceDesc is the grpc.ServiceDesc for SemanticService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -533,6 +605,14 @@ var SemanticService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddPaper",
 			Handler:    _SemanticService_AddPaper_Handler,
 		},
+		{
+			MethodName: "GetPaper",
+			Handler:    _SemanticService_GetPaper_Handler,
+		},
+		{
+			MethodName: "GetPapers",
+			Handler:    _SemanticService_GetPapers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
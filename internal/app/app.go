@@ -2,28 +2,65 @@ package app
 
 import (
     "VKR_gateway_service/internal/config"
+    "VKR_gateway_service/internal/events"
     "VKR_gateway_service/internal/repository"
+    "VKR_gateway_service/pkg/lifecycle"
+    "VKR_gateway_service/pkg/storage"
     pb "VKR_gateway_service/gen/go"
 
     "github.com/sirupsen/logrus"
 )
 
 type App struct {
-    Config *config.Config
+    // Current config, reloaded on SIGHUP or config file change
+    ConfigStore *config.Store
+    // Postgres pools, repositories below use them
+    DB          *storage.DB
     Logger *logrus.Logger
     // gRPC client for external AI service
     AI     pb.SemanticServiceClient
+    // Gateway-owned data
+    Collections repository.CollectionRepository
+    Shares      repository.ChatShareRepository
+    Users       repository.UserRepository
+    Audit       repository.AuditRepository
+    APIKeys     repository.APIKeyRepository
+    // Live updates pushed to user sockets
+    Events *events.Hub
+    // Readiness and open streams, drained on shutdown
+    Lifecycle *lifecycle.Manager
 }
 
 func NewApp(
-    cfg *config.Config,
+    ConfigStore *config.Store,
+    DB *storage.DB,
     UserRepository repository.UserRepository,
+    CollectionRepository repository.CollectionRepository,
+    ChatShareRepository repository.ChatShareRepository,
+    AuditRepository repository.AuditRepository,
+    APIKeyRepository repository.APIKeyRepository,
     Logger *logrus.Logger,
     AI pb.SemanticServiceClient,
+    Events *events.Hub,
+    Lifecycle *lifecycle.Manager,
 ) *App {
     return &App{
-        Config: cfg,
-        Logger: Logger,
-        AI:     AI,
+        ConfigStore: ConfigStore,
+        DB:          DB,
+        Logger:      Logger,
+        AI:          AI,
+        Collections: CollectionRepository,
+        Shares:      ChatShareRepository,
+        Users:       UserRepository,
+        Audit:       AuditRepository,
+        APIKeys:     APIKeyRepository,
+        Events:      Events,
+        Lifecycle:   Lifecycle,
     }
 }
+
+// Config returns the current config; read it per request so reloaded
+// settings take effect.
+func (a *App) Config() *config.Config {
+    return a.ConfigStore.Config()
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/domain"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxClaimsDepth bounds nesting of the SSO response.
+	maxClaimsDepth = 16
+	// maxUnixTime is 9999-12-31T23:59:59Z
+	maxUnixTime = 253402300799
+)
+
+// Claims are the identity fields read from the SSO validate response.
+type Claims struct {
+	UserID    int64
+	Roles     []domain.Role
+	ExpiresAt time.Time
+}
+
+// ParseClaims reads the claims at the paths configured in mapping from the
+// JSON object body. Nothing is guessed: the user id must be a positive
+// integer at its exact path, roles a string or a list of strings (unknown
+// names are ignored) and the expiry a Unix time or an RFC 3339 string.
+// Duplicate keys are rejected, so a field cannot be shadowed.
+func ParseClaims(body []byte, mapping config.SSOClaimsConfig) (*Claims, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	root, err := decodeValue(dec, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: data after the top-level value")
+	}
+	if _, ok := root.(map[string]interface{}); !ok {
+		return nil, errors.New("response is not a JSON object")
+	}
+
+	var claims Claims
+	raw, ok := lookup(root, mapping.UserID)
+	if !ok {
+		return nil, fmt.Errorf("claim %q is missing", mapping.UserID)
+	}
+	if claims.UserID, err = parseUserID(raw); err != nil {
+		return nil, fmt.Errorf("claim %q: %w", mapping.UserID, err)
+	}
+	if mapping.Roles != "" {
+		if raw, ok := lookup(root, mapping.Roles); ok {
+			if claims.Roles, err = parseRoles(raw); err != nil {
+				return nil, fmt.Errorf("claim %q: %w", mapping.Roles, err)
+			}
+		}
+	}
+	if mapping.ExpiresAt != "" {
+		if raw, ok := lookup(root, mapping.ExpiresAt); ok {
+			if claims.ExpiresAt, err = parseTime(raw); err != nil {
+				return nil, fmt.Errorf("claim %q: %w", mapping.ExpiresAt, err)
+			}
+		}
+	}
+	return &claims, nil
+}
+
+// lookup follows a dot-separated path through nested objects.
+func lookup(v interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+func parseUserID(v interface{}) (int64, error) {
+	var s string
+	switch t := v.(type) {
+	case json.Number:
+		s = string(t)
+	case string:
+		s = t
+	default:
+		return 0, errors.New("must be an integer or a string of digits")
+	}
+	if s == "" || len(s) > 19 || strings.Trim(s, "0123456789") != "" {
+		return 0, fmt.Errorf("%q is not a positive integer", s)
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%q is not a positive integer", s)
+	}
+	return id, nil
+}
+
+func parseRoles(v interface{}) ([]domain.Role, error) {
+	var names []string
+	switch t := v.(type) {
+	case string:
+		names = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return nil, errors.New("must be a string or a list of strings")
+			}
+			names = append(names, s)
+		}
+	default:
+		return nil, errors.New("must be a string or a list of strings")
+	}
+	var roles []domain.Role
+	for _, name := range names {
+		if r, ok := domain.ParseRole(name); ok {
+			roles = append(roles, r)
+		}
+	}
+	return roles, nil
+}
+
+func parseTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case json.Number:
+		sec, err := t.Float64()
+		if err != nil || sec <= 0 || math.IsInf(sec, 0) || sec > maxUnixTime {
+			return time.Time{}, fmt.Errorf("%s is not a Unix time", t)
+		}
+		whole, frac := math.Modf(sec)
+		return time.Unix(int64(whole), int64(frac*1e9)), nil
+	case string:
+		ts, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time", t)
+		}
+		return ts, nil
+	default:
+		return time.Time{}, errors.New("must be a Unix time or an RFC 3339 string")
+	}
+}
+
+// decodeValue decodes the next JSON value like encoding/json into
+// interface{}, but fails on duplicate object keys and deep nesting.
+func decodeValue(dec *json.Decoder, depth int) (interface{}, error) {
+	if depth > maxClaimsDepth {
+		return nil, errors.New("nested too deeply")
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := make(map[string]interface{})
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				if _, dup := obj[key]; dup {
+					return nil, fmt.Errorf("duplicate key %q", key)
+				}
+				if obj[key], err = decodeValue(dec, depth+1); err != nil {
+					return nil, err
+				}
+			}
+			_, err := dec.Token()
+			return obj, err
+		case '[':
+			list := make([]interface{}, 0)
+			for dec.More() {
+				item, err := decodeValue(dec, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			_, err := dec.Token()
+			return list, err
+		}
+		return nil, fmt.Errorf("unexpected %s", t)
+	default:
+		return t, nil
+	}
+}
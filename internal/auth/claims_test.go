@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+var defaultMapping = config.SSOClaimsConfig{UserID: "user_id", Roles: "roles", ExpiresAt: "exp"}
+
+func TestParseClaims(t *testing.T) {
+	claims, err := ParseClaims([]byte(`{"user_id": 42, "roles": ["admin", "unknown"], "exp": 1893456000}`), defaultMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != 42 || len(claims.Roles) != 1 || claims.Roles[0] != domain.RoleAdmin || !claims.ExpiresAt.Equal(time.Unix(1893456000, 0)) {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	nested := config.SSOClaimsConfig{UserID: "user.id", Roles: "user.role", ExpiresAt: "token.expires_at"}
+	claims, err = ParseClaims([]byte(`{"user": {"id": "7", "role": "curator"}, "token": {"expires_at": "2030-01-01T00:00:00Z"}}`), nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != 7 || len(claims.Roles) != 1 || claims.Roles[0] != domain.RoleCurator || claims.ExpiresAt.Year() != 2030 {
+		t.Fatalf("claims = %+v", claims)
+	}
+}
+
+func TestParseClaimsRejectsMaliciousPayloads(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"duplicate user id", `{"user_id": 1, "user_id": 2}`, "duplicate key"},
+		{"duplicate user id, null first", `{"user_id": null, "user_id": 2}`, "duplicate key"},
+		{"duplicate roles", `{"user_id": 1, "roles": "user", "roles": "admin"}`, "duplicate key"},
+		{"duplicate nested key", `{"user_id": 1, "x": {"a": 1, "a": 2}}`, "duplicate key"},
+		{"deep nesting", `{"user_id": 1, "x": ` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `}`, "nested too deeply"},
+		{"deep objects", strings.Repeat(`{"a":`, 40) + "1" + strings.Repeat("}", 40), "nested too deeply"},
+		{"negative user id", `{"user_id": -1}`, "not a positive integer"},
+		{"zero user id", `{"user_id": 0}`, "not a positive integer"},
+		{"fractional user id", `{"user_id": 1.5}`, "not a positive integer"},
+		{"exponent user id", `{"user_id": 1e3}`, "not a positive integer"},
+		{"signed string user id", `{"user_id": "+5"}`, "not a positive integer"},
+		{"padded string user id", `{"user_id": " 5"}`, "not a positive integer"},
+		{"empty string user id", `{"user_id": ""}`, "not a positive integer"},
+		{"boolean user id", `{"user_id": true}`, "must be an integer"},
+		{"object user id", `{"user_id": {"id": 1}}`, "must be an integer"},
+		{"null user id", `{"user_id": null}`, "is missing"},
+		{"missing user id", `{"id": 1}`, "is missing"},
+		{"overlong user id", `{"user_id": 99999999999999999999}`, "not a positive integer"},
+		{"user id above int64", `{"user_id": "9223372036854775808"}`, "not a positive integer"},
+		{"huge user id", `{"user_id": ` + strings.Repeat("9", 5000) + `}`, "not a positive integer"},
+		{"overlong expiry", `{"user_id": 1, "exp": 1` + strings.Repeat("0", 400) + `}`, "not a Unix time"},
+		{"negative expiry", `{"user_id": 1, "exp": -1}`, "not a Unix time"},
+		{"roles not strings", `{"user_id": 1, "roles": [1, 2]}`, "list of strings"},
+		{"trailing data", `{"user_id": 1} {"user_id": 2}`, "data after the top-level value"},
+		{"not an object", `[{"user_id": 1}]`, "not a JSON object"},
+		{"invalid JSON", `{"user_id": 1`, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseClaims([]byte(tt.body), defaultMapping)
+			if err == nil {
+				t.Fatalf("ParseClaims() = %+v, want an error", claims)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ParseClaims() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClaimsMaxUserID(t *testing.T) {
+	claims, err := ParseClaims([]byte(`{"user_id": 9223372036854775807}`), defaultMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != 9223372036854775807 {
+		t.Fatalf("UserID = %d", claims.UserID)
+	}
+}
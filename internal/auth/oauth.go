@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/pkg/securetoken"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CallbackPath is where the SSO sends the browser back, relative to PUBLIC_URL.
+const CallbackPath = "/api/sso/callback"
+
+const maxTokenResponseSize = 64 << 10
+
+// Tokens is the response of the SSO token endpoint.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	// Lifetime of the access token in seconds, 0 if unknown
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// OAuthError is an error response of the token endpoint (RFC 6749 5.2).
+type OAuthError struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return e.Code + ": " + e.Description
+	}
+	return e.Code
+}
+
+// NewPKCE returns a code verifier and its S256 challenge (RFC 7636).
+func NewPKCE() (verifier, challenge string, err error) {
+	verifier, err = securetoken.Generate(securetoken.DefaultSize)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// OAuthClient runs the authorization code flow against the SSO.
+type OAuthClient struct {
+	ClientID     string
+	ClientSecret string
+	AuthorizeURL string
+	TokenURL     string
+	RevokeURL    string
+	RedirectURL  string
+	Scopes       string
+	HTTP         *http.Client
+}
+
+// NewOAuthClient builds the client from the current config. Endpoints not
+// set explicitly are derived from SSO_HTTP_URL.
+func NewOAuthClient(cfg *config.Config) *OAuthClient {
+	oauth := cfg.SSOOAuthConfig
+	base := strings.TrimRight(cfg.SSO_HTTP_URL, "/")
+	endpoint := func(explicit, path string) string {
+		if explicit != "" {
+			return explicit
+		}
+		return base + path
+	}
+	timeout := 5 * time.Second
+	if cfg.GRPCTimeout > 0 {
+		timeout = cfg.GRPCTimeout
+	}
+	return &OAuthClient{
+		ClientID:     oauth.ClientID,
+		ClientSecret: oauth.ClientSecret,
+		AuthorizeURL: endpoint(oauth.AuthorizeURL, "/oauth/authorize"),
+		TokenURL:     endpoint(oauth.TokenURL, "/oauth/token"),
+		RevokeURL:    endpoint(oauth.RevokeURL, "/oauth/revoke"),
+		RedirectURL:  strings.TrimRight(cfg.PublicURL, "/") + CallbackPath,
+		Scopes:       oauth.Scopes,
+		HTTP:         &http.Client{Timeout: timeout},
+	}
+}
+
+// AuthCodeURL returns the SSO login page the browser is sent to.
+func (c *OAuthClient) AuthCodeURL(state, challenge string) string {
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.ClientID},
+		"redirect_uri":          {c.RedirectURL},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	if c.Scopes != "" {
+		q.Set("scope", c.Scopes)
+	}
+	sep := "?"
+	if strings.Contains(c.AuthorizeURL, "?") {
+		sep = "&"
+	}
+	return c.AuthorizeURL + sep + q.Encode()
+}
+
+// Exchange trades an authorization code for tokens.
+func (c *OAuthClient) Exchange(ctx context.Context, code, verifier string) (*Tokens, error) {
+	return c.token(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.RedirectURL},
+		"code_verifier": {verifier},
+	})
+}
+
+// Refresh obtains new tokens with a refresh token. The SSO may rotate the
+// refresh token; if it does not, the old one is returned again.
+func (c *OAuthClient) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	tokens, err := c.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tokens.RefreshToken == "" {
+		tokens.RefreshToken = refreshToken
+	}
+	return tokens, nil
+}
+
+// Revoke invalidates a refresh token (RFC 7009).
+func (c *OAuthClient) Revoke(ctx context.Context, refreshToken string) error {
+	resp, err := c.post(ctx, c.RevokeURL, url.Values{
+		"token":           {refreshToken},
+		"token_type_hint": {"refresh_token"},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("revoke: SSO returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *OAuthClient) token(ctx context.Context, form url.Values) (*Tokens, error) {
+	resp, err := c.post(ctx, c.TokenURL, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxTokenResponseSize {
+		return nil, errors.New("token response is too large")
+	}
+	if resp.StatusCode != http.StatusOK {
+		oauthErr := &OAuthError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(body, oauthErr) != nil || oauthErr.Code == "" {
+			oauthErr.Code = "server_error"
+			oauthErr.Description = "SSO returned " + resp.Status
+		}
+		return nil, oauthErr
+	}
+	var tokens Tokens
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if tokens.AccessToken == "" {
+		return nil, errors.New("invalid token response: access_token is missing")
+	}
+	if tokens.TokenType != "" && !strings.EqualFold(tokens.TokenType, "bearer") {
+		return nil, fmt.Errorf("invalid token response: unsupported token_type %q", tokens.TokenType)
+	}
+	if tokens.ExpiresIn < 0 {
+		tokens.ExpiresIn = 0
+	}
+	return &tokens, nil
+}
+
+func (c *OAuthClient) post(ctx context.Context, target string, form url.Values) (*http.Response, error) {
+	if c.ClientSecret == "" {
+		form.Set("client_id", c.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+	return c.HTTP.Do(req)
+}
@@ -0,0 +1,75 @@
+// Package auth describes the authenticated caller of a request.
+package auth
+
+import (
+	"VKR_gateway_service/internal/domain"
+	"context"
+	"time"
+)
+
+// Method is how a principal was authenticated.
+type Method string
+
+const (
+	MethodBearer Method = "bearer"
+	MethodAPIKey Method = "api_key"
+	// A bearer token sent by the browser in the session cookie
+	MethodSession Method = "session"
+)
+
+// Principal is the authenticated caller. It is built only from the SSO
+// validate response or from a stored API key, never from client input.
+type Principal struct {
+	UserID int64
+	Roles  []domain.Role
+	// Scopes of the API key; nil for bearer tokens, which carry every scope
+	Scopes []domain.Scope
+	// When the token or key stops being valid, zero if unknown
+	ExpiresAt time.Time
+	Method    Method
+	// Set for MethodAPIKey
+	APIKeyID int64
+}
+
+// HasRole reports whether any role of the principal includes role.
+func (p *Principal) HasRole(role domain.Role) bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range p.Roles {
+		if r.Includes(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the principal may use scope. Bearer tokens act
+// with the full rights of the user, API keys only with their scopes.
+func (p *Principal) HasScope(scope domain.Scope) bool {
+	if p == nil {
+		return false
+	}
+	if p.Method != MethodAPIKey {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by WithPrincipal.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
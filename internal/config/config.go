@@ -1,46 +1,310 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+// Config is read from the environment and, when CONFIG_FILE is set, from a
+// YAML or TOML file. Environment variables take precedence over the file.
+// Fields tagged reload:"true" are applied on reload without a restart.
+// Fields tagged secret:"true" may also be read from NAME_FILE and secret
+// providers, are refreshed at runtime and are redacted when shown.
 type Config struct {
-	PostgresConfig      PostgresConfig
-	HttpServerConfig    HTTPServerConfig
-	Domain              string   `env:"DOMAIN" env-default:"localhost"`
-	PublicURL           string   `env:"PUBLIC_URL"`
-	AllowedCORSOrigins  []string `env:"ALLOWED_CORS_ORIGINS" env-separator:","`
-	AllowedRedirectURLs []string `env:"ALLOWED_REDIRECT_URLS" env-separator:","`
-	SwaggerEnabled      bool     `env:"SWAGGER_ENABLED" env-default:"true"`
-	SwaggerUser         string   `env:"SWAGGER_USER"`
-	SwaggerPassword     string   `env:"SWAGGER_PASSWORD"`
+	PostgresConfig      PostgresConfig   `yaml:"postgres" toml:"postgres"`
+	RedisConfig         RedisConfig      `yaml:"redis" toml:"redis"`
+	HttpServerConfig    HTTPServerConfig `yaml:"http" toml:"http"`
+	TLSConfig           TLSConfig        `yaml:"tls" toml:"tls"`
+	LifecycleConfig     LifecycleConfig  `yaml:"lifecycle" toml:"lifecycle"`
+	WebSocketConfig     WebSocketConfig  `yaml:"websocket" toml:"websocket" reload:"true"`
+	GraphQLConfig       GraphQLConfig    `yaml:"graphql" toml:"graphql" reload:"true"`
+	PaperGraphConfig    PaperGraphConfig `yaml:"paper_graph" toml:"paper_graph" reload:"true"`
+	APIVersionConfig    APIVersionConfig `yaml:"api_versions" toml:"api_versions"`
+	SecretsConfig       SecretsConfig    `yaml:"secrets" toml:"secrets"`
+	Domain              string           `yaml:"domain" toml:"domain" env:"DOMAIN" env-default:"localhost"`
+	PublicURL           string           `yaml:"public_url" toml:"public_url" env:"PUBLIC_URL"`
+	AllowedCORSOrigins  []string         `yaml:"allowed_cors_origins" toml:"allowed_cors_origins" env:"ALLOWED_CORS_ORIGINS" env-separator:"," reload:"true"`
+	CORSConfig          CORSConfig       `yaml:"cors" toml:"cors" reload:"true"`
+	AllowedRedirectURLs []string         `yaml:"allowed_redirect_urls" toml:"allowed_redirect_urls" env:"ALLOWED_REDIRECT_URLS" env-separator:"," reload:"true"`
+	SwaggerEnabled      bool             `yaml:"swagger_enabled" toml:"swagger_enabled" env:"SWAGGER_ENABLED" env-default:"true"`
+	// Serve SemanticService over Connect, gRPC-Web and gRPC on the HTTP port
+	PublicRPCEnabled bool   `yaml:"public_rpc_enabled" toml:"public_rpc_enabled" env:"PUBLIC_RPC_ENABLED" env-default:"true"`
+	SwaggerUser      string `yaml:"swagger_user" toml:"swagger_user" env:"SWAGGER_USER"`
+	SwaggerPassword  string `yaml:"swagger_password" toml:"swagger_password" env:"SWAGGER_PASSWORD" secret:"true"`
 	// Address of external AI gRPC service (host:port)
-	AIServiceAddress string `env:"AI_GRPC_ADDR" env-default:"localhost:5104"`
+	AIServiceAddress string `yaml:"ai_grpc_addr" toml:"ai_grpc_addr" env:"AI_GRPC_ADDR" env-default:"localhost:5104"`
 	// Default timeout for gRPC dials/requests
-	GRPCTimeout  time.Duration `env:"GRPC_TIMEOUT" env-default:"5s"`
-	SSO_HTTP_URL string        `env:"SSO_HTTP_URL"`
+	GRPCTimeout  time.Duration `yaml:"grpc_timeout" toml:"grpc_timeout" env:"GRPC_TIMEOUT" env-default:"5s" reload:"true"`
+	SSO_HTTP_URL string        `yaml:"sso_http_url" toml:"sso_http_url" env:"SSO_HTTP_URL" reload:"true"`
+	// Where the identity is found in the SSO validate response
+	SSOClaimsConfig SSOClaimsConfig `yaml:"sso_claims" toml:"sso_claims" reload:"true"`
+	// Browser login via the SSO authorization endpoint, disabled without a client id
+	SSOOAuthConfig SSOOAuthConfig `yaml:"sso_oauth" toml:"sso_oauth" reload:"true"`
+	// Lifetime of chat share links
+	ShareDefaultTTL time.Duration `yaml:"share_default_ttl" toml:"share_default_ttl" env:"SHARE_DEFAULT_TTL" env-default:"168h" reload:"true"`
+	ShareMaxTTL     time.Duration `yaml:"share_max_ttl" toml:"share_max_ttl" env:"SHARE_MAX_TTL" env-default:"720h" reload:"true"`
+	// Lifetime of API keys created without an explicit one, and the longest allowed
+	APIKeyDefaultTTL time.Duration `yaml:"api_key_default_ttl" toml:"api_key_default_ttl" env:"API_KEY_DEFAULT_TTL" env-default:"2160h" reload:"true"`
+	APIKeyMaxTTL     time.Duration `yaml:"api_key_max_ttl" toml:"api_key_max_ttl" env:"API_KEY_MAX_TTL" env-default:"8760h" reload:"true"`
+	// Users always treated as admins, used to bootstrap the role table
+	AdminUserIDs []int64 `yaml:"admin_user_ids" toml:"admin_user_ids" env:"ADMIN_USER_IDS" env-separator:"," reload:"true"`
+	// How often the config file is checked for changes, 0 disables polling
+	// (SIGHUP still reloads it)
+	ConfigWatchInterval time.Duration `yaml:"config_watch_interval" toml:"config_watch_interval" env:"CONFIG_WATCH_INTERVAL" env-default:"10s"`
+	// "development" reports some config problems as warnings instead of
+	// refusing to start, see Check
+	Environment string `yaml:"environment" toml:"environment" env:"APP_ENV" env-default:"production"`
 }
 
 type PostgresConfig struct {
-	Host     string `env:"DB_HOST" env-required:"true"`
-	Port     int    `env:"DB_PORT" env-required:"true"`
-	User     string `env:"DB_USER" env-required:"true"`
-	Password string `env:"DB_PASSWORD" env-required:"true"`
-	DBName   string `env:"DB_NAME" env-required:"true"`
-	SSLMode  string `env:"DB_SSL" env-default:"disable"`
+	Host     string `yaml:"host" toml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" toml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" toml:"user" env:"DB_USER"`
+	Password string `yaml:"password" toml:"password" env:"DB_PASSWORD" secret:"true"`
+	DBName   string `yaml:"name" toml:"name" env:"DB_NAME"`
+	SSLMode  string `yaml:"ssl_mode" toml:"ssl_mode" env:"DB_SSL" env-default:"disable"`
+	// Optional read replica with the same credentials and database, used
+	// for read-only queries that tolerate replication lag
+	ReplicaHost string `yaml:"replica_host" toml:"replica_host" env:"DB_REPLICA_HOST"`
+	// 0 means the primary port
+	ReplicaPort int `yaml:"replica_port" toml:"replica_port" env:"DB_REPLICA_PORT" env-default:"0"`
+	// Pool settings, see pgxpool.Config
+	MinConns              int           `yaml:"min_conns" toml:"min_conns" env:"DB_MIN_CONNS" env-default:"0"`
+	MaxConns              int           `yaml:"max_conns" toml:"max_conns" env:"DB_MAX_CONNS" env-default:"10"`
+	MaxConnLifetime       time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME" env-default:"1h"`
+	MaxConnLifetimeJitter time.Duration `yaml:"max_conn_lifetime_jitter" toml:"max_conn_lifetime_jitter" env:"DB_MAX_CONN_LIFETIME_JITTER" env-default:"5m"`
+	MaxConnIdleTime       time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME" env-default:"30m"`
+	HealthCheckPeriod     time.Duration `yaml:"health_check_period" toml:"health_check_period" env:"DB_HEALTH_CHECK_PERIOD" env-default:"1m"`
+	// Timeout of a single connection attempt
+	ConnectTimeout time.Duration `yaml:"connect_timeout" toml:"connect_timeout" env:"DB_CONNECT_TIMEOUT" env-default:"5s"`
+	// Server side statement_timeout, 0 disables it
+	StatementTimeout time.Duration `yaml:"statement_timeout" toml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" env-default:"0s"`
+	ApplicationName  string        `yaml:"application_name" toml:"application_name" env:"DB_APPLICATION_NAME" env-default:"vkr-gateway"`
+	// Startup retries with exponential backoff until Postgres is reachable
+	StartupTimeout  time.Duration `yaml:"startup_timeout" toml:"startup_timeout" env:"DB_STARTUP_TIMEOUT" env-default:"60s"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" toml:"retry_backoff" env:"DB_RETRY_BACKOFF" env-default:"500ms"`
+	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff" toml:"retry_max_backoff" env:"DB_RETRY_MAX_BACKOFF" env-default:"5s"`
+	// Apply embedded migrations at startup; replicas take turns under an
+	// advisory lock
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate" env:"DB_AUTO_MIGRATE" env-default:"false"`
+}
+
+// RedisConfig is used for pub/sub between gateway replicas. Without a
+// host events are delivered only to sockets of the same replica.
+type RedisConfig struct {
+	Host     string `yaml:"host" toml:"host" env:"REDIS_HOST"`
+	Port     int    `yaml:"port" toml:"port" env:"REDIS_PORT" env-default:"6379"`
+	Password string `yaml:"password" toml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB       int    `yaml:"db" toml:"db" env:"REDIS_DB" env-default:"0"`
+}
+
+// SSOClaimsConfig maps fields of the SSO validate response body to the
+// principal. Paths are dot-separated keys of nested objects, e.g. "user.id".
+type SSOClaimsConfig struct {
+	// Positive integer or string of digits, required in every response
+	UserID string `yaml:"user_id" toml:"user_id" env:"SSO_CLAIM_USER_ID" env-default:"user_id"`
+	// String or list of strings; empty disables SSO roles
+	Roles string `yaml:"roles" toml:"roles" env:"SSO_CLAIM_ROLES" env-default:"roles"`
+	// Unix time or RFC 3339 string; empty disables the expiry check
+	ExpiresAt string `yaml:"expires_at" toml:"expires_at" env:"SSO_CLAIM_EXPIRES_AT" env-default:"exp"`
+}
+
+// SSOOAuthConfig configures the authorization code flow with PKCE that the
+// gateway runs for browsers. The callback is PUBLIC_URL + "/api/sso/callback"
+// and must be registered with the SSO.
+type SSOOAuthConfig struct {
+	ClientID string `yaml:"client_id" toml:"client_id" env:"SSO_CLIENT_ID"`
+	// Empty for a public client
+	ClientSecret string `yaml:"client_secret" toml:"client_secret" env:"SSO_CLIENT_SECRET" secret:"true"`
+	// Default to SSO_HTTP_URL + /oauth/authorize, /oauth/token and /oauth/revoke
+	AuthorizeURL string `yaml:"authorize_url" toml:"authorize_url" env:"SSO_AUTHORIZE_URL"`
+	TokenURL     string `yaml:"token_url" toml:"token_url" env:"SSO_TOKEN_URL"`
+	RevokeURL    string `yaml:"revoke_url" toml:"revoke_url" env:"SSO_REVOKE_URL"`
+	// Space-separated
+	Scopes string `yaml:"scopes" toml:"scopes" env:"SSO_SCOPES" env-default:"openid profile"`
+	// Empty means host-only cookies
+	CookieDomain string `yaml:"cookie_domain" toml:"cookie_domain" env:"SSO_COOKIE_DOMAIN"`
+	// Disable only for local development over plain HTTP
+	CookieSecure bool `yaml:"cookie_secure" toml:"cookie_secure" env:"SSO_COOKIE_SECURE" env-default:"true"`
+	// Lifetime of the refresh token cookie
+	RefreshTTL time.Duration `yaml:"refresh_ttl" toml:"refresh_ttl" env:"SSO_REFRESH_TTL" env-default:"720h"`
+	// Time allowed to complete the login at the SSO
+	LoginTimeout time.Duration `yaml:"login_timeout" toml:"login_timeout" env:"SSO_LOGIN_TIMEOUT" env-default:"10m"`
+}
+
+// Enabled reports whether browser login is configured.
+func (c SSOOAuthConfig) Enabled() bool {
+	return c.ClientID != ""
+}
+
+// LifecycleConfig bounds startup and shutdown of the process.
+type LifecycleConfig struct {
+	// Time to connect to Postgres, Redis and the AI service
+	StartTimeout time.Duration `yaml:"start_timeout" toml:"start_timeout" env:"START_TIMEOUT" env-default:"90s"`
+	// Time to drain requests and streams and release connections after SIGTERM
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-default:"30s"`
+	// Time readiness fails before draining starts, so load balancers stop
+	// routing new requests here
+	DrainDelay time.Duration `yaml:"drain_delay" toml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" env-default:"5s"`
+}
+
+type WebSocketConfig struct {
+	PingInterval time.Duration `yaml:"ping_interval" toml:"ping_interval" env:"WS_PING_INTERVAL" env-default:"30s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"WS_WRITE_TIMEOUT" env-default:"10s"`
+	// Events queued per connection; a client that falls further behind is disconnected
+	SendBuffer int `yaml:"send_buffer" toml:"send_buffer" env:"WS_SEND_BUFFER" env-default:"64"`
+	// Open sockets per user, 0 means unlimited
+	MaxConnectionsPerUser int `yaml:"max_connections_per_user" toml:"max_connections_per_user" env:"WS_MAX_CONNECTIONS_PER_USER" env-default:"10"`
+}
+
+type GraphQLConfig struct {
+	MaxDepth int `yaml:"max_depth" toml:"max_depth" env:"GRAPHQL_MAX_DEPTH" env-default:"8"`
+	// Every field costs 1, fields calling the AI service cost 10; fields under a
+	// list count once for each of 10 assumed items
+	MaxComplexity int `yaml:"max_complexity" toml:"max_complexity" env:"GRAPHQL_MAX_COMPLEXITY" env-default:"5000"`
+}
+
+// PaperGraphConfig bounds the citation graph built by GET /api/papers/{id}/graph.
+type PaperGraphConfig struct {
+	MaxDepth int `yaml:"max_depth" toml:"max_depth" env:"PAPER_GRAPH_MAX_DEPTH" env-default:"3"`
+	// Nodes are added level by level until the limit, the rest is cut off
+	MaxNodes int `yaml:"max_nodes" toml:"max_nodes" env:"PAPER_GRAPH_MAX_NODES" env-default:"500"`
+}
+
+// APIVersionConfig announces the retirement of /api/v1 (and the unversioned
+// /api alias) through the Deprecation and Sunset response headers.
+type APIVersionConfig struct {
+	V1DeprecatedAt time.Time `yaml:"v1_deprecated_at" toml:"v1_deprecated_at" env:"API_V1_DEPRECATED_AT" env-layout:"2006-01-02" env-default:"2026-11-01"`
+	V1SunsetAt     time.Time `yaml:"v1_sunset_at" toml:"v1_sunset_at" env:"API_V1_SUNSET_AT" env-layout:"2006-01-02" env-default:"2027-05-01"`
+}
+
+// SecretsConfig configures secret providers queried in addition to env,
+// NAME_FILE variables and the config file, see internal/secrets.
+type SecretsConfig struct {
+	// How often secrets are read again, 0 disables refresh
+	RefreshInterval time.Duration `yaml:"refresh_interval" toml:"refresh_interval" env:"SECRETS_REFRESH_INTERVAL" env-default:"5m"`
+	Vault           VaultConfig   `yaml:"vault" toml:"vault"`
+}
+
+// VaultConfig points at a HashiCorp Vault KV v2 secret whose keys are the
+// env variable names of secret settings, e.g. DB_PASSWORD. Vault is not
+// used without an address.
+type VaultConfig struct {
+	Addr      string `yaml:"addr" toml:"addr" env:"VAULT_ADDR"`
+	Token     string `yaml:"token" toml:"token" env:"VAULT_TOKEN" secret:"true"`
+	Namespace string `yaml:"namespace" toml:"namespace" env:"VAULT_NAMESPACE"`
+	Mount     string `yaml:"mount" toml:"mount" env:"VAULT_KV_MOUNT" env-default:"secret"`
+	Path      string `yaml:"path" toml:"path" env:"VAULT_SECRET_PATH"`
 }
 
 type HTTPServerConfig struct {
-	Port string `env:"HTTP_PORT" env-default:"8080"`
+	Port string `yaml:"port" toml:"port" env:"HTTP_PORT" env-default:"8080"`
+	// Server timeouts; WebSockets and exports are exempt from read and write
+	// timeouts. 0 disables read, write and idle timeouts.
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" env-default:"10s"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"HTTP_READ_TIMEOUT" env-default:"30s"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" env-default:"60s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"120s"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" toml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES" env-default:"65536"`
+	// Request body limits in bytes; uploads are the paper import
+	MaxBodySize   int64 `yaml:"max_body_size" toml:"max_body_size" env:"HTTP_MAX_BODY_SIZE" env-default:"1048576" reload:"true"`
+	MaxUploadSize int64 `yaml:"max_upload_size" toml:"max_upload_size" env:"HTTP_MAX_UPLOAD_SIZE" env-default:"10485760" reload:"true"`
+	// IPs or CIDRs of proxies whose X-Forwarded-For and X-Real-IP are
+	// trusted for the client IP; empty trusts none
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES" env-separator:","`
+	// Sent with HTTPS responses, 0 disables Strict-Transport-Security
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age" toml:"hsts_max_age" env:"HTTP_HSTS_MAX_AGE" env-default:"8760h" reload:"true"`
+	ContentSecurityPolicy string        `yaml:"content_security_policy" toml:"content_security_policy" env:"HTTP_CONTENT_SECURITY_POLICY" env-default:"default-src 'none'; frame-ancestors 'none'" reload:"true"`
+	ReferrerPolicy        string        `yaml:"referrer_policy" toml:"referrer_policy" env:"HTTP_REFERRER_POLICY" env-default:"no-referrer" reload:"true"`
 }
 
-func MustLoadConfig() (*Config, error) {
-	var config Config
-	if err := cleanenv.ReadEnv(&config); err != nil {
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Development reports whether the gateway runs in the development environment.
+func (c *Config) Development() bool {
+	return c.Environment == EnvDevelopment
+}
+
+// CORSConfig completes ALLOWED_CORS_ORIGINS, the policy of the
+// authenticated API, with the policy of public share links. Origins may be
+// patterns like https://*.example.com matching any subdomain.
+type CORSConfig struct {
+	// Origins allowed to read share links, without credentials; "*" allows any
+	SharedOrigins []string `yaml:"shared_origins" toml:"shared_origins" env:"CORS_SHARED_ORIGINS" env-separator:"," env-default:"*"`
+	// Response headers readable by browser scripts
+	ExposedHeaders []string `yaml:"exposed_headers" toml:"exposed_headers" env:"CORS_EXPOSED_HEADERS" env-separator:"," env-default:"Deprecation,Sunset,Link,X-Request-Id"`
+	// How long browsers may cache a preflight response
+	MaxAge time.Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE" env-default:"10m"`
+}
+
+// TLSConfig enables HTTPS on HTTP_PORT with a certificate from files or
+// from an ACME CA. Without either the server speaks plain HTTP (and h2c).
+type TLSConfig struct {
+	// PEM files, reread when they change
+	CertFile       string        `yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile        string        `yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE"`
+	ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval" env:"TLS_RELOAD_INTERVAL" env-default:"1m"`
+	// Host names to obtain certificates for, enables ACME
+	ACMEDomains      []string `yaml:"acme_domains" toml:"acme_domains" env:"TLS_ACME_DOMAINS" env-separator:","`
+	ACMEDirectoryURL string   `yaml:"acme_directory_url" toml:"acme_directory_url" env:"TLS_ACME_DIRECTORY_URL" env-default:"https://acme-v02.api.letsencrypt.org/directory"`
+	ACMEEmail        string   `yaml:"acme_email" toml:"acme_email" env:"TLS_ACME_EMAIL"`
+	ACMECacheDir     string   `yaml:"acme_cache_dir" toml:"acme_cache_dir" env:"TLS_ACME_CACHE_DIR" env-default:"acme-cache"`
+	// PEM roots trusted for the ACME directory, e.g. of a test CA
+	ACMECAFile string `yaml:"acme_ca_file" toml:"acme_ca_file" env:"TLS_ACME_CA_FILE"`
+	// Plain HTTP port redirecting to HTTPS and answering ACME HTTP-01
+	// challenges, empty disables it
+	RedirectPort string `yaml:"redirect_port" toml:"redirect_port" env:"TLS_REDIRECT_PORT"`
+	// PEM CAs of client certificates; when set /api/admin requires one
+	ClientCAFile string `yaml:"client_ca_file" toml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+}
+
+// Enabled reports whether the server terminates TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.ACMEEnabled()
+}
+
+func (c TLSConfig) ACMEEnabled() bool {
+	return len(c.ACMEDomains) > 0
+}
+
+// Load reads the config like Read and validates it.
+func Load(path string) (*Config, error) {
+	config, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Read reads defaults, the optional file at path, the environment and
+// NAME_FILE variables of secret settings, in increasing order of
+// precedence. Callers adding secrets from providers validate afterwards.
+func Read(path string) (*Config, error) {
+	var env Config
+	if err := cleanenv.ReadEnv(&env); err != nil {
+		return nil, err
+	}
+	config := env
+	if path != "" {
+		if err := parseFile(path, &config); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+		overrideFromEnv(&config, &env)
+	}
+	files, err := ReadSecretFiles()
+	if err != nil {
 		return nil, err
 	}
+	ApplySecrets(&config, files)
 
 	return &config, nil
 }
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// parseFile decodes a YAML or TOML file, chosen by extension, over cfg.
+// Unknown keys are rejected so typos do not silently fall back to defaults.
+func parseFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return decode(path, data, cfg)
+}
+
+func decode(path string, data []byte, cfg *Config) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	case ".toml":
+		md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(cfg)
+		if err != nil {
+			return err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, 0, len(undecoded))
+			for _, key := range undecoded {
+				keys = append(keys, key.String())
+			}
+			return fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+		}
+	default:
+		return fmt.Errorf("unsupported config file format %q, use .yaml, .yml or .toml", ext)
+	}
+	return nil
+}
+
+// overrideFromEnv copies from env every field whose environment variable
+// is set to a non-empty value, so the environment wins over the file.
+// Empty variables, as passed by docker compose for unset ones, are ignored.
+func overrideFromEnv(dst, env *Config) {
+	overrideStruct(reflect.ValueOf(dst).Elem(), reflect.ValueOf(env).Elem())
+}
+
+func overrideStruct(dst, env reflect.Value) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name := f.Tag.Get("env"); name != "" {
+			if os.Getenv(name) != "" {
+				dst.Field(i).Set(env.Field(i))
+			}
+			continue
+		}
+		if f.Type.Kind() == reflect.Struct {
+			overrideStruct(dst.Field(i), env.Field(i))
+		}
+	}
+}
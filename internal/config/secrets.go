@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// fileSuffix marks variables holding the path of a file with the value,
+// as used for Docker and Kubernetes secrets.
+const fileSuffix = "_FILE"
+
+// SecretKeys returns the env variable names of secret settings, which are
+// also the keys secret providers are queried for.
+func SecretKeys() []string {
+	var keys []string
+	eachSecret(reflect.ValueOf(&Config{}).Elem(), "", func(env, _ string, _ reflect.Value) {
+		keys = append(keys, env)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// ReadSecretFiles reads the file named by NAME_FILE for every secret
+// setting NAME. A trailing newline is dropped. Setting both NAME and
+// NAME_FILE is an error.
+func ReadSecretFiles() (map[string]string, error) {
+	values := map[string]string{}
+	for _, key := range SecretKeys() {
+		path := os.Getenv(key + fileSuffix)
+		if path == "" {
+			continue
+		}
+		if os.Getenv(key) != "" {
+			return nil, fmt.Errorf("both %s and %s%s are set", key, key, fileSuffix)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s%s: %w", key, fileSuffix, err)
+		}
+		values[key] = strings.TrimRight(string(data), "\r\n")
+	}
+	return values, nil
+}
+
+// ApplySecrets sets secret settings from values keyed by env variable name
+// and returns the file keys of the settings that changed.
+func ApplySecrets(cfg *Config, values map[string]string) []string {
+	var changed []string
+	eachSecret(reflect.ValueOf(cfg).Elem(), "", func(env, key string, field reflect.Value) {
+		if value, ok := values[env]; ok && field.String() != value {
+			field.SetString(value)
+			changed = append(changed, key)
+		}
+	})
+	return changed
+}
+
+func eachSecret(v reflect.Value, prefix string, fn func(env, key string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := prefix + fileKey(f)
+		if isSection(f) {
+			eachSecret(v.Field(i), key+".", fn)
+		} else if f.Tag.Get("secret") == "true" && f.Type.Kind() == reflect.String {
+			fn(f.Tag.Get("env"), key, v.Field(i))
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// Store holds the effective config and swaps it when the config file
+// changes or the process receives SIGHUP. Only fields tagged reload:"true"
+// are replaced; the rest keep their startup values until a restart.
+type Store struct {
+	path    string
+	mu      sync.Mutex // serialises reloads
+	current atomic.Pointer[snapshot]
+	digest  [sha256.Size]byte
+	// Values from secret providers, applied over every reload
+	secrets map[string]string
+}
+
+type snapshot struct {
+	config   *Config
+	loadedAt time.Time
+}
+
+// ReloadResult names, by file key, the settings a reload applied and the
+// changed settings that need a restart to take effect. Warnings are the
+// problems Check tolerates in development.
+type ReloadResult struct {
+	Applied         []string
+	RestartRequired []string
+	Warnings        []string
+}
+
+// NewStore returns a store holding cfg, loaded from the file at path (may
+// be empty) and the environment.
+func NewStore(path string, cfg *Config) *Store {
+	s := &Store{path: path}
+	s.current.Store(&snapshot{config: cfg, loadedAt: time.Now()})
+	s.digest, _ = fileDigest(path)
+	return s
+}
+
+// Config returns the current config. Callers should not keep it across
+// requests so reloaded values are picked up.
+func (s *Store) Config() *Config {
+	if s == nil {
+		return nil
+	}
+	return s.current.Load().config
+}
+
+// Path returns the config file path, empty if only the environment is used.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// LoadedAt returns when the current config was loaded or last reloaded.
+func (s *Store) LoadedAt() time.Time {
+	return s.current.Load().loadedAt
+}
+
+// Reload reads the file and environment again. An invalid config is
+// rejected as a whole and the current one is kept.
+func (s *Store) Reload() (ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result ReloadResult
+	// Remember the content even if it is invalid, so polling reports a
+	// broken file once rather than on every tick
+	s.digest, _ = fileDigest(s.path)
+	next, err := Read(s.path)
+	if err != nil {
+		return result, err
+	}
+	ApplySecrets(next, s.secrets)
+	result.Warnings, err = next.Check()
+	if err != nil {
+		return result, err
+	}
+
+	merged := *s.current.Load().config
+	compare(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(next).Elem(), "", false, &result)
+	if len(result.Applied) > 0 {
+		s.current.Store(&snapshot{config: &merged, loadedAt: time.Now()})
+	}
+	return result, nil
+}
+
+// SetSecrets applies the values read from all secret providers, keyed by
+// env variable name, to the current config and keeps them over later
+// reloads. It returns the file keys of the settings that changed.
+func (s *Store) SetSecrets(values map[string]string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.secrets = values
+	next := *s.current.Load().config
+	changed := ApplySecrets(&next, values)
+	if len(changed) > 0 {
+		s.current.Store(&snapshot{config: &next, loadedAt: time.Now()})
+	}
+	return changed
+}
+
+// Watch reloads the config on every value received from signals and, if
+// the config watch interval is positive, whenever the file content
+// changes. report is called with the outcome of every reload.
+func (s *Store) Watch(ctx context.Context, signals <-chan os.Signal, report func(ReloadResult, error)) {
+	var poll <-chan time.Time
+	if interval := s.Config().ConfigWatchInterval; s.path != "" && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		case <-poll:
+			if !s.fileChanged() {
+				continue
+			}
+		}
+		report(s.Reload())
+	}
+}
+
+func (s *Store) fileChanged() bool {
+	digest, err := fileDigest(s.path)
+	if err != nil {
+		// A file being replaced may be briefly missing; Reload reports
+		// the error if it persists
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return digest != s.digest
+}
+
+func fileDigest(path string) ([sha256.Size]byte, error) {
+	if path == "" {
+		return [sha256.Size]byte{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// compare copies reloadable fields of next into cur and records changed
+// leaf settings in result.
+func compare(cur, next reflect.Value, prefix string, reloadable bool, result *ReloadResult) {
+	t := cur.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := prefix + fileKey(f)
+		fieldReloadable := reloadable || isReloadable(f)
+		if isSection(f) {
+			compare(cur.Field(i), next.Field(i), key+".", fieldReloadable, result)
+			continue
+		}
+		if reflect.DeepEqual(cur.Field(i).Interface(), next.Field(i).Interface()) {
+			continue
+		}
+		if fieldReloadable {
+			cur.Field(i).Set(next.Field(i))
+			result.Applied = append(result.Applied, key)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, key)
+		}
+	}
+}
+
+// ReloadableKeys lists the settings applied on reload without a restart.
+func ReloadableKeys() []string {
+	var keys []string
+	var walk func(t reflect.Type, prefix string, reloadable bool)
+	walk = func(t reflect.Type, prefix string, reloadable bool) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			key := prefix + fileKey(f)
+			fieldReloadable := reloadable || isReloadable(f)
+			if isSection(f) {
+				walk(f.Type, key+".", fieldReloadable)
+			} else if fieldReloadable {
+				keys = append(keys, key)
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "", false)
+	sort.Strings(keys)
+	return keys
+}
+
+// Redacted returns the config keyed like the config file, with secrets
+// replaced by a placeholder and durations and dates formatted as in the file.
+func (c *Config) Redacted() map[string]interface{} {
+	return redactStruct(reflect.ValueOf(c).Elem())
+}
+
+func redactStruct(v reflect.Value) map[string]interface{} {
+	out := map[string]interface{}{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		value := v.Field(i)
+		switch {
+		case isSection(f):
+			out[fileKey(f)] = redactStruct(value)
+		case f.Tag.Get("secret") == "true":
+			if value.IsZero() {
+				out[fileKey(f)] = ""
+			} else {
+				out[fileKey(f)] = redacted
+			}
+		default:
+			switch x := value.Interface().(type) {
+			case time.Duration:
+				out[fileKey(f)] = x.String()
+			case time.Time:
+				out[fileKey(f)] = x.Format(f.Tag.Get("env-layout"))
+			default:
+				out[fileKey(f)] = x
+			}
+		}
+	}
+	return out
+}
+
+// isReloadable reports whether f is applied on reload. Secrets are, as
+// they are refreshed at runtime anyway.
+func isReloadable(f reflect.StructField) bool {
+	return f.Tag.Get("reload") == "true" || f.Tag.Get("secret") == "true"
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// isSection reports whether f is a nested group of settings.
+func isSection(f reflect.StructField) bool {
+	return f.Type.Kind() == reflect.Struct && f.Type != timeType
+}
+
+func fileKey(f reflect.StructField) string {
+	if key := f.Tag.Get("yaml"); key != "" {
+		return key
+	}
+	return f.Name
+}
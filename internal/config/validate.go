@@ -0,0 +1,377 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every problem found in a config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks required settings, URL formats, port ranges and
+// durations, reporting all problems at once as a *ValidationError.
+func (c *Config) Validate() error {
+	_, err := c.Check()
+	return err
+}
+
+// Check validates like Validate. In development, problems that do not
+// prevent the gateway from running, such as missing or malformed CORS
+// origins, are returned as warnings instead.
+func (c *Config) Check() (warnings []string, err error) {
+	v := &validator{lenient: c.Development()}
+	if c.Environment != EnvDevelopment && c.Environment != EnvProduction {
+		v.addf("environment (APP_ENV): must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Environment)
+	}
+	c.PostgresConfig.validate(v)
+
+	if c.RedisConfig.Host != "" {
+		v.port("redis.port (REDIS_PORT)", c.RedisConfig.Port)
+	}
+	if c.RedisConfig.DB < 0 {
+		v.addf("redis.db (REDIS_DB): must not be negative, got %d", c.RedisConfig.DB)
+	}
+
+	if p, err := strconv.Atoi(c.HttpServerConfig.Port); err != nil {
+		v.addf("http.port (HTTP_PORT): %q is not a number", c.HttpServerConfig.Port)
+	} else {
+		v.port("http.port (HTTP_PORT)", p)
+	}
+	srv := c.HttpServerConfig
+	v.positive("http.read_header_timeout (HTTP_READ_HEADER_TIMEOUT)", srv.ReadHeaderTimeout)
+	v.notNegative("http.read_timeout (HTTP_READ_TIMEOUT)", srv.ReadTimeout)
+	v.notNegative("http.write_timeout (HTTP_WRITE_TIMEOUT)", srv.WriteTimeout)
+	v.notNegative("http.idle_timeout (HTTP_IDLE_TIMEOUT)", srv.IdleTimeout)
+	v.notNegative("http.hsts_max_age (HTTP_HSTS_MAX_AGE)", srv.HSTSMaxAge)
+	if srv.ReadTimeout > 0 && srv.ReadTimeout < srv.ReadHeaderTimeout {
+		v.addf("http.read_timeout (HTTP_READ_TIMEOUT): must not be less than read_header_timeout")
+	}
+	if srv.MaxHeaderBytes <= 0 {
+		v.addf("http.max_header_bytes (HTTP_MAX_HEADER_BYTES): must be positive, got %d", srv.MaxHeaderBytes)
+	}
+	if srv.MaxBodySize <= 0 {
+		v.addf("http.max_body_size (HTTP_MAX_BODY_SIZE): must be positive, got %d", srv.MaxBodySize)
+	}
+	if srv.MaxUploadSize <= 0 {
+		v.addf("http.max_upload_size (HTTP_MAX_UPLOAD_SIZE): must be positive, got %d", srv.MaxUploadSize)
+	}
+	for _, proxy := range srv.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			v.addf("http.trusted_proxies (TRUSTED_PROXIES): %q is not an IP or CIDR", proxy)
+		}
+	}
+
+	c.TLSConfig.validate(v, c.HttpServerConfig.Port)
+
+	claims := c.SSOClaimsConfig
+	v.required("sso_claims.user_id (SSO_CLAIM_USER_ID)", claims.UserID)
+	v.claimPath("sso_claims.user_id (SSO_CLAIM_USER_ID)", claims.UserID)
+	v.claimPath("sso_claims.roles (SSO_CLAIM_ROLES)", claims.Roles)
+	v.claimPath("sso_claims.expires_at (SSO_CLAIM_EXPIRES_AT)", claims.ExpiresAt)
+
+	if oauth := c.SSOOAuthConfig; oauth.Enabled() {
+		if c.SSO_HTTP_URL == "" && (oauth.AuthorizeURL == "" || oauth.TokenURL == "") {
+			v.addf("sso_oauth.authorize_url (SSO_AUTHORIZE_URL) and sso_oauth.token_url (SSO_TOKEN_URL) are required without sso_http_url (SSO_HTTP_URL)")
+		}
+		if oauth.AuthorizeURL != "" {
+			v.url("sso_oauth.authorize_url (SSO_AUTHORIZE_URL)", oauth.AuthorizeURL)
+		}
+		if oauth.TokenURL != "" {
+			v.url("sso_oauth.token_url (SSO_TOKEN_URL)", oauth.TokenURL)
+		}
+		if oauth.RevokeURL != "" {
+			v.url("sso_oauth.revoke_url (SSO_REVOKE_URL)", oauth.RevokeURL)
+		}
+		v.required("public_url (PUBLIC_URL), needed for the SSO callback", c.PublicURL)
+		if len(c.AllowedRedirectURLs) == 0 {
+			v.addf("allowed_redirect_urls (ALLOWED_REDIRECT_URLS): at least one URL is required for SSO login")
+		}
+		v.positive("sso_oauth.refresh_ttl (SSO_REFRESH_TTL)", oauth.RefreshTTL)
+		v.positive("sso_oauth.login_timeout (SSO_LOGIN_TIMEOUT)", oauth.LoginTimeout)
+	}
+
+	lc := c.LifecycleConfig
+	v.positive("lifecycle.start_timeout (START_TIMEOUT)", lc.StartTimeout)
+	v.positive("lifecycle.shutdown_timeout (SHUTDOWN_TIMEOUT)", lc.ShutdownTimeout)
+	if lc.DrainDelay < 0 {
+		v.addf("lifecycle.drain_delay (SHUTDOWN_DRAIN_DELAY): must not be negative, got %s", lc.DrainDelay)
+	} else if lc.ShutdownTimeout > 0 && lc.DrainDelay >= lc.ShutdownTimeout {
+		v.addf("lifecycle.drain_delay (SHUTDOWN_DRAIN_DELAY): must be less than shutdown_timeout")
+	}
+
+	ws := c.WebSocketConfig
+	v.positive("websocket.ping_interval (WS_PING_INTERVAL)", ws.PingInterval)
+	v.positive("websocket.write_timeout (WS_WRITE_TIMEOUT)", ws.WriteTimeout)
+	if ws.SendBuffer <= 0 {
+		v.addf("websocket.send_buffer (WS_SEND_BUFFER): must be positive, got %d", ws.SendBuffer)
+	}
+	if ws.MaxConnectionsPerUser < 0 {
+		v.addf("websocket.max_connections_per_user (WS_MAX_CONNECTIONS_PER_USER): must not be negative, got %d", ws.MaxConnectionsPerUser)
+	}
+
+	if c.GraphQLConfig.MaxDepth <= 0 {
+		v.addf("graphql.max_depth (GRAPHQL_MAX_DEPTH): must be positive, got %d", c.GraphQLConfig.MaxDepth)
+	}
+	if c.GraphQLConfig.MaxComplexity <= 0 {
+		v.addf("graphql.max_complexity (GRAPHQL_MAX_COMPLEXITY): must be positive, got %d", c.GraphQLConfig.MaxComplexity)
+	}
+	if c.PaperGraphConfig.MaxDepth <= 0 {
+		v.addf("paper_graph.max_depth (PAPER_GRAPH_MAX_DEPTH): must be positive, got %d", c.PaperGraphConfig.MaxDepth)
+	}
+	if c.PaperGraphConfig.MaxNodes <= 0 {
+		v.addf("paper_graph.max_nodes (PAPER_GRAPH_MAX_NODES): must be positive, got %d", c.PaperGraphConfig.MaxNodes)
+	}
+
+	versions := c.APIVersionConfig
+	if !versions.V1DeprecatedAt.IsZero() && !versions.V1SunsetAt.IsZero() && versions.V1SunsetAt.Before(versions.V1DeprecatedAt) {
+		v.addf("api_versions.v1_sunset_at (API_V1_SUNSET_AT): must not be before v1_deprecated_at")
+	}
+
+	v.required("domain (DOMAIN)", c.Domain)
+	if c.PublicURL != "" {
+		v.url("public_url (PUBLIC_URL)", c.PublicURL)
+	}
+	if len(c.AllowedCORSOrigins) == 0 {
+		v.devWarnf("allowed_cors_origins (ALLOWED_CORS_ORIGINS): at least one origin is required")
+	}
+	for _, origin := range c.AllowedCORSOrigins {
+		// Credentials are allowed, so any origin would defeat the CSRF check
+		v.origin("allowed_cors_origins (ALLOWED_CORS_ORIGINS)", origin, false)
+	}
+	for _, origin := range c.CORSConfig.SharedOrigins {
+		v.origin("cors.shared_origins (CORS_SHARED_ORIGINS)", origin, true)
+	}
+	for _, header := range c.CORSConfig.ExposedHeaders {
+		if header == "" || strings.ContainsAny(header, " :,") {
+			v.addf("cors.exposed_headers (CORS_EXPOSED_HEADERS): %q is not a header name", header)
+		}
+	}
+	v.notNegative("cors.max_age (CORS_MAX_AGE)", c.CORSConfig.MaxAge)
+	for _, redirect := range c.AllowedRedirectURLs {
+		v.url("allowed_redirect_urls (ALLOWED_REDIRECT_URLS)", redirect)
+	}
+	if (c.SwaggerUser == "") != (c.SwaggerPassword == "") {
+		v.addf("swagger_user (SWAGGER_USER) and swagger_password (SWAGGER_PASSWORD) must be set together")
+	}
+
+	if host, port, err := net.SplitHostPort(c.AIServiceAddress); err != nil || host == "" {
+		v.addf("ai_grpc_addr (AI_GRPC_ADDR): %q is not host:port", c.AIServiceAddress)
+	} else if p, err := strconv.Atoi(port); err != nil {
+		v.addf("ai_grpc_addr (AI_GRPC_ADDR): %q is not a number", port)
+	} else {
+		v.port("ai_grpc_addr (AI_GRPC_ADDR)", p)
+	}
+	v.positive("grpc_timeout (GRPC_TIMEOUT)", c.GRPCTimeout)
+	if c.SSO_HTTP_URL != "" {
+		v.url("sso_http_url (SSO_HTTP_URL)", c.SSO_HTTP_URL)
+	}
+
+	v.positive("share_default_ttl (SHARE_DEFAULT_TTL)", c.ShareDefaultTTL)
+	v.positive("share_max_ttl (SHARE_MAX_TTL)", c.ShareMaxTTL)
+	if c.ShareMaxTTL > 0 && c.ShareDefaultTTL > c.ShareMaxTTL {
+		v.addf("share_default_ttl (SHARE_DEFAULT_TTL): %s exceeds share_max_ttl %s", c.ShareDefaultTTL, c.ShareMaxTTL)
+	}
+	v.positive("api_key_default_ttl (API_KEY_DEFAULT_TTL)", c.APIKeyDefaultTTL)
+	v.positive("api_key_max_ttl (API_KEY_MAX_TTL)", c.APIKeyMaxTTL)
+	if c.APIKeyMaxTTL > 0 && c.APIKeyDefaultTTL > c.APIKeyMaxTTL {
+		v.addf("api_key_default_ttl (API_KEY_DEFAULT_TTL): %s exceeds api_key_max_ttl %s", c.APIKeyDefaultTTL, c.APIKeyMaxTTL)
+	}
+	for _, id := range c.AdminUserIDs {
+		if id <= 0 {
+			v.addf("admin_user_ids (ADMIN_USER_IDS): %d is not a valid user id", id)
+		}
+	}
+	if c.SecretsConfig.RefreshInterval < 0 {
+		v.addf("secrets.refresh_interval (SECRETS_REFRESH_INTERVAL): must not be negative, got %s", c.SecretsConfig.RefreshInterval)
+	}
+	if vault := c.SecretsConfig.Vault; vault.Addr != "" {
+		v.url("secrets.vault.addr (VAULT_ADDR)", vault.Addr)
+		v.required("secrets.vault.token (VAULT_TOKEN)", vault.Token)
+		v.required("secrets.vault.mount (VAULT_KV_MOUNT)", vault.Mount)
+		v.required("secrets.vault.path (VAULT_SECRET_PATH)", vault.Path)
+	}
+	if c.ConfigWatchInterval < 0 {
+		v.addf("config_watch_interval (CONFIG_WATCH_INTERVAL): must not be negative, got %s", c.ConfigWatchInterval)
+	}
+
+	return v.warnings, v.err()
+}
+
+// Validate checks the Postgres settings alone, for commands that only
+// need the database.
+func (c *PostgresConfig) Validate() error {
+	v := &validator{}
+	c.validate(v)
+	return v.err()
+}
+
+func (c *PostgresConfig) validate(v *validator) {
+	v.required("postgres.host (DB_HOST)", c.Host)
+	v.required("postgres.user (DB_USER)", c.User)
+	v.required("postgres.password (DB_PASSWORD)", c.Password)
+	v.required("postgres.name (DB_NAME)", c.DBName)
+	v.port("postgres.port (DB_PORT)", c.Port)
+	if c.ReplicaPort != 0 {
+		v.port("postgres.replica_port (DB_REPLICA_PORT)", c.ReplicaPort)
+	}
+	if c.MaxConns <= 0 {
+		v.addf("postgres.max_conns (DB_MAX_CONNS): must be positive, got %d", c.MaxConns)
+	}
+	if c.MinConns < 0 || c.MinConns > c.MaxConns {
+		v.addf("postgres.min_conns (DB_MIN_CONNS): must be between 0 and max_conns, got %d", c.MinConns)
+	}
+	v.positive("postgres.max_conn_lifetime (DB_MAX_CONN_LIFETIME)", c.MaxConnLifetime)
+	v.positive("postgres.max_conn_idle_time (DB_MAX_CONN_IDLE_TIME)", c.MaxConnIdleTime)
+	v.positive("postgres.health_check_period (DB_HEALTH_CHECK_PERIOD)", c.HealthCheckPeriod)
+	v.positive("postgres.connect_timeout (DB_CONNECT_TIMEOUT)", c.ConnectTimeout)
+	v.positive("postgres.startup_timeout (DB_STARTUP_TIMEOUT)", c.StartupTimeout)
+	v.positive("postgres.retry_backoff (DB_RETRY_BACKOFF)", c.RetryBackoff)
+	if c.RetryMaxBackoff < c.RetryBackoff {
+		v.addf("postgres.retry_max_backoff (DB_RETRY_MAX_BACKOFF): must not be less than retry_backoff")
+	}
+	if c.MaxConnLifetimeJitter < 0 {
+		v.addf("postgres.max_conn_lifetime_jitter (DB_MAX_CONN_LIFETIME_JITTER): must not be negative, got %s", c.MaxConnLifetimeJitter)
+	}
+	if c.StatementTimeout < 0 {
+		v.addf("postgres.statement_timeout (DB_STATEMENT_TIMEOUT): must not be negative, got %s", c.StatementTimeout)
+	}
+	switch c.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		v.addf("postgres.ssl_mode (DB_SSL): unknown mode %q", c.SSLMode)
+	}
+}
+
+func (c *TLSConfig) validate(v *validator, httpPort string) {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		v.addf("tls.cert_file (TLS_CERT_FILE) and tls.key_file (TLS_KEY_FILE) must be set together")
+	}
+	if c.CertFile != "" && c.ACMEEnabled() {
+		v.addf("tls.cert_file (TLS_CERT_FILE) and tls.acme_domains (TLS_ACME_DOMAINS) are mutually exclusive")
+	}
+	if c.CertFile != "" {
+		v.notNegative("tls.reload_interval (TLS_RELOAD_INTERVAL)", c.ReloadInterval)
+	}
+	if c.ACMEEnabled() {
+		v.url("tls.acme_directory_url (TLS_ACME_DIRECTORY_URL)", c.ACMEDirectoryURL)
+		v.required("tls.acme_cache_dir (TLS_ACME_CACHE_DIR)", c.ACMECacheDir)
+		for _, domain := range c.ACMEDomains {
+			if domain == "" || strings.ContainsAny(domain, ":/*") {
+				v.addf("tls.acme_domains (TLS_ACME_DOMAINS): %q is not a host name", domain)
+			}
+		}
+	}
+	if c.RedirectPort != "" {
+		if !c.Enabled() {
+			v.addf("tls.redirect_port (TLS_REDIRECT_PORT): requires tls.cert_file (TLS_CERT_FILE) or tls.acme_domains (TLS_ACME_DOMAINS)")
+		}
+		if p, err := strconv.Atoi(c.RedirectPort); err != nil {
+			v.addf("tls.redirect_port (TLS_REDIRECT_PORT): %q is not a number", c.RedirectPort)
+		} else {
+			v.port("tls.redirect_port (TLS_REDIRECT_PORT)", p)
+		}
+		if c.RedirectPort == httpPort {
+			v.addf("tls.redirect_port (TLS_REDIRECT_PORT): must differ from http.port (HTTP_PORT)")
+		}
+	}
+	if c.ClientCAFile != "" && !c.Enabled() {
+		v.addf("tls.client_ca_file (TLS_CLIENT_CA_FILE): requires tls.cert_file (TLS_CERT_FILE) or tls.acme_domains (TLS_ACME_DOMAINS)")
+	}
+}
+
+type validator struct {
+	problems []string
+	// lenient turns devWarnf problems into warnings
+	lenient  bool
+	warnings []string
+}
+
+func (v *validator) err() error {
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// devWarnf reports a problem that is only a warning in development.
+func (v *validator) devWarnf(format string, args ...interface{}) {
+	if v.lenient {
+		v.warnings = append(v.warnings, fmt.Sprintf(format, args...))
+		return
+	}
+	v.addf(format, args...)
+}
+
+func (v *validator) required(name, value string) {
+	if value == "" {
+		v.addf("%s: is required", name)
+	}
+}
+
+func (v *validator) claimPath(name, path string) {
+	if path == "" {
+		return
+	}
+	for _, key := range strings.Split(path, ".") {
+		if strings.TrimSpace(key) == "" {
+			v.addf("%s: %q has an empty segment", name, path)
+			return
+		}
+	}
+}
+
+func (v *validator) port(name string, port int) {
+	if port < 1 || port > 65535 {
+		v.addf("%s: port %d is out of range 1-65535", name, port)
+	}
+}
+
+func (v *validator) positive(name string, d time.Duration) {
+	if d <= 0 {
+		v.addf("%s: must be a positive duration, got %s", name, d)
+	}
+}
+
+func (v *validator) notNegative(name string, d time.Duration) {
+	if d < 0 {
+		v.addf("%s: must not be negative, got %s", name, d)
+	}
+}
+
+// origin accepts an http(s) origin without a path, optionally with a
+// "*." wildcard subdomain, and "*" if anyOrigin is true.
+func (v *validator) origin(name, raw string, anyOrigin bool) {
+	if raw == "*" {
+		if !anyOrigin {
+			v.devWarnf("%s: \"*\" is not allowed, list the origins", name)
+		}
+		return
+	}
+	u, err := url.Parse(strings.Replace(raw, "://*.", "://wildcard.", 1))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil ||
+		strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" || strings.Contains(u.Host, "*") {
+		v.devWarnf("%s: %q is not an origin like https://app.example.com or https://*.example.com", name, raw)
+	}
+}
+
+// url accepts absolute http and https URLs.
+func (v *validator) url(name, raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.addf("%s: %q is not an absolute http(s) URL", name, raw)
+	}
+}
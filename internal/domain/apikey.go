@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognise
+// and are not mistaken for other tokens.
+const APIKeyPrefix = "alib_"
+
+// Scope limits what an API key may do on behalf of its owner. Scopes are
+// "<resource>:read" or "<resource>:write"; write does not imply read.
+type Scope string
+
+const (
+	ScopePapersRead       Scope = "papers:read"
+	ScopePapersWrite      Scope = "papers:write"
+	ScopeChatsRead        Scope = "chats:read"
+	ScopeChatsWrite       Scope = "chats:write"
+	ScopeCollectionsRead  Scope = "collections:read"
+	ScopeCollectionsWrite Scope = "collections:write"
+	ScopeAdminRead        Scope = "admin:read"
+	ScopeAdminWrite       Scope = "admin:write"
+)
+
+// scopeRoles is the role the owner needs to hold a scope; the roles are
+// checked again on every request, so scopes never widen them.
+var scopeRoles = map[Scope]Role{
+	ScopePapersRead:       RoleUser,
+	ScopePapersWrite:      RoleCurator,
+	ScopeChatsRead:        RoleUser,
+	ScopeChatsWrite:       RoleUser,
+	ScopeCollectionsRead:  RoleUser,
+	ScopeCollectionsWrite: RoleUser,
+	ScopeAdminRead:        RoleAdmin,
+	ScopeAdminWrite:       RoleAdmin,
+}
+
+// ParseScope converts a scope name from a request, unknown names are rejected.
+func ParseScope(raw string) (Scope, bool) {
+	s := Scope(strings.ToLower(strings.TrimSpace(raw)))
+	_, ok := scopeRoles[s]
+	return s, ok
+}
+
+// ResourceScope returns the scope needed to read (safe methods) or write a resource.
+func ResourceScope(resource string, write bool) Scope {
+	if write {
+		return Scope(resource + ":write")
+	}
+	return Scope(resource + ":read")
+}
+
+// Role returns the least role allowed to grant the scope.
+func (s Scope) Role() Role {
+	return scopeRoles[s]
+}
+
+// APIKey authenticates scripts and services as its owner. Only the hash of
+// the key is stored; Prefix identifies it in listings.
+type APIKey struct {
+	ID          int64
+	UserID      int64
+	Name        string
+	Prefix      string
+	KeyHash     []byte
+	Scopes      []Scope
+	CreatedBy   int64
+	CreatedAt   time.Time
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	RotatedFrom *int64
+}
+
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+func (k *APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeDenied  = "denied"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry is a record of the append-only audit log. Entries are chained:
+// Hash covers the entry fields and PrevHash, so editing or deleting a row
+// breaks verification of every following row.
+type AuditEntry struct {
+	ID         int64
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetIDs  []string
+	RequestID  string
+	ClientIP   string
+	Outcome    string
+	StatusCode int
+	// Payload is the redacted request payload as JSON.
+	Payload   string
+	CreatedAt time.Time
+	PrevHash  []byte
+	Hash      []byte
+}
+
+type AuditFilter struct {
+	ActorID int64
+	Action  string
+	From    time.Time
+	To      time.Time
+	Limit   int
+	Offset  int
+}
+
+// ComputeHash returns the chain hash of the entry. The field order is part of
+// the on-disk format and must not change.
+func (e *AuditEntry) ComputeHash() []byte {
+	targets := e.TargetIDs
+	if targets == nil {
+		targets = []string{}
+	}
+	canonical, _ := json.Marshal([]interface{}{
+		hex.EncodeToString(e.PrevHash),
+		e.ActorID,
+		e.Action,
+		e.TargetType,
+		targets,
+		e.RequestID,
+		e.ClientIP,
+		e.Outcome,
+		e.StatusCode,
+		e.Payload,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(canonical)
+	return sum[:]
+}
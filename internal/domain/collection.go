@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+type Collection struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	PaperCount int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// CollectionPaper is a paper saved to a collection. Paper metadata is a snapshot
+// taken at save time, so the list stays readable even if the index changes.
+type CollectionPaper struct {
+	CollectionID   int64
+	PaperID        string
+	Title          string
+	Abstract       string
+	Year           int64
+	BestOaLocation string
+	Note           string
+	Tags           []string
+	AddedAt        time.Time
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// ChatShare is a read-only link to a chat. Only the hash of the link token is stored.
+type ChatShare struct {
+	ID        int64
+	ChatID    int64
+	UserID    int64
+	Title     string
+	TokenHash []byte
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+func (s *ChatShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleUser    Role = "user"
+	RoleCurator Role = "curator"
+	RoleAdmin   Role = "admin"
+)
+
+var roleRank = map[Role]int{
+	RoleUser:    1,
+	RoleCurator: 2,
+	RoleAdmin:   3,
+}
+
+// ParseRole converts a role name from SSO claims or requests, unknown names are rejected.
+func ParseRole(raw string) (Role, bool) {
+	r := Role(strings.ToLower(strings.TrimSpace(raw)))
+	_, ok := roleRank[r]
+	return r, ok
+}
+
+// Includes reports whether r grants everything other grants (admin > curator > user).
+func (r Role) Includes(other Role) bool {
+	return roleRank[r] >= roleRank[other] && roleRank[other] > 0
+}
+
+// User is a user known to the gateway. Accounts live in SSO, the gateway only
+// keeps its role assignment and activity.
+type User struct {
+	ID         int64
+	Role       Role
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
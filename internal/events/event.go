@@ -0,0 +1,36 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types pushed to the user's sockets.
+const (
+	ChatCreated        = "chat.created"
+	ChatUpdated        = "chat.updated"
+	ChatDeleted        = "chat.deleted"
+	ChatHistoryCreated = "chat.history.created"
+)
+
+// Event is both the Redis message and the WebSocket frame sent to the client.
+type Event struct {
+	Type      string          `json:"type"`
+	UserID    int64           `json:"user_id"`
+	ChatID    int64           `json:"chat_id,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewEvent encodes data as the event payload.
+func NewEvent(eventType string, userID, chatID int64, data interface{}) (Event, error) {
+	ev := Event{Type: eventType, UserID: userID, ChatID: chatID, CreatedAt: time.Now().UTC()}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return Event{}, err
+		}
+		ev.Data = raw
+	}
+	return ev, nil
+}
@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const redisChannel = "gateway:events"
+
+var ErrTooManySubscriptions = errors.New("too many open connections")
+
+// Hub fans events out to the subscriptions of a user. With Redis every
+// replica publishes to a shared channel and delivers what it receives from
+// it, so a socket gets events produced on any replica. Without Redis events
+// are delivered in process only.
+type Hub struct {
+	redis  *redis.Client
+	logger *logrus.Logger
+
+	mu   sync.RWMutex
+	subs map[int64]map[*Subscription]struct{}
+}
+
+func NewHub(client *redis.Client, logger *logrus.Logger) *Hub {
+	return &Hub{
+		redis:  client,
+		logger: logger,
+		subs:   make(map[int64]map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives encoded events of one user. Messages is bounded:
+// when a consumer does not keep up, Overflow is closed and further events
+// are dropped for it.
+type Subscription struct {
+	hub      *Hub
+	userID   int64
+	messages chan []byte
+	overflow chan struct{}
+	once     sync.Once
+}
+
+func (s *Subscription) Messages() <-chan []byte { return s.messages }
+
+func (s *Subscription) Overflow() <-chan struct{} { return s.overflow }
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if subs, ok := s.hub.subs[s.userID]; ok {
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(s.hub.subs, s.userID)
+		}
+	}
+}
+
+// Subscribe registers a new subscription for the user. maxPerUser <= 0 means unlimited.
+func (h *Hub) Subscribe(userID int64, buffer, maxPerUser int) (*Subscription, error) {
+	if buffer <= 0 {
+		buffer = 1
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.subs[userID]
+	if maxPerUser > 0 && len(subs) >= maxPerUser {
+		return nil, ErrTooManySubscriptions
+	}
+	if subs == nil {
+		subs = make(map[*Subscription]struct{})
+		h.subs[userID] = subs
+	}
+	s := &Subscription{
+		hub:      h,
+		userID:   userID,
+		messages: make(chan []byte, buffer),
+		overflow: make(chan struct{}),
+	}
+	subs[s] = struct{}{}
+	return s, nil
+}
+
+// Publish sends the event to all sockets of ev.UserID. If Redis is not
+// reachable the event still reaches the sockets of this replica.
+func (h *Hub) Publish(ctx context.Context, ev Event) error {
+	if ev.CreatedAt.IsZero() {
+		ev.CreatedAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	if h.redis == nil {
+		h.deliver(ev.UserID, payload)
+		return nil
+	}
+	if err := h.redis.Publish(ctx, redisChannel, payload).Err(); err != nil {
+		h.deliver(ev.UserID, payload)
+		return fmt.Errorf("publish event: %w", err)
+	}
+	return nil
+}
+
+// Run delivers events received from Redis until ctx is done. Reconnects
+// are handled by the Redis client.
+func (h *Hub) Run(ctx context.Context) {
+	if h.redis == nil {
+		<-ctx.Done()
+		return
+	}
+	pubsub := h.redis.Subscribe(ctx, redisChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var head struct {
+				UserID int64 `json:"user_id"`
+			}
+			if err := json.Unmarshal([]byte(msg.Payload), &head); err != nil || head.UserID == 0 {
+				h.logger.WithError(err).Warn("Skip malformed event from Redis")
+				continue
+			}
+			h.deliver(head.UserID, []byte(msg.Payload))
+		}
+	}
+}
+
+func (h *Hub) deliver(userID int64, payload []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subs[userID] {
+		select {
+		case s.messages <- payload:
+		default:
+			s.once.Do(func() { close(s.overflow) })
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestHub(client *redis.Client) *Hub {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewHub(client, logger)
+}
+
+func publish(t *testing.T, h *Hub, userID, chatID int64) {
+	t.Helper()
+	ev, err := NewEvent(ChatUpdated, userID, chatID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Publish(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// receive waits for the next message of s and returns its chat id.
+func receive(t *testing.T, s *Subscription) int64 {
+	t.Helper()
+	select {
+	case msg := <-s.Messages():
+		var ev Event
+		if err := json.Unmarshal(msg, &ev); err != nil {
+			t.Fatal(err)
+		}
+		return ev.ChatID
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+		return 0
+	}
+}
+
+func expectNothing(t *testing.T, s *Subscription) {
+	t.Helper()
+	select {
+	case msg := <-s.Messages():
+		t.Fatalf("unexpected event %s", msg)
+	default:
+	}
+}
+
+func TestSlowSubscriptionOverflows(t *testing.T) {
+	h := newTestHub(nil)
+	slow, err := h.Subscribe(1, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, err := h.Subscribe(1, 8, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publish(t, h, 1, 1)
+	publish(t, h, 1, 2)
+	select {
+	case <-slow.Overflow():
+		t.Fatal("overflow before the buffer is full")
+	default:
+	}
+	// The third event does not fit and is dropped for the slow consumer only
+	publish(t, h, 1, 3)
+	publish(t, h, 1, 4)
+	select {
+	case <-slow.Overflow():
+	default:
+		t.Fatal("overflow not signalled")
+	}
+	if got := []int64{receive(t, slow), receive(t, slow)}; got[0] != 1 || got[1] != 2 {
+		t.Fatalf("slow consumer got chats %v, want [1 2]", got)
+	}
+	expectNothing(t, slow)
+	for want := int64(1); want <= 4; want++ {
+		if got := receive(t, fast); got != want {
+			t.Fatalf("fast consumer got chat %d, want %d", got, want)
+		}
+	}
+}
+
+func TestSubscribeLimitsConnectionsPerUser(t *testing.T) {
+	h := newTestHub(nil)
+	first, err := h.Subscribe(1, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Subscribe(1, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Subscribe(1, 1, 2); !errors.Is(err, ErrTooManySubscriptions) {
+		t.Fatalf("third subscription: err = %v, want ErrTooManySubscriptions", err)
+	}
+	// The limit is per user
+	if _, err := h.Subscribe(2, 1, 2); err != nil {
+		t.Fatalf("other user: %v", err)
+	}
+	first.Close()
+	if _, err := h.Subscribe(1, 1, 2); err != nil {
+		t.Fatalf("after close: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := h.Subscribe(3, 1, 0); err != nil {
+			t.Fatalf("unlimited: %v", err)
+		}
+	}
+}
+
+func TestEventsReachOnlyTheOwner(t *testing.T) {
+	h := newTestHub(nil)
+	owner, _ := h.Subscribe(1, 4, 0)
+	ownerDevice, _ := h.Subscribe(1, 4, 0)
+	other, _ := h.Subscribe(2, 4, 0)
+	closed, _ := h.Subscribe(1, 4, 0)
+	closed.Close()
+
+	publish(t, h, 1, 10)
+	if receive(t, owner) != 10 || receive(t, ownerDevice) != 10 {
+		t.Fatal("owner sockets did not get the event")
+	}
+	expectNothing(t, other)
+	expectNothing(t, closed)
+}
+
+func TestRedisEventsAreDeliveredLocally(t *testing.T) {
+	fake := newFakeRedis(t)
+	client := func() *redis.Client {
+		c := redis.NewClient(&redis.Options{Addr: fake.addr, Protocol: 2, DisableIdentity: true})
+		t.Cleanup(func() { c.Close() })
+		return c
+	}
+	// Two replicas sharing the channel
+	producer, consumer := newTestHub(client()), newTestHub(client())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go producer.Run(ctx)
+	go consumer.Run(ctx)
+
+	local, _ := producer.Subscribe(1, 4, 0)
+	remote, _ := consumer.Subscribe(1, 4, 0)
+	other, _ := consumer.Subscribe(2, 4, 0)
+
+	// Run subscribes asynchronously, retry until the channel has both hubs
+	deadline := time.Now().Add(2 * time.Second)
+	for fake.subscribers(redisChannel) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("hubs did not subscribe to Redis")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Malformed messages and events without a user are skipped
+	raw := client()
+	for _, payload := range []string{"not json", `{"type":"chat.updated","chat_id":5}`} {
+		if err := raw.Publish(ctx, redisChannel, payload).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	publish(t, producer, 1, 7)
+	if receive(t, remote) != 7 {
+		t.Fatal("event from Redis not delivered on the other replica")
+	}
+	// The producer gets its own event back from Redis, exactly once
+	if receive(t, local) != 7 {
+		t.Fatal("event from Redis not delivered on the producing replica")
+	}
+	expectNothing(t, local)
+	expectNothing(t, remote)
+	expectNothing(t, other)
+}
+
+// fakeRedis implements the subset of RESP2 the hub uses: PUBLISH, SUBSCRIBE
+// and PING. Other commands, such as the HELLO the client starts with, get an
+// error reply, which the client treats as an old server.
+type fakeRedis struct {
+	addr string
+
+	mu   sync.Mutex
+	subs map[string]map[*fakeRedisConn]struct{}
+}
+
+type fakeRedisConn struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func (c *fakeRedisConn) write(parts ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, p := range parts {
+		c.w.WriteString(p)
+	}
+	c.w.Flush()
+}
+
+func bulk(s string) string { return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s) }
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &fakeRedis{addr: ln.Addr().String(), subs: map[string]map[*fakeRedisConn]struct{}{}}
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	t.Cleanup(func() {
+		ln.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, nc := range conns {
+			nc.Close()
+		}
+	})
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, nc)
+			mu.Unlock()
+			go srv.serve(nc)
+		}
+	}()
+	return srv
+}
+
+func (s *fakeRedis) subscribers(channel string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs[channel])
+}
+
+func (s *fakeRedis) serve(nc net.Conn) {
+	conn := &fakeRedisConn{w: bufio.NewWriter(nc)}
+	defer func() {
+		s.mu.Lock()
+		for _, subs := range s.subs {
+			delete(subs, conn)
+		}
+		s.mu.Unlock()
+	}()
+	r := bufio.NewReader(nc)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			conn.write("+PONG\r\n")
+		case "SUBSCRIBE":
+			for _, ch := range args[1:] {
+				s.mu.Lock()
+				if s.subs[ch] == nil {
+					s.subs[ch] = map[*fakeRedisConn]struct{}{}
+				}
+				s.subs[ch][conn] = struct{}{}
+				s.mu.Unlock()
+				conn.write("*3\r\n", bulk("subscribe"), bulk(ch), ":1\r\n")
+			}
+		case "PUBLISH":
+			s.mu.Lock()
+			n := 0
+			for sub := range s.subs[args[1]] {
+				sub.write("*3\r\n", bulk("message"), bulk(args[1]), bulk(args[2]))
+				n++
+			}
+			s.mu.Unlock()
+			conn.write(fmt.Sprintf(":%d\r\n", n))
+		default:
+			conn.write("-ERR unknown command '" + args[0] + "'\r\n")
+		}
+	}
+}
+
+// readCommand reads one command sent as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("unexpected %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
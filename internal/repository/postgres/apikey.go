@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/pkg/storage"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type apiKeyRepository struct {
+	db *storage.DB
+}
+
+func NewAPIKeyRepository(db *storage.DB) repository.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at, rotated_from`
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	out := *key
+	err := r.db.Primary.QueryRow(ctx, `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, scopeNames(key.Scopes), key.CreatedBy, key.ExpiresAt,
+	).Scan(&out.ID, &out.CreatedAt)
+	if err != nil {
+		return nil, mapError(err, "create api key")
+	}
+	return &out, nil
+}
+
+func (r *apiKeyRepository) GetUserAPIKeys(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get api keys: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.APIKey, 0)
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		out = append(out, *k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get api keys: %w", err)
+	}
+	return out, nil
+}
+
+func (r *apiKeyRepository) RotateAPIKey(ctx context.Context, userID, keyID int64, next *domain.APIKey, oldExpiresAt time.Time) (*domain.APIKey, error) {
+	tx, err := r.db.Primary.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rotate api key: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	old, err := scanAPIKey(tx.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE id = $1 AND user_id = $2
+		FOR UPDATE`,
+		keyID, userID,
+	))
+	if err != nil {
+		return nil, mapError(err, "rotate api key")
+	}
+	if !old.Active(time.Now()) {
+		return nil, repository.ErrNotFound
+	}
+
+	out := *next
+	out.UserID = old.UserID
+	out.Name = old.Name
+	out.Scopes = old.Scopes
+	out.RotatedFrom = &old.ID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, created_by, expires_at, rotated_from)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`,
+		out.UserID, out.Name, out.Prefix, out.KeyHash, scopeNames(out.Scopes), out.CreatedBy, out.ExpiresAt, old.ID,
+	).Scan(&out.ID, &out.CreatedAt)
+	if err != nil {
+		return nil, mapError(err, "rotate api key")
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE api_keys
+		SET expires_at = LEAST(COALESCE(expires_at, $2), $2)
+		WHERE id = $1`,
+		old.ID, oldExpiresAt,
+	); err != nil {
+		return nil, fmt.Errorf("rotate api key: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("rotate api key: %w", err)
+	}
+	return &out, nil
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, userID, keyID int64) error {
+	tag, err := r.db.Primary.Exec(ctx, `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, now())
+		WHERE id = $1 AND user_id = $2`,
+		keyID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetAPIKeyByHash authenticates requests and stays on the primary, a
+// revoked or rotated key must be refused at once.
+func (r *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash []byte) (*domain.APIKey, error) {
+	k, err := scanAPIKey(r.db.Primary.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE key_hash = $1`,
+		keyHash,
+	))
+	if err != nil {
+		return nil, mapError(err, "get api key")
+	}
+	return k, nil
+}
+
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, keyID int64, interval time.Duration) error {
+	_, err := r.db.Primary.Exec(ctx, `
+		UPDATE api_keys
+		SET last_used_at = now()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - make_interval(secs => $2))`,
+		keyID, interval.Seconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("touch api key: %w", err)
+	}
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*domain.APIKey, error) {
+	var (
+		k      domain.APIKey
+		scopes []string
+	)
+	if err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &scopes, &k.CreatedBy, &k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.RotatedFrom); err != nil {
+		return nil, err
+	}
+	k.Scopes = make([]domain.Scope, 0, len(scopes))
+	for _, s := range scopes {
+		k.Scopes = append(k.Scopes, domain.Scope(s))
+	}
+	return &k, nil
+}
+
+func scopeNames(scopes []domain.Scope) []string {
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		out = append(out, string(s))
+	}
+	return out
+}
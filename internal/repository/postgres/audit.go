@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// auditChainLock serializes appends so that every entry links to the latest hash.
+const auditChainLock = 0x61756469 // "audi"
+
+const auditColumns = `id, actor_id, action, target_type, target_ids, request_id, client_ip, outcome, status_code, payload, created_at, prev_hash, hash`
+
+type auditRepository struct {
+	db *storage.DB
+}
+
+func NewAuditRepository(db *storage.DB) repository.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry.TargetIDs == nil {
+		entry.TargetIDs = []string{}
+	}
+	tx, err := r.db.Primary.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return fmt.Errorf("lock audit chain: %w", err)
+	}
+	var prev []byte
+	err = tx.QueryRow(ctx, `SELECT hash FROM audit_log WHERE hash IS NOT NULL ORDER BY id DESC LIMIT 1`).Scan(&prev)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("read audit chain head: %w", err)
+	}
+
+	// Postgres keeps microseconds, truncate so the stored value hashes the same.
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.PrevHash = prev
+	entry.Hash = entry.ComputeHash()
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO audit_log (actor_id, action, target_type, target_ids, request_id, client_ip, outcome, status_code, payload, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id`,
+		entry.ActorID, entry.Action, entry.TargetType, entry.TargetIDs, entry.RequestID, entry.ClientIP,
+		entry.Outcome, entry.StatusCode, entry.Payload, entry.CreatedAt, entry.PrevHash, entry.Hash,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *auditRepository) GetAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	where, args := auditWhere(filter)
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.Reader().Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log
+		%s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d`, auditColumns, where, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get audit entries: %w", err)
+	}
+	return out, nil
+}
+
+func (r *auditRepository) StreamAuditEntries(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEntry) error) error {
+	where, args := auditWhere(filter)
+	rows, err := r.db.Reader().Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log
+		%s
+		ORDER BY id`, auditColumns, where),
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("stream audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("stream audit entries: %w", err)
+	}
+	return nil
+}
+
+func auditWhere(filter domain.AuditFilter) (string, []interface{}) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	if filter.ActorID > 0 {
+		args = append(args, filter.ActorID)
+		conds = append(conds, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conds = append(conds, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+func scanAuditEntry(rows pgx.Rows) (*domain.AuditEntry, error) {
+	var e domain.AuditEntry
+	err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.TargetType, &e.TargetIDs, &e.RequestID, &e.ClientIP,
+		&e.Outcome, &e.StatusCode, &e.Payload, &e.CreatedAt, &e.PrevHash, &e.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("scan audit entry: %w", err)
+	}
+	return &e, nil
+}
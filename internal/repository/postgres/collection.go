@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const uniqueViolation = "23505"
+
+type collectionRepository struct {
+	db *storage.DB
+}
+
+func NewCollectionRepository(db *storage.DB) repository.CollectionRepository {
+	return &collectionRepository{db: db}
+}
+
+func (r *collectionRepository) CreateCollection(ctx context.Context, userID int64, name string) (*domain.Collection, error) {
+	c := domain.Collection{UserID: userID, Name: name}
+	err := r.db.Primary.QueryRow(ctx, `
+		INSERT INTO collections (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at`,
+		userID, name,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, mapError(err, "create collection")
+	}
+	return &c, nil
+}
+
+func (r *collectionRepository) GetUserCollections(ctx context.Context, userID int64) ([]domain.Collection, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT c.id, c.user_id, c.name, c.created_at, c.updated_at, COUNT(p.paper_id)
+		FROM collections c
+		LEFT JOIN collection_papers p ON p.collection_id = c.id
+		WHERE c.user_id = $1
+		GROUP BY c.id
+		ORDER BY c.updated_at DESC, c.id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get user collections: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Collection, 0)
+	for rows.Next() {
+		var c domain.Collection
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.CreatedAt, &c.UpdatedAt, &c.PaperCount); err != nil {
+			return nil, fmt.Errorf("scan collection: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get user collections: %w", err)
+	}
+	return out, nil
+}
+
+func (r *collectionRepository) RenameCollection(ctx context.Context, userID, collectionID int64, name string) (*domain.Collection, error) {
+	c := domain.Collection{ID: collectionID, UserID: userID, Name: name}
+	err := r.db.Primary.QueryRow(ctx, `
+		UPDATE collections
+		SET name = $3, updated_at = now()
+		WHERE id = $1 AND user_id = $2
+		RETURNING created_at, updated_at,
+			(SELECT COUNT(*) FROM collection_papers WHERE collection_id = $1)`,
+		collectionID, userID, name,
+	).Scan(&c.CreatedAt, &c.UpdatedAt, &c.PaperCount)
+	if err != nil {
+		return nil, mapError(err, "rename collection")
+	}
+	return &c, nil
+}
+
+func (r *collectionRepository) DeleteCollection(ctx context.Context, userID, collectionID int64) error {
+	tag, err := r.db.Primary.Exec(ctx, `DELETE FROM collections WHERE id = $1 AND user_id = $2`, collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("delete collection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *collectionRepository) GetCollectionPapers(ctx context.Context, userID, collectionID int64) ([]domain.CollectionPaper, error) {
+	if err := r.checkOwner(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT collection_id, paper_id, title, abstract, year, best_oa_location, note, tags, added_at
+		FROM collection_papers
+		WHERE collection_id = $1
+		ORDER BY added_at DESC`,
+		collectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get collection papers: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.CollectionPaper, 0)
+	for rows.Next() {
+		var p domain.CollectionPaper
+		if err := rows.Scan(&p.CollectionID, &p.PaperID, &p.Title, &p.Abstract, &p.Year, &p.BestOaLocation, &p.Note, &p.Tags, &p.AddedAt); err != nil {
+			return nil, fmt.Errorf("scan collection paper: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get collection papers: %w", err)
+	}
+	return out, nil
+}
+
+// SaveCollectionPaper adds a paper or, if it is already saved, replaces its note, tags and snapshot.
+func (r *collectionRepository) SaveCollectionPaper(ctx context.Context, userID int64, paper *domain.CollectionPaper) (*domain.CollectionPaper, error) {
+	tags := paper.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	out := *paper
+	out.Tags = tags
+
+	tx, err := r.db.Primary.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("save collection paper: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE collections SET updated_at = now() WHERE id = $1 AND user_id = $2`, paper.CollectionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("save collection paper: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, repository.ErrNotFound
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO collection_papers (collection_id, paper_id, title, abstract, year, best_oa_location, note, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (collection_id, paper_id) DO UPDATE
+		SET title = EXCLUDED.title,
+			abstract = EXCLUDED.abstract,
+			year = EXCLUDED.year,
+			best_oa_location = EXCLUDED.best_oa_location,
+			note = EXCLUDED.note,
+			tags = EXCLUDED.tags
+		RETURNING added_at`,
+		paper.CollectionID, paper.PaperID, paper.Title, paper.Abstract, paper.Year, paper.BestOaLocation, paper.Note, tags,
+	).Scan(&out.AddedAt)
+	if err != nil {
+		return nil, mapError(err, "save collection paper")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("save collection paper: %w", err)
+	}
+	return &out, nil
+}
+
+func (r *collectionRepository) RemoveCollectionPaper(ctx context.Context, userID, collectionID int64, paperID string) error {
+	tag, err := r.db.Primary.Exec(ctx, `
+		DELETE FROM collection_papers p
+		USING collections c
+		WHERE p.collection_id = c.id AND c.id = $1 AND c.user_id = $2 AND p.paper_id = $3`,
+		collectionID, userID, paperID,
+	)
+	if err != nil {
+		return fmt.Errorf("remove collection paper: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	_, err = r.db.Primary.Exec(ctx, `UPDATE collections SET updated_at = now() WHERE id = $1`, collectionID)
+	if err != nil {
+		return fmt.Errorf("remove collection paper: %w", err)
+	}
+	return nil
+}
+
+// checkOwner runs on the reader, like GetCollectionPapers, its only caller.
+// Writes check ownership inside their own statements on the primary.
+func (r *collectionRepository) checkOwner(ctx context.Context, userID, collectionID int64) error {
+	var exists bool
+	err := r.db.Reader().QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM collections WHERE id = $1 AND user_id = $2)`, collectionID, userID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check collection owner: %w", err)
+	}
+	if !exists {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// mapError converts pgx errors to repository sentinel errors.
+func mapError(err error, op string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return repository.ErrAlreadyExists
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
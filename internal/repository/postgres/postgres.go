@@ -1,15 +1,112 @@
 package postgres
 
 import (
+	"VKR_gateway_service/internal/domain"
 	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 )
 
 type userRepository struct {
-	db *pgxpool.Pool
+	db *storage.DB
 }
 
-func NewUserRepository(db *pgxpool.Pool) repository.UserRepository {
+func NewUserRepository(db *storage.DB) repository.UserRepository {
 	return &userRepository{db: db}
 }
+
+// TouchUser reads the role of a known user without writing; last_seen_at
+// is updated only once it is older than interval. The primary is read so
+// role changes apply to the next request.
+func (r *userRepository) TouchUser(ctx context.Context, userID int64, interval time.Duration) (domain.Role, error) {
+	var (
+		role     string
+		lastSeen time.Time
+	)
+	err := r.db.Primary.QueryRow(ctx, `
+		SELECT role, last_seen_at
+		FROM users
+		WHERE user_id = $1`,
+		userID,
+	).Scan(&role, &lastSeen)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// First sight, a concurrent request may have inserted the row
+		err = r.db.Primary.QueryRow(ctx, `
+			INSERT INTO users (user_id)
+			VALUES ($1)
+			ON CONFLICT (user_id) DO UPDATE SET last_seen_at = now()
+			RETURNING role`,
+			userID,
+		).Scan(&role)
+		if err != nil {
+			return "", fmt.Errorf("touch user: %w", err)
+		}
+		return domain.Role(role), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("touch user: %w", err)
+	}
+	if time.Since(lastSeen) >= interval {
+		_, err := r.db.Primary.Exec(ctx, `
+			UPDATE users
+			SET last_seen_at = now()
+			WHERE user_id = $1 AND last_seen_at < now() - make_interval(secs => $2)`,
+			userID, interval.Seconds(),
+		)
+		if err != nil {
+			return "", fmt.Errorf("touch user: %w", err)
+		}
+	}
+	return domain.Role(role), nil
+}
+
+func (r *userRepository) GetUsers(ctx context.Context, limit, offset int) ([]domain.User, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT user_id, role, created_at, last_seen_at
+		FROM users
+		ORDER BY user_id
+		LIMIT $1 OFFSET $2`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.User, 0)
+	for rows.Next() {
+		var (
+			u    domain.User
+			role string
+		)
+		if err := rows.Scan(&u.ID, &role, &u.CreatedAt, &u.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		u.Role = domain.Role(role)
+		out = append(out, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+	return out, nil
+}
+
+func (r *userRepository) SetUserRole(ctx context.Context, userID int64, role domain.Role) (*domain.User, error) {
+	u := domain.User{ID: userID, Role: role}
+	err := r.db.Primary.QueryRow(ctx, `
+		INSERT INTO users (user_id, role)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING created_at, last_seen_at`,
+		userID, string(role),
+	).Scan(&u.CreatedAt, &u.LastSeenAt)
+	if err != nil {
+		return nil, mapError(err, "set user role")
+	}
+	return &u, nil
+}
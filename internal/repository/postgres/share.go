@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/pkg/storage"
+	"context"
+	"fmt"
+)
+
+type chatShareRepository struct {
+	db *storage.DB
+}
+
+func NewChatShareRepository(db *storage.DB) repository.ChatShareRepository {
+	return &chatShareRepository{db: db}
+}
+
+const chatShareColumns = `id, chat_id, user_id, title, token_hash, created_at, expires_at, revoked_at`
+
+func (r *chatShareRepository) CreateShare(ctx context.Context, share *domain.ChatShare) (*domain.ChatShare, error) {
+	out := *share
+	err := r.db.Primary.QueryRow(ctx, `
+		INSERT INTO chat_shares (chat_id, user_id, title, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		share.ChatID, share.UserID, share.Title, share.TokenHash, share.ExpiresAt,
+	).Scan(&out.ID, &out.CreatedAt)
+	if err != nil {
+		return nil, mapError(err, "create chat share")
+	}
+	return &out, nil
+}
+
+func (r *chatShareRepository) GetChatShares(ctx context.Context, userID, chatID int64) ([]domain.ChatShare, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT `+chatShareColumns+`
+		FROM chat_shares
+		WHERE user_id = $1 AND chat_id = $2
+		ORDER BY created_at DESC`,
+		userID, chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get chat shares: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ChatShare, 0)
+	for rows.Next() {
+		var s domain.ChatShare
+		if err := rows.Scan(&s.ID, &s.ChatID, &s.UserID, &s.Title, &s.TokenHash, &s.CreatedAt, &s.ExpiresAt, &s.RevokedAt); err != nil {
+			return nil, fmt.Errorf("scan chat share: %w", err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get chat shares: %w", err)
+	}
+	return out, nil
+}
+
+func (r *chatShareRepository) RevokeShare(ctx context.Context, userID, chatID, shareID int64) error {
+	tag, err := r.db.Primary.Exec(ctx, `
+		UPDATE chat_shares
+		SET revoked_at = COALESCE(revoked_at, now())
+		WHERE id = $1 AND user_id = $2 AND chat_id = $3`,
+		shareID, userID, chatID,
+	)
+	if err != nil {
+		return fmt.Errorf("revoke chat share: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetShareByTokenHash stays on the primary so that a revoked share stops
+// working at once rather than after the replica catches up.
+func (r *chatShareRepository) GetShareByTokenHash(ctx context.Context, tokenHash []byte) (*domain.ChatShare, error) {
+	var s domain.ChatShare
+	err := r.db.Primary.QueryRow(ctx, `
+		SELECT `+chatShareColumns+`
+		FROM chat_shares
+		WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&s.ID, &s.ChatID, &s.UserID, &s.Title, &s.TokenHash, &s.CreatedAt, &s.ExpiresAt, &s.RevokedAt)
+	if err != nil {
+		return nil, mapError(err, "get chat share")
+	}
+	return &s, nil
+}
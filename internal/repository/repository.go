@@ -1,7 +1,68 @@
 package repository
 
+import (
+	"VKR_gateway_service/internal/domain"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+)
+
+// UserRepository keeps gateway role assignments of SSO users.
 type UserRepository interface {
-	// GetUserByID(ctx context.Context, id int) (*domain.User, error)
-	// UpdateUser(ctx context.Context, user *domain.User) error
-	// CreateUser(ctx context.Context, user *domain.User) (userID int, err error)
+	// TouchUser registers the user on first sight, updates last activity at
+	// most once per interval and returns the stored role.
+	TouchUser(ctx context.Context, userID int64, interval time.Duration) (domain.Role, error)
+	GetUsers(ctx context.Context, limit, offset int) ([]domain.User, error)
+	SetUserRole(ctx context.Context, userID int64, role domain.Role) (*domain.User, error)
+}
+
+// AuditRepository is an append-only, hash-chained log of mutating and privileged actions.
+type AuditRepository interface {
+	// CreateAuditEntry links the entry to the chain and fills ID, CreatedAt, PrevHash and Hash.
+	CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) error
+	GetAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+	// StreamAuditEntries calls fn for every matching entry in chain order, ignoring Limit and Offset.
+	StreamAuditEntries(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEntry) error) error
+}
+
+// CollectionRepository stores saved papers. Every method is scoped by userID,
+// collections of other users are reported as ErrNotFound.
+type CollectionRepository interface {
+	CreateCollection(ctx context.Context, userID int64, name string) (*domain.Collection, error)
+	GetUserCollections(ctx context.Context, userID int64) ([]domain.Collection, error)
+	RenameCollection(ctx context.Context, userID, collectionID int64, name string) (*domain.Collection, error)
+	DeleteCollection(ctx context.Context, userID, collectionID int64) error
+	GetCollectionPapers(ctx context.Context, userID, collectionID int64) ([]domain.CollectionPaper, error)
+	SaveCollectionPaper(ctx context.Context, userID int64, paper *domain.CollectionPaper) (*domain.CollectionPaper, error)
+	RemoveCollectionPaper(ctx context.Context, userID, collectionID int64, paperID string) error
+}
+
+// APIKeyRepository stores API keys. Methods taking userID are scoped by it,
+// keys of other users are reported as ErrNotFound.
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+	GetUserAPIKeys(ctx context.Context, userID int64) ([]domain.APIKey, error)
+	// RotateAPIKey stores next as the successor of an active key, copying its
+	// name and scopes, and makes the old key expire at oldExpiresAt unless it
+	// already expires earlier.
+	RotateAPIKey(ctx context.Context, userID, keyID int64, next *domain.APIKey, oldExpiresAt time.Time) (*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID int64) error
+	// GetAPIKeyByHash returns the key regardless of its state, callers check Active.
+	GetAPIKeyByHash(ctx context.Context, keyHash []byte) (*domain.APIKey, error)
+	// TouchAPIKey records a use of the key, at most once per interval.
+	TouchAPIKey(ctx context.Context, keyID int64, interval time.Duration) error
+}
+
+// ChatShareRepository stores read-only share links to chats.
+type ChatShareRepository interface {
+	CreateShare(ctx context.Context, share *domain.ChatShare) (*domain.ChatShare, error)
+	GetChatShares(ctx context.Context, userID, chatID int64) ([]domain.ChatShare, error)
+	RevokeShare(ctx context.Context, userID, chatID, shareID int64) error
+	// GetShareByTokenHash returns the share regardless of its state, callers check Active.
+	GetShareByTokenHash(ctx context.Context, tokenHash []byte) (*domain.ChatShare, error)
 }
@@ -0,0 +1,39 @@
+// Package repotest provides in-memory repositories for tests.
+package repotest
+
+import (
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"context"
+	"sync"
+)
+
+var _ repository.AuditRepository = (*AuditRepository)(nil)
+
+// AuditRepository records the entries written to it. Queries return nothing.
+type AuditRepository struct {
+	mu      sync.Mutex
+	entries []domain.AuditEntry
+}
+
+func (r *AuditRepository) CreateAuditEntry(_ context.Context, entry *domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *AuditRepository) GetAuditEntries(context.Context, domain.AuditFilter) ([]domain.AuditEntry, error) {
+	return nil, nil
+}
+
+func (r *AuditRepository) StreamAuditEntries(context.Context, domain.AuditFilter, func(*domain.AuditEntry) error) error {
+	return nil
+}
+
+// Entries returns a copy of the entries recorded so far.
+func (r *AuditRepository) Entries() []domain.AuditEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.AuditEntry(nil), r.entries...)
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"VKR_gateway_service/internal/config"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const refreshTimeout = 30 * time.Second
+
+// Manager merges secrets from providers into the config store and
+// refreshes them periodically.
+type Manager struct {
+	store     *config.Store
+	providers []Provider
+	logger    *logrus.Logger
+
+	mu        sync.Mutex
+	listeners []func(cfg *config.Config, changed []string)
+}
+
+func NewManager(store *config.Store, providers []Provider, logger *logrus.Logger) *Manager {
+	return &Manager{store: store, providers: providers, logger: logger}
+}
+
+// OnChange registers fn to be called with the new config and the file keys
+// of the changed settings whenever a refresh changes secrets.
+func (m *Manager) OnChange(fn func(cfg *config.Config, changed []string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// Refresh reads every provider, later providers overriding earlier ones.
+// Each provider sees the config with the secrets of the previous ones, so
+// Vault can use a token from VAULT_TOKEN_FILE. If any provider fails the
+// current secrets are kept.
+func (m *Manager) Refresh(ctx context.Context) ([]string, error) {
+	cfg := *m.store.Config()
+	values := map[string]string{}
+	for _, p := range m.providers {
+		secrets, err := p.Secrets(ctx, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%s secrets: %w", p.Name(), err)
+		}
+		for key, value := range secrets {
+			values[key] = value
+		}
+		config.ApplySecrets(&cfg, secrets)
+	}
+
+	changed := m.store.SetSecrets(values)
+	if len(changed) > 0 {
+		m.mu.Lock()
+		listeners := append([]func(*config.Config, []string){}, m.listeners...)
+		m.mu.Unlock()
+		current := m.store.Config()
+		for _, fn := range listeners {
+			fn(current, changed)
+		}
+	}
+	return changed, nil
+}
+
+// Run refreshes secrets every SECRETS_REFRESH_INTERVAL until ctx is done.
+func (m *Manager) Run(ctx context.Context) {
+	interval := m.store.Config().SecretsConfig.RefreshInterval
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		refreshCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		changed, err := m.Refresh(refreshCtx)
+		cancel()
+		if err != nil {
+			m.logger.WithError(err).Error("Secret refresh failed, keeping current secrets")
+		} else if len(changed) > 0 {
+			m.logger.Infof("Secrets rotated: %s", strings.Join(changed, ", "))
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package secrets reads secret settings from sources other than plain env
+// variables and keeps them fresh at runtime. Values are keyed by the env
+// variable name of the setting, e.g. DB_PASSWORD, see config.SecretKeys.
+package secrets
+
+import (
+	"VKR_gateway_service/internal/config"
+	"context"
+	"net/http"
+	"time"
+)
+
+// Provider returns current secret values. Keys a provider does not know
+// are left out of the result. cfg holds the secrets read so far.
+type Provider interface {
+	Name() string
+	Secrets(ctx context.Context, cfg *config.Config) (map[string]string, error)
+}
+
+// FileProvider re-reads the files named by NAME_FILE variables, so secrets
+// rotated in mounted Docker or Kubernetes secret files are picked up.
+type FileProvider struct{}
+
+func (FileProvider) Name() string {
+	return "file"
+}
+
+func (FileProvider) Secrets(context.Context, *config.Config) (map[string]string, error) {
+	return config.ReadSecretFiles()
+}
+
+// Providers returns the providers enabled in cfg, in increasing order of
+// precedence.
+func Providers(cfg *config.Config) []Provider {
+	providers := []Provider{FileProvider{}}
+	if cfg.SecretsConfig.Vault.Addr != "" {
+		providers = append(providers, NewVaultProvider(&http.Client{Timeout: 10 * time.Second}))
+	}
+	return providers
+}
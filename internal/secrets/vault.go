@@ -0,0 +1,72 @@
+package secrets
+
+import (
+	"VKR_gateway_service/internal/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VaultProvider reads one secret from the HashiCorp Vault KV v2 engine
+// over its HTTP API. The token is taken from the config on every read, so
+// a rotated VAULT_TOKEN_FILE applies.
+type VaultProvider struct {
+	client *http.Client
+}
+
+func NewVaultProvider(client *http.Client) *VaultProvider {
+	return &VaultProvider{client: client}
+}
+
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+type vaultResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// Secrets reads the latest version of the secret. Values must be strings.
+func (p *VaultProvider) Secrets(ctx context.Context, c *config.Config) (map[string]string, error) {
+	cfg := c.SecretsConfig.Vault
+	target := strings.TrimRight(cfg.Addr, "/") + "/v1/" +
+		url.PathEscape(strings.Trim(cfg.Mount, "/")) + "/data/" + strings.Trim(cfg.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", cfg.Token)
+	if cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", cfg.Namespace)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body vaultResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.Join(body.Errors, "; "))
+	}
+
+	values := make(map[string]string, len(body.Data.Data))
+	for key, raw := range body.Data.Data {
+		value, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("vault secret %s/%s: value of %s is not a string", cfg.Mount, cfg.Path, key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
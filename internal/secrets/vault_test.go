@@ -0,0 +1,222 @@
+package secrets
+
+import (
+	"VKR_gateway_service/internal/config"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeVault serves one KV v2 secret at /v1/secret/data/gateway to the
+// tokens in tokens.
+type fakeVault struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	tokens map[string]bool
+	data   map[string]interface{}
+	reads  int
+}
+
+func newFakeVault(t *testing.T, data map[string]interface{}, tokens ...string) *fakeVault {
+	v := &fakeVault{tokens: map[string]bool{}, data: data}
+	for _, token := range tokens {
+		v.tokens[token] = true
+	}
+	v.Server = httptest.NewServer(http.HandlerFunc(v.serve))
+	t.Cleanup(v.Close)
+	return v
+}
+
+func (v *fakeVault) serve(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.reads++
+	w.Header().Set("Content-Type", "application/json")
+	if !v.tokens[r.Header.Get("X-Vault-Token")] {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, `{"errors":["permission denied"]}`)
+		return
+	}
+	if r.Method != http.MethodGet || r.URL.Path != "/v1/secret/data/gateway" {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"errors":[]}`)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]interface{}{
+			"data":     v.data,
+			"metadata": map[string]interface{}{"version": v.reads},
+		},
+	})
+}
+
+func (v *fakeVault) set(key string, value interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.data[key] = value
+}
+
+func (v *fakeVault) revoke(token string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.tokens, token)
+}
+
+func (v *fakeVault) allow(token string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tokens[token] = true
+}
+
+func vaultConfig(addr, token string) *config.Config {
+	cfg := &config.Config{}
+	cfg.SecretsConfig.Vault = config.VaultConfig{Addr: addr + "/", Token: token, Mount: "/secret/", Path: "/gateway"}
+	return cfg
+}
+
+func TestVaultProviderReadsKVv2(t *testing.T) {
+	vault := newFakeVault(t, map[string]interface{}{"DB_PASSWORD": "p1", "SSO_CLIENT_SECRET": "s1"}, "t1")
+	var namespace string
+	inner := vault.Config.Handler
+	vault.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace = r.Header.Get("X-Vault-Namespace")
+		inner.ServeHTTP(w, r)
+	})
+
+	cfg := vaultConfig(vault.URL, "t1")
+	cfg.SecretsConfig.Vault.Namespace = "team"
+	got, err := NewVaultProvider(vault.Client()).Secrets(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"DB_PASSWORD": "p1", "SSO_CLIENT_SECRET": "s1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Secrets() = %v, want %v", got, want)
+	}
+	if namespace != "team" {
+		t.Fatalf("X-Vault-Namespace = %q", namespace)
+	}
+}
+
+func TestVaultProviderErrors(t *testing.T) {
+	vault := newFakeVault(t, map[string]interface{}{"DB_PASSWORD": "p1"}, "t1")
+	p := NewVaultProvider(vault.Client())
+
+	if _, err := p.Secrets(context.Background(), vaultConfig(vault.URL, "expired")); err == nil ||
+		!strings.Contains(err.Error(), "vault returned 403: permission denied") {
+		t.Fatalf("expired token: err = %v", err)
+	}
+
+	cfg := vaultConfig(vault.URL, "t1")
+	cfg.SecretsConfig.Vault.Path = "missing"
+	if _, err := p.Secrets(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "vault returned 404") {
+		t.Fatalf("missing secret: err = %v", err)
+	}
+
+	vault.set("DB_PORT", 5432)
+	if _, err := p.Secrets(context.Background(), vaultConfig(vault.URL, "t1")); err == nil || !strings.Contains(err.Error(), "DB_PORT is not a string") {
+		t.Fatalf("non-string value: err = %v", err)
+	}
+
+	garbage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		io.WriteString(w, "<html>proxy error</html>")
+	}))
+	defer garbage.Close()
+	if _, err := p.Secrets(context.Background(), vaultConfig(garbage.URL, "t1")); err == nil || !strings.Contains(err.Error(), "failed to decode") {
+		t.Fatalf("invalid body: err = %v", err)
+	}
+
+	vault.Close()
+	if _, err := p.Secrets(context.Background(), vaultConfig(vault.URL, "t1")); err == nil || !strings.Contains(err.Error(), "vault request failed") {
+		t.Fatalf("vault down: err = %v", err)
+	}
+}
+
+// TestManagerReloadsRotatedVaultSecrets rotates a secret in Vault and the
+// Vault token in VAULT_TOKEN_FILE, as an agent renewing the token would.
+func TestManagerReloadsRotatedVaultSecrets(t *testing.T) {
+	vault := newFakeVault(t, map[string]interface{}{"DB_PASSWORD": "p1"}, "t1")
+	tokenFile := filepath.Join(t.TempDir(), "vault-token")
+	writeFile(t, tokenFile, "t1\n")
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_TOKEN_FILE", tokenFile)
+
+	cfg := vaultConfig(vault.URL, "")
+	cfg.PostgresConfig.Password = "initial"
+	store := config.NewStore("", cfg)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	m := NewManager(store, []Provider{FileProvider{}, NewVaultProvider(vault.Client())}, logger)
+	var notified [][]string
+	m.OnChange(func(cfg *config.Config, changed []string) {
+		notified = append(notified, changed)
+	})
+
+	refresh := func() []string {
+		t.Helper()
+		changed, err := m.Refresh(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return changed
+	}
+
+	if changed := refresh(); !slices.Contains(changed, "postgres.password") || store.Config().PostgresConfig.Password != "p1" {
+		t.Fatalf("first refresh changed %v, password %q", changed, store.Config().PostgresConfig.Password)
+	}
+	if changed := refresh(); len(changed) != 0 {
+		t.Fatalf("refresh without rotation changed %v", changed)
+	}
+
+	// The secret is rotated in Vault
+	vault.set("DB_PASSWORD", "p2")
+	if changed := refresh(); !reflect.DeepEqual(changed, []string{"postgres.password"}) || store.Config().PostgresConfig.Password != "p2" {
+		t.Fatalf("refresh after rotation changed %v, password %q", changed, store.Config().PostgresConfig.Password)
+	}
+
+	// The token is renewed: the new one is written to the file and the old
+	// one revoked, the next refresh reads Vault with the new token
+	vault.allow("t2")
+	vault.revoke("t1")
+	writeFile(t, tokenFile, "t2\n")
+	vault.set("DB_PASSWORD", "p3")
+	refresh()
+	if got := store.Config().PostgresConfig.Password; got != "p3" {
+		t.Fatalf("password after token renewal = %q, want p3", got)
+	}
+	if got := store.Config().SecretsConfig.Vault.Token; got != "t2" {
+		t.Fatalf("Vault token = %q, want t2", got)
+	}
+
+	// An expired token fails the refresh and keeps the current secrets
+	vault.revoke("t2")
+	vault.set("DB_PASSWORD", "p4")
+	if _, err := m.Refresh(context.Background()); err == nil || !strings.Contains(err.Error(), "vault secrets") {
+		t.Fatalf("refresh with expired token: err = %v", err)
+	}
+	if got := store.Config().PostgresConfig.Password; got != "p3" {
+		t.Fatalf("password after failed refresh = %q, want p3 kept", got)
+	}
+	if len(notified) != 3 {
+		t.Fatalf("listeners notified %d times, want 3: %v", len(notified), notified)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
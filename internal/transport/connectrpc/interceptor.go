@@ -0,0 +1,234 @@
+package connectrpc
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const auditWriteTimeout = 5 * time.Second
+
+// procedureRoles lists procedures that need more than RoleUser. Writes into
+// the shared index are limited to curators, as on the REST routes.
+var procedureRoles = map[string]domain.Role{
+	pb.SemanticService_AddPaper_FullMethodName:       domain.RoleCurator,
+	pb.SemanticService_AddAuthor_FullMethodName:      domain.RoleCurator,
+	pb.SemanticService_AddInstitution_FullMethodName: domain.RoleCurator,
+}
+
+// procedureScopes is the scope an API key needs for each procedure.
+// Procedures missing here are not available with API keys.
+var procedureScopes = map[string]domain.Scope{
+	pb.SemanticService_GetInstitutions_FullMethodName: domain.ScopePapersRead,
+	pb.SemanticService_GetAuthors_FullMethodName:      domain.ScopePapersRead,
+	pb.SemanticService_GetAuthorPapers_FullMethodName: domain.ScopePapersRead,
+	pb.SemanticService_GetPaper_FullMethodName:        domain.ScopePapersRead,
+	pb.SemanticService_GetPapers_FullMethodName:       domain.ScopePapersRead,
+	pb.SemanticService_AddPaper_FullMethodName:        domain.ScopePapersWrite,
+	pb.SemanticService_AddAuthor_FullMethodName:       domain.ScopePapersWrite,
+	pb.SemanticService_AddInstitution_FullMethodName:  domain.ScopePapersWrite,
+	pb.SemanticService_GetUserChats_FullMethodName:    domain.ScopeChatsRead,
+	pb.SemanticService_GetChatHistory_FullMethodName:  domain.ScopeChatsRead,
+	pb.SemanticService_CreateNewChat_FullMethodName:   domain.ScopeChatsWrite,
+	pb.SemanticService_UpdateChat_FullMethodName:      domain.ScopeChatsWrite,
+	pb.SemanticService_DeleteChat_FullMethodName:      domain.ScopeChatsWrite,
+	pb.SemanticService_SearchPaper_FullMethodName:     domain.ScopeChatsWrite,
+}
+
+// auditedProcedures change data and are written to the audit log.
+var auditedProcedures = map[string]bool{
+	pb.SemanticService_AddPaper_FullMethodName:       true,
+	pb.SemanticService_AddAuthor_FullMethodName:      true,
+	pb.SemanticService_AddInstitution_FullMethodName: true,
+	pb.SemanticService_CreateNewChat_FullMethodName:  true,
+	pb.SemanticService_UpdateChat_FullMethodName:     true,
+	pb.SemanticService_DeleteChat_FullMethodName:     true,
+	pb.SemanticService_SearchPaper_FullMethodName:    true,
+}
+
+// principalFrom returns the caller stored by the interceptor or the invoker.
+func principalFrom(ctx context.Context) *auth.Principal {
+	p, _ := auth.FromContext(ctx)
+	return p
+}
+
+// callerID returns the id of the caller, 0 if there is none.
+func callerID(ctx context.Context) int64 {
+	if p := principalFrom(ctx); p != nil {
+		return p.UserID
+	}
+	return 0
+}
+
+// authorize checks that the caller has the role and, for API keys, the
+// scope the procedure requires.
+func authorize(procedure string, p *auth.Principal) error {
+	if min, ok := procedureRoles[procedure]; ok && !p.HasRole(min) {
+		return connect.NewError(connect.CodePermissionDenied, errors.New("insufficient role, "+string(min)+" required"))
+	}
+	if p.Method == auth.MethodAPIKey {
+		scope, ok := procedureScopes[procedure]
+		if !ok {
+			return connect.NewError(connect.CodePermissionDenied, errors.New("not available with an API key"))
+		}
+		if !p.HasScope(scope) {
+			return connect.NewError(connect.CodePermissionDenied, errors.New("API key lacks scope "+string(scope)))
+		}
+	}
+	return nil
+}
+
+// newAuthInterceptor validates the bearer token via SSO or the API key,
+// enforces procedure roles and scopes and records data-changing calls in
+// the audit log.
+func newAuthInterceptor(a *app.App) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			procedure := req.Spec().Procedure
+			requestID := middlewares.NormalizeRequestID(req.Header().Get(middlewares.RequestIDHeader))
+
+			var principal *auth.Principal
+			resp, err := func() (connect.AnyResponse, error) {
+				token := req.Header().Get("Authorization")
+				if apiKey := req.Header().Get(middlewares.APIKeyHeader); apiKey != "" {
+					if token != "" {
+						return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("send either Authorization or "+middlewares.APIKeyHeader+", not both"))
+					}
+					p, statusCode, err := middlewares.AuthenticateAPIKey(ctx, a, apiKey)
+					if err != nil {
+						return nil, connect.NewError(httpToCode(statusCode), err)
+					}
+					principal = p
+				} else {
+					if !strings.HasPrefix(token, "Bearer ") {
+						return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("token required"))
+					}
+					p, statusCode, err := middlewares.Authenticate(ctx, a, token)
+					if err != nil {
+						return nil, connect.NewError(httpToCode(statusCode), err)
+					}
+					principal = p
+				}
+				if err := authorize(procedure, principal); err != nil {
+					return nil, err
+				}
+				return next(auth.WithPrincipal(ctx, principal), req)
+			}()
+
+			var actorID int64
+			if principal != nil {
+				actorID = principal.UserID
+			}
+			if err != nil && a.Logger != nil {
+				a.Logger.WithError(err).WithFields(map[string]interface{}{
+					"procedure":  procedure,
+					"user_id":    actorID,
+					"request_id": requestID,
+				}).Warn("RPC failed")
+			}
+			if auditedProcedures[procedure] {
+				audit(ctx, a, req, actorID, requestID, err)
+			}
+			if err == nil {
+				resp.Header().Set(middlewares.RequestIDHeader, requestID)
+			}
+			return resp, err
+		}
+	}
+}
+
+func audit(ctx context.Context, a *app.App, req connect.AnyRequest, actorID int64, requestID string, callErr error) {
+	if a.Audit == nil {
+		return
+	}
+	procedure := req.Spec().Procedure
+	entry := &domain.AuditEntry{
+		ActorID:    actorID,
+		Action:     "RPC " + procedure,
+		TargetType: "rpc",
+		RequestID:  requestID,
+		ClientIP:   clientIP(req.Peer().Addr),
+		Outcome:    domain.AuditOutcomeSuccess,
+		StatusCode: http.StatusOK,
+	}
+	if msg, ok := req.Any().(proto.Message); ok {
+		if payload, err := protojson.Marshal(msg); err == nil {
+			entry.Payload = string(payload)
+		}
+	}
+	if callErr != nil {
+		code := connect.CodeOf(callErr)
+		entry.StatusCode = codeToHTTP(code)
+		entry.Outcome = domain.AuditOutcomeFailure
+		if code == connect.CodeUnauthenticated || code == connect.CodePermissionDenied {
+			entry.Outcome = domain.AuditOutcomeDenied
+		}
+	}
+
+	wctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+	if err := a.Audit.CreateAuditEntry(wctx, entry); err != nil {
+		a.Logger.WithError(err).WithFields(map[string]interface{}{
+			"action":     entry.Action,
+			"actor_id":   entry.ActorID,
+			"request_id": entry.RequestID,
+		}).Error("Failed to write audit entry")
+	}
+}
+
+func clientIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func httpToCode(statusCode int) connect.Code {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		return connect.CodePermissionDenied
+	case http.StatusTooManyRequests:
+		return connect.CodeResourceExhausted
+	case http.StatusBadRequest:
+		return connect.CodeInvalidArgument
+	default:
+		return connect.CodeUnavailable
+	}
+}
+
+func codeToHTTP(code connect.Code) int {
+	switch code {
+	case connect.CodeInvalidArgument, connect.CodeFailedPrecondition, connect.CodeOutOfRange:
+		return http.StatusBadRequest
+	case connect.CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case connect.CodePermissionDenied:
+		return http.StatusForbidden
+	case connect.CodeNotFound:
+		return http.StatusNotFound
+	case connect.CodeAlreadyExists, connect.CodeAborted:
+		return http.StatusConflict
+	case connect.CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case connect.CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case connect.CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,77 @@
+package connectrpc
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+)
+
+type unaryMethod func(ctx context.Context, req proto.Message) (proto.Message, error)
+
+// Invoker calls SemanticService procedures in process for transports that
+// authenticate the caller themselves, such as the transcoded REST routes.
+// Ownership checks and chat events are the same as for Connect clients.
+type Invoker struct {
+	methods map[string]unaryMethod
+}
+
+func NewInvoker(a *app.App) *Invoker {
+	s := &semanticServer{a: a}
+	return &Invoker{methods: map[string]unaryMethod{
+		pb.SemanticService_GetInstitutions_FullMethodName: unary(s.GetInstitutions),
+		pb.SemanticService_AddInstitution_FullMethodName:  unary(s.AddInstitution),
+		pb.SemanticService_GetAuthors_FullMethodName:      unary(s.GetAuthors),
+		pb.SemanticService_AddAuthor_FullMethodName:       unary(s.AddAuthor),
+		pb.SemanticService_GetChatHistory_FullMethodName:  unary(s.GetChatHistory),
+		pb.SemanticService_CreateNewChat_FullMethodName:   unary(s.CreateNewChat),
+		pb.SemanticService_UpdateChat_FullMethodName:      unary(s.UpdateChat),
+		pb.SemanticService_DeleteChat_FullMethodName:      unary(s.DeleteChat),
+		pb.SemanticService_GetUserChats_FullMethodName:    unary(s.GetUserChats),
+		pb.SemanticService_GetAuthorPapers_FullMethodName: unary(s.GetAuthorPapers),
+		pb.SemanticService_SearchPaper_FullMethodName:     unary(s.SearchPaper),
+		pb.SemanticService_AddPaper_FullMethodName:        unary(s.AddPaper),
+		pb.SemanticService_GetPaper_FullMethodName:        unary(s.GetPaper),
+		pb.SemanticService_GetPapers_FullMethodName:       unary(s.GetPapers),
+	}}
+}
+
+// Invoke runs procedure on behalf of an authenticated principal. req must be
+// the procedure's input message; errors are *connect.Error.
+func (i *Invoker) Invoke(ctx context.Context, procedure string, p *auth.Principal, req proto.Message) (proto.Message, error) {
+	method, ok := i.methods[procedure]
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("procedure %s is not implemented", procedure))
+	}
+	if p == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("user is not authenticated"))
+	}
+	if err := authorize(procedure, p); err != nil {
+		return nil, err
+	}
+	return method(auth.WithPrincipal(ctx, p), req)
+}
+
+// HTTPStatus returns the HTTP status matching the code of err.
+func HTTPStatus(err error) int {
+	return codeToHTTP(connect.CodeOf(err))
+}
+
+func unary[Req, Res any](fn func(context.Context, *connect.Request[Req]) (*connect.Response[Res], error)) unaryMethod {
+	return func(ctx context.Context, msg proto.Message) (proto.Message, error) {
+		req, ok := any(msg).(*Req)
+		if !ok {
+			return nil, connect.NewError(connect.CodeInternal, errors.New("unexpected request message"))
+		}
+		resp, err := fn(ctx, connect.NewRequest(req))
+		if err != nil {
+			return nil, err
+		}
+		return any(resp.Msg).(proto.Message), nil
+	}
+}
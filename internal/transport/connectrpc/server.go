@@ -0,0 +1,281 @@
+// Package connectrpc serves the SemanticService API to clients speaking
+// Connect, gRPC-Web or gRPC. Every call is authenticated and checked the same
+// way as the REST routes before it is forwarded to the AI service.
+package connectrpc
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/events"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	ServiceName    = "semantic.SemanticService"
+	publishTimeout = 2 * time.Second
+	// Most IDs accepted by one GetPapers call
+	maxPapersPerCall = 100
+)
+
+type semanticServer struct {
+	a *app.App
+}
+
+// NewHandler returns the mount path and handler of the public SemanticService.
+func NewHandler(a *app.App) (string, http.Handler) {
+	s := &semanticServer{a: a}
+	opts := connect.WithHandlerOptions(
+		connect.WithInterceptors(newAuthInterceptor(a)),
+		connect.WithReadMaxBytes(int(a.Config().HttpServerConfig.MaxBodySize)),
+	)
+	service := pb.File_service_proto.Services().ByName("SemanticService")
+	schema := func(name string) connect.HandlerOption {
+		return connect.WithSchema(service.Methods().ByName(protoreflect.Name(name)))
+	}
+
+	mux := http.NewServeMux()
+	handle := func(procedure string, h http.Handler) { mux.Handle(procedure, h) }
+	handle(pb.SemanticService_GetInstitutions_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_GetInstitutions_FullMethodName, s.GetInstitutions, opts, schema("GetInstitutions")))
+	handle(pb.SemanticService_AddInstitution_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_AddInstitution_FullMethodName, s.AddInstitution, opts, schema("AddInstitution")))
+	handle(pb.SemanticService_GetAuthors_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_GetAuthors_FullMethodName, s.GetAuthors, opts, schema("GetAuthors")))
+	handle(pb.SemanticService_AddAuthor_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_AddAuthor_FullMethodName, s.AddAuthor, opts, schema("AddAuthor")))
+	handle(pb.SemanticService_GetChatHistory_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_GetChatHistory_FullMethodName, s.GetChatHistory, opts, schema("GetChatHistory")))
+	handle(pb.SemanticService_CreateNewChat_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_CreateNewChat_FullMethodName, s.CreateNewChat, opts, schema("CreateNewChat")))
+	handle(pb.SemanticService_UpdateChat_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_UpdateChat_FullMethodName, s.UpdateChat, opts, schema("UpdateChat")))
+	handle(pb.SemanticService_DeleteChat_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_DeleteChat_FullMethodName, s.DeleteChat, opts, schema("DeleteChat")))
+	handle(pb.SemanticService_GetUserChats_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_GetUserChats_FullMethodName, s.GetUserChats, opts, schema("GetUserChats")))
+	handle(pb.SemanticService_GetAuthorPapers_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_GetAuthorPapers_FullMethodName, s.GetAuthorPapers, opts, schema("GetAuthorPapers")))
+	handle(pb.SemanticService_SearchPaper_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_SearchPaper_FullMethodName, s.SearchPaper, opts, schema("SearchPaper")))
+	handle(pb.SemanticService_AddPaper_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_AddPaper_FullMethodName, s.AddPaper, opts, schema("AddPaper")))
+	handle(pb.SemanticService_GetPaper_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_GetPaper_FullMethodName, s.GetPaper, opts, schema("GetPaper")))
+	handle(pb.SemanticService_GetPapers_FullMethodName, connect.NewUnaryHandler(pb.SemanticService_GetPapers_FullMethodName, s.GetPapers, opts, schema("GetPapers")))
+	return "/" + ServiceName + "/", mux
+}
+
+func (s *semanticServer) GetInstitutions(ctx context.Context, req *connect.Request[pb.InstitutionReq]) (*connect.Response[pb.InstitutionsResp], error) {
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetInstitutions(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) AddInstitution(ctx context.Context, req *connect.Request[pb.Institution]) (*connect.Response[pb.ErrorResponse], error) {
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.AddInstitution(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) GetAuthors(ctx context.Context, req *connect.Request[pb.AuthorReq]) (*connect.Response[pb.AuthorsResp], error) {
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetAuthors(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) AddAuthor(ctx context.Context, req *connect.Request[pb.Author]) (*connect.Response[pb.ErrorResponse], error) {
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.AddAuthor(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) GetAuthorPapers(ctx context.Context, req *connect.Request[pb.AuthorPaperReq]) (*connect.Response[pb.PapersResponse], error) {
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetAuthorPapers(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) GetPaper(ctx context.Context, req *connect.Request[pb.PaperReq]) (*connect.Response[pb.PaperDetail], error) {
+	if req.Msg.GetID() == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("ID is required"))
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetPaper(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) GetPapers(ctx context.Context, req *connect.Request[pb.PapersReq]) (*connect.Response[pb.PaperDetails], error) {
+	if n := len(req.Msg.GetIDs()); n > maxPapersPerCall {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("at most %d IDs are allowed, got %d", maxPapersPerCall, n))
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetPapers(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) AddPaper(ctx context.Context, req *connect.Request[pb.AddRequest]) (*connect.Response[pb.ErrorResponse], error) {
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.AddPaper(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) GetUserChats(ctx context.Context, req *connect.Request[pb.UserChatsReq]) (*connect.Response[pb.ChatsResp], error) {
+	userID, err := ownUserID(ctx, req.Msg.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetUserChats(rctx, &pb.UserChatsReq{UserId: userID})
+	return respond(resp, err)
+}
+
+func (s *semanticServer) CreateNewChat(ctx context.Context, req *connect.Request[pb.Chat]) (*connect.Response[pb.ChatResp], error) {
+	userID, err := ownUserID(ctx, req.Msg.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.CreateNewChat(rctx, &pb.Chat{UserId: userID, Title: req.Msg.GetTitle()})
+	if err == nil && resp.GetChat() != nil {
+		s.publish(ctx, events.ChatCreated, userID, resp.GetChat().GetChatId(), presenters.MapChat(resp.GetChat()))
+	}
+	return respond(resp, err)
+}
+
+func (s *semanticServer) GetChatHistory(ctx context.Context, req *connect.Request[pb.HistoryReq]) (*connect.Response[pb.HistoryResp], error) {
+	if _, err := s.ownChat(ctx, callerID(ctx), req.Msg.GetChatId()); err != nil {
+		return nil, err
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetChatHistory(rctx, req.Msg)
+	return respond(resp, err)
+}
+
+func (s *semanticServer) UpdateChat(ctx context.Context, req *connect.Request[pb.UpdateChatReq]) (*connect.Response[pb.ChatResp], error) {
+	userID, err := ownUserID(ctx, req.Msg.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ownChat(ctx, userID, req.Msg.GetChatId()); err != nil {
+		return nil, err
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.UpdateChat(rctx, &pb.UpdateChatReq{ChatId: req.Msg.GetChatId(), UserId: userID, Title: req.Msg.GetTitle()})
+	if err == nil && resp.GetChat() != nil {
+		s.publish(ctx, events.ChatUpdated, userID, req.Msg.GetChatId(), presenters.MapChat(resp.GetChat()))
+	}
+	return respond(resp, err)
+}
+
+func (s *semanticServer) DeleteChat(ctx context.Context, req *connect.Request[pb.DeleteChatReq]) (*connect.Response[pb.ErrorResponse], error) {
+	userID, err := ownUserID(ctx, req.Msg.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ownChat(ctx, userID, req.Msg.GetChatId()); err != nil {
+		return nil, err
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.DeleteChat(rctx, &pb.DeleteChatReq{ChatId: req.Msg.GetChatId(), UserId: userID})
+	if err == nil && resp.GetError() == "" {
+		s.publish(ctx, events.ChatDeleted, userID, req.Msg.GetChatId(), nil)
+	}
+	return respond(resp, err)
+}
+
+func (s *semanticServer) SearchPaper(ctx context.Context, req *connect.Request[pb.SearchRequest]) (*connect.Response[pb.PapersResponse], error) {
+	userID := callerID(ctx)
+	if _, err := s.ownChat(ctx, userID, req.Msg.GetChatId()); err != nil {
+		return nil, err
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.SearchPaper(rctx, req.Msg)
+	if err == nil {
+		s.publish(ctx, events.ChatHistoryCreated, userID, req.Msg.GetChatId(), presenters.ChatHistoryMessage{
+			SearchQuery: req.Msg.GetInputData(),
+			CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+			Papers:      presenters.MapPapers(resp.GetPapers()),
+		})
+	}
+	return respond(resp, err)
+}
+
+// ownUserID returns the caller's id. A user_id in the request is accepted
+// only if it is the caller's own id.
+func ownUserID(ctx context.Context, requested int64) (int64, error) {
+	userID := callerID(ctx)
+	if requested != 0 && requested != userID {
+		return 0, connect.NewError(connect.CodePermissionDenied, errors.New("user_id does not match token"))
+	}
+	return userID, nil
+}
+
+// ownChat returns the chat if it belongs to the user.
+func (s *semanticServer) ownChat(ctx context.Context, userID, chatID int64) (*pb.Chat, error) {
+	if chatID <= 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("chat_id must be a positive integer"))
+	}
+	rctx, cancel := s.rpcContext(ctx)
+	defer cancel()
+	resp, err := s.a.AI.GetUserChats(rctx, &pb.UserChatsReq{UserId: userID})
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+	for _, chat := range resp.GetChats() {
+		if chat.GetChatId() == chatID {
+			return chat, nil
+		}
+	}
+	return nil, connect.NewError(connect.CodePermissionDenied, errors.New("chat access denied"))
+}
+
+func (s *semanticServer) rpcContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if cfg := s.a.Config(); cfg != nil && cfg.GRPCTimeout > 0 {
+		return context.WithTimeout(ctx, cfg.GRPCTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (s *semanticServer) publish(ctx context.Context, eventType string, userID, chatID int64, data interface{}) {
+	if s.a.Events == nil {
+		return
+	}
+	ev, err := events.NewEvent(eventType, userID, chatID, data)
+	if err == nil {
+		pctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+		defer cancel()
+		err = s.a.Events.Publish(pctx, ev)
+	}
+	if err != nil {
+		s.a.Logger.WithError(err).WithFields(map[string]interface{}{
+			"event":   eventType,
+			"user_id": userID,
+			"chat_id": chatID,
+		}).Error("Failed to publish chat event")
+	}
+}
+
+func respond[T any](msg *T, err error) (*connect.Response[T], error) {
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+	return connect.NewResponse(msg), nil
+}
+
+// upstreamError keeps the gRPC status of the AI service; codes are shared by gRPC and Connect.
+func upstreamError(err error) error {
+	if s, ok := status.FromError(err); ok {
+		return connect.NewError(connect.Code(s.Code()), errors.New(s.Message()))
+	}
+	return connect.NewError(connect.CodeUnavailable, err)
+}
@@ -0,0 +1,228 @@
+package connectrpc
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/domain"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// validTokens are the bearer tokens the fake SSO accepts and its validate
+// response for each.
+var validTokens = map[string]string{
+	"Bearer user-token":    `{"user_id": 1}`,
+	"Bearer curator-token": `{"user_id": 2, "roles": ["curator"]}`,
+}
+
+func newFakeSSO(t *testing.T) *httptest.Server {
+	sso := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := validTokens[r.Header.Get("Authorization")]
+		if r.URL.Path != "/api/auth/validate" || !ok {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(sso.Close)
+	return sso
+}
+
+// fakeAI serves chat 10 of user 1 and chat 20 of user 2 and records the
+// methods called.
+type fakeAI struct {
+	pb.SemanticServiceClient
+
+	mu    sync.Mutex
+	calls []string
+}
+
+var chatOwners = map[int64]int64{10: 1, 20: 2}
+
+func (f *fakeAI) record(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, method)
+}
+
+func (f *fakeAI) called(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, m := range f.calls {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeAI) GetUserChats(_ context.Context, in *pb.UserChatsReq, _ ...grpc.CallOption) (*pb.ChatsResp, error) {
+	f.record("GetUserChats")
+	out := &pb.ChatsResp{}
+	for chatID, owner := range chatOwners {
+		if owner == in.GetUserId() {
+			out.Chats = append(out.Chats, &pb.Chat{ChatId: chatID, UserId: owner})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAI) GetChatHistory(context.Context, *pb.HistoryReq, ...grpc.CallOption) (*pb.HistoryResp, error) {
+	f.record("GetChatHistory")
+	return &pb.HistoryResp{}, nil
+}
+
+func (f *fakeAI) DeleteChat(context.Context, *pb.DeleteChatReq, ...grpc.CallOption) (*pb.ErrorResponse, error) {
+	f.record("DeleteChat")
+	return &pb.ErrorResponse{}, nil
+}
+
+func (f *fakeAI) AddPaper(context.Context, *pb.AddRequest, ...grpc.CallOption) (*pb.ErrorResponse, error) {
+	f.record("AddPaper")
+	return &pb.ErrorResponse{}, nil
+}
+
+func newTestApp(t *testing.T, ai pb.SemanticServiceClient) *app.App {
+	t.Helper()
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SSO_HTTP_URL = newFakeSSO(t).URL
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, AI: ai}
+}
+
+// newTestServer serves NewHandler and returns its URL.
+func newTestServer(t *testing.T, a *app.App) string {
+	t.Helper()
+	path, h := NewHandler(a)
+	mux := http.NewServeMux()
+	mux.Handle(path, h)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// call invokes procedure with token as the Authorization header.
+func call[Req, Res any](t *testing.T, url, procedure, token string, msg *Req) error {
+	t.Helper()
+	req := connect.NewRequest(msg)
+	if token != "" {
+		req.Header().Set("Authorization", token)
+	}
+	_, err := connect.NewClient[Req, Res](http.DefaultClient, url+procedure).CallUnary(context.Background(), req)
+	return err
+}
+
+func TestUnauthenticatedCallsAreRejected(t *testing.T) {
+	ai := &fakeAI{}
+	url := newTestServer(t, newTestApp(t, ai))
+
+	for _, token := range []string{"", "user-token", "Bearer expired-token"} {
+		err := call[pb.UserChatsReq, pb.ChatsResp](t, url, pb.SemanticService_GetUserChats_FullMethodName, token, &pb.UserChatsReq{})
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("token %q: err = %v, want Unauthenticated", token, err)
+		}
+	}
+	if len(ai.calls) != 0 {
+		t.Fatalf("AI service called without authentication: %v", ai.calls)
+	}
+}
+
+func TestCallsAreLimitedToOwnData(t *testing.T) {
+	ai := &fakeAI{}
+	url := newTestServer(t, newTestApp(t, ai))
+	const user = "Bearer user-token"
+
+	if err := call[pb.UserChatsReq, pb.ChatsResp](t, url, pb.SemanticService_GetUserChats_FullMethodName, user, &pb.UserChatsReq{}); err != nil {
+		t.Fatalf("own chats: %v", err)
+	}
+	if err := call[pb.UserChatsReq, pb.ChatsResp](t, url, pb.SemanticService_GetUserChats_FullMethodName, user, &pb.UserChatsReq{UserId: 1}); err != nil {
+		t.Fatalf("own chats with user_id: %v", err)
+	}
+	err := call[pb.UserChatsReq, pb.ChatsResp](t, url, pb.SemanticService_GetUserChats_FullMethodName, user, &pb.UserChatsReq{UserId: 2})
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("foreign user_id: err = %v, want PermissionDenied", err)
+	}
+	if n := ai.called("GetUserChats"); n != 2 {
+		t.Fatalf("GetUserChats called %d times, want 2", n)
+	}
+
+	if err := call[pb.HistoryReq, pb.HistoryResp](t, url, pb.SemanticService_GetChatHistory_FullMethodName, user, &pb.HistoryReq{ChatId: 10}); err != nil {
+		t.Fatalf("own chat history: %v", err)
+	}
+	err = call[pb.HistoryReq, pb.HistoryResp](t, url, pb.SemanticService_GetChatHistory_FullMethodName, user, &pb.HistoryReq{ChatId: 20})
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("foreign chat history: err = %v, want PermissionDenied", err)
+	}
+	err = call[pb.DeleteChatReq, pb.ErrorResponse](t, url, pb.SemanticService_DeleteChat_FullMethodName, user, &pb.DeleteChatReq{ChatId: 20})
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("foreign chat delete: err = %v, want PermissionDenied", err)
+	}
+	if ai.called("GetChatHistory") != 1 || ai.called("DeleteChat") != 0 {
+		t.Fatalf("AI calls = %v, foreign chats must not be forwarded", ai.calls)
+	}
+}
+
+func TestAddPaperNeedsCurator(t *testing.T) {
+	ai := &fakeAI{}
+	url := newTestServer(t, newTestApp(t, ai))
+	paper := &pb.AddRequest{ID: "10.1000/1", Title: "Paper"}
+
+	err := call[pb.AddRequest, pb.ErrorResponse](t, url, pb.SemanticService_AddPaper_FullMethodName, "Bearer user-token", paper)
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("AddPaper as user: err = %v, want PermissionDenied", err)
+	}
+	if n := ai.called("AddPaper"); n != 0 {
+		t.Fatalf("AddPaper forwarded %d times for a user", n)
+	}
+
+	if err := call[pb.AddRequest, pb.ErrorResponse](t, url, pb.SemanticService_AddPaper_FullMethodName, "Bearer curator-token", paper); err != nil {
+		t.Fatalf("AddPaper as curator: %v", err)
+	}
+	if n := ai.called("AddPaper"); n != 1 {
+		t.Fatalf("AddPaper forwarded %d times, want 1", n)
+	}
+}
+
+func TestInvokerChecksPrincipal(t *testing.T) {
+	ai := &fakeAI{}
+	invoker := NewInvoker(newTestApp(t, ai))
+	user := &auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleUser}}
+	curator := &auth.Principal{UserID: 2, Roles: []domain.Role{domain.RoleUser, domain.RoleCurator}}
+	key := &auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleUser}, Method: auth.MethodAPIKey, Scopes: []domain.Scope{domain.ScopePapersRead}}
+
+	ctx := context.Background()
+	paper := &pb.AddRequest{ID: "10.1000/1"}
+	if _, err := invoker.Invoke(ctx, pb.SemanticService_AddPaper_FullMethodName, nil, paper); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("no principal: err = %v, want Unauthenticated", err)
+	}
+	if _, err := invoker.Invoke(ctx, pb.SemanticService_AddPaper_FullMethodName, user, paper); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("AddPaper as user: err = %v, want PermissionDenied", err)
+	}
+	if _, err := invoker.Invoke(ctx, pb.SemanticService_GetChatHistory_FullMethodName, user, &pb.HistoryReq{ChatId: 20}); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("foreign chat: err = %v, want PermissionDenied", err)
+	}
+	if _, err := invoker.Invoke(ctx, pb.SemanticService_DeleteChat_FullMethodName, key, &pb.DeleteChatReq{ChatId: 10}); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("API key without chats:write: err = %v, want PermissionDenied", err)
+	}
+	if _, err := invoker.Invoke(ctx, pb.SemanticService_AddPaper_FullMethodName, curator, paper); err != nil {
+		t.Fatalf("AddPaper as curator: %v", err)
+	}
+	if ai.called("AddPaper") != 1 || ai.called("GetChatHistory") != 0 || ai.called("DeleteChat") != 0 {
+		t.Fatalf("AI calls = %v", ai.calls)
+	}
+}
@@ -0,0 +1,74 @@
+// Package graphql serves a read-only GraphQL view of the AI service for the
+// frontend to load a screen in one request.
+package graphql
+
+import (
+	"VKR_gateway_service/internal/app"
+	"context"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Schema struct {
+	app    *app.App
+	schema gql.Schema
+}
+
+func NewSchema(a *app.App) (*Schema, error) {
+	schema, err := newSchema()
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{app: a, schema: schema}, nil
+}
+
+// Execute runs the request on behalf of userID. executed is false when the
+// request was rejected before execution (syntax, validation or limits).
+func (s *Schema) Execute(ctx context.Context, userID int64, req Request) (result *gql.Result, executed bool) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &gql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+	if vr := gql.ValidateDocument(&s.schema, doc, nil); !vr.IsValid {
+		return &gql.Result{Errors: vr.Errors}, false
+	}
+	cfg := s.app.Config().GraphQLConfig
+	if err := checkLimits(measure(&s.schema, doc), cfg.MaxDepth, cfg.MaxComplexity); err != nil {
+		return &gql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+
+	ctx = context.WithValue(ctx, requestKey{}, &requestState{userID: userID, loaders: newLoaders(s.app)})
+	return gql.Execute(gql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	}), true
+}
+
+type requestKey struct{}
+
+type requestState struct {
+	userID  int64
+	loaders *loaders
+}
+
+func requestUserID(ctx context.Context) int64 {
+	return ctx.Value(requestKey{}).(*requestState).userID
+}
+
+func requestLoaders(ctx context.Context) *loaders {
+	return ctx.Value(requestKey{}).(*requestState).loaders
+}
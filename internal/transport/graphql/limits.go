@@ -0,0 +1,151 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// assumedListSize is the number of items a list field is expected to
+// return: the selections under a list are counted once per item.
+const assumedListSize = 10
+
+// queryCost holds the depth and complexity of an operation.
+type queryCost struct {
+	Depth      int
+	Complexity int
+}
+
+// measure computes the cost of every operation of a validated document.
+// Introspection fields are not counted: they never reach the AI service.
+func measure(schema *gql.Schema, doc *ast.Document) queryCost {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name != nil {
+			fragments[f.Name.Value] = f
+		}
+	}
+	m := &meter{schema: schema, fragments: fragments}
+
+	var total queryCost
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		var root *gql.Object
+		switch op.Operation {
+		case ast.OperationTypeQuery:
+			root = schema.QueryType()
+		case ast.OperationTypeMutation:
+			root = schema.MutationType()
+		}
+		cost := m.selectionSet(root, op.SelectionSet, nil)
+		total.Depth = max(total.Depth, cost.Depth)
+		total.Complexity = max(total.Complexity, cost.Complexity)
+	}
+	return total
+}
+
+type meter struct {
+	schema    *gql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+func (m *meter) selectionSet(parent *gql.Object, set *ast.SelectionSet, visiting map[string]bool) queryCost {
+	var out queryCost
+	if parent == nil || set == nil {
+		return out
+	}
+	for _, sel := range set.Selections {
+		var cost queryCost
+		switch s := sel.(type) {
+		case *ast.Field:
+			cost = m.field(parent, s, visiting)
+			out.Complexity += cost.Complexity
+		case *ast.InlineFragment:
+			cost = m.selectionSet(m.fragmentType(parent, s.TypeCondition), s.SelectionSet, visiting)
+			out.Complexity += cost.Complexity
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			f, ok := m.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			next := make(map[string]bool, len(visiting)+1)
+			for k := range visiting {
+				next[k] = true
+			}
+			next[name] = true
+			cost = m.selectionSet(m.fragmentType(parent, f.TypeCondition), f.SelectionSet, next)
+			out.Complexity += cost.Complexity
+		}
+		out.Depth = max(out.Depth, cost.Depth)
+	}
+	return out
+}
+
+func (m *meter) field(parent *gql.Object, f *ast.Field, visiting map[string]bool) queryCost {
+	name := f.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return queryCost{}
+	}
+	cost, ok := fieldCosts[parent.Name()+"."+name]
+	if !ok {
+		cost = 1
+	}
+	def, ok := parent.Fields()[name]
+	if !ok {
+		return queryCost{Depth: 1, Complexity: cost}
+	}
+	child := m.selectionSet(namedObject(def.Type), f.SelectionSet, visiting)
+	if isList(def.Type) {
+		child.Complexity *= assumedListSize
+	}
+	return queryCost{Depth: child.Depth + 1, Complexity: child.Complexity + cost}
+}
+
+func (m *meter) fragmentType(parent *gql.Object, cond *ast.Named) *gql.Object {
+	if cond == nil || cond.Name == nil {
+		return parent
+	}
+	if obj, ok := m.schema.Type(cond.Name.Value).(*gql.Object); ok {
+		return obj
+	}
+	return parent
+}
+
+func namedObject(t gql.Type) *gql.Object {
+	for {
+		switch v := t.(type) {
+		case *gql.NonNull:
+			t = v.OfType
+		case *gql.List:
+			t = v.OfType
+		case *gql.Object:
+			return v
+		default:
+			return nil
+		}
+	}
+}
+
+func isList(t gql.Type) bool {
+	if nn, ok := t.(*gql.NonNull); ok {
+		t = nn.OfType
+	}
+	_, ok := t.(*gql.List)
+	return ok
+}
+
+func checkLimits(cost queryCost, maxDepth, maxComplexity int) error {
+	if maxDepth > 0 && cost.Depth > maxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", cost.Depth, maxDepth)
+	}
+	if maxComplexity > 0 && cost.Complexity > maxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", cost.Complexity, maxComplexity)
+	}
+	return nil
+}
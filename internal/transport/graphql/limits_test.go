@@ -0,0 +1,56 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestMeasureCountsListFanOut(t *testing.T) {
+	schema, err := newSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		query      string
+		depth      int
+		complexity int
+	}{
+		{"object field", `{ chat(id: "1") { id title } }`, 2, 10 + 2},
+		{"list field", `{ chats { id title } }`, 2, 10 + 10*2},
+		{"nested lists", `{ chats { messages { papers { id } } } }`, 4, 10 + 10*(10+10*(1+10*1))},
+		{"fragment under a list", `{ authors(query: "a") { ...A } } fragment A on Author { id papers { title } }`, 3, 10 + 10*(1+10+10*1)},
+		{"introspection", `{ __schema { types { name } } }`, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := measure(&schema, doc)
+			if got.Depth != tt.depth || got.Complexity != tt.complexity {
+				t.Fatalf("measure() = %+v, want depth %d, complexity %d", got, tt.depth, tt.complexity)
+			}
+		})
+	}
+}
+
+func TestCheckLimitsRejectsWideQueries(t *testing.T) {
+	schema, err := newSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Few fields, but every chat loads its messages and every message its papers
+	doc, err := parser.Parse(parser.ParseParams{Source: `{ chats { messages { papers { id title abstract year bestOaLocation } } } }`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkLimits(measure(&schema, doc), 8, 5000); err == nil {
+		t.Fatal("checkLimits() accepted a query fanning out to thousands of fields")
+	}
+	if err := checkLimits(measure(&schema, doc), 8, 10000); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/pkg/dataloader"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/status"
+)
+
+// AI service calls in flight per batch
+const rpcParallelism = 8
+
+type authorPapersKey struct {
+	AuthorID int64
+	State    string
+}
+
+// loaders deduplicate and batch AI service calls made while resolving one request.
+type loaders struct {
+	chats        *dataloader.Loader[int64, []*pb.Chat]
+	history      *dataloader.Loader[int64, []*pb.ChatMessage]
+	authorPapers *dataloader.Loader[authorPapersKey, []*pb.PaperResponse]
+	authors      *dataloader.Loader[string, []*pb.Author]
+	institutions *dataloader.Loader[string, []*pb.Institution]
+}
+
+func newLoaders(a *app.App) *loaders {
+	return &loaders{
+		chats: dataloader.New(func(ctx context.Context, userIDs []int64) ([][]*pb.Chat, []error) {
+			return dataloader.ForEach(ctx, userIDs, rpcParallelism, func(ctx context.Context, userID int64) ([]*pb.Chat, error) {
+				rctx, cancel := rpcContext(ctx, a)
+				defer cancel()
+				resp, err := a.AI.GetUserChats(rctx, &pb.UserChatsReq{UserId: userID})
+				if err != nil {
+					return nil, rpcError(a, err, "GetUserChats")
+				}
+				return resp.GetChats(), nil
+			})
+		}),
+		history: dataloader.New(func(ctx context.Context, chatIDs []int64) ([][]*pb.ChatMessage, []error) {
+			return dataloader.ForEach(ctx, chatIDs, rpcParallelism, func(ctx context.Context, chatID int64) ([]*pb.ChatMessage, error) {
+				rctx, cancel := rpcContext(ctx, a)
+				defer cancel()
+				resp, err := a.AI.GetChatHistory(rctx, &pb.HistoryReq{ChatId: chatID})
+				if err != nil {
+					return nil, rpcError(a, err, "GetChatHistory")
+				}
+				return resp.GetChatMessages(), nil
+			})
+		}),
+		authorPapers: dataloader.New(func(ctx context.Context, keys []authorPapersKey) ([][]*pb.PaperResponse, []error) {
+			return dataloader.ForEach(ctx, keys, rpcParallelism, func(ctx context.Context, key authorPapersKey) ([]*pb.PaperResponse, error) {
+				rctx, cancel := rpcContext(ctx, a)
+				defer cancel()
+				resp, err := a.AI.GetAuthorPapers(rctx, &pb.AuthorPaperReq{Author_ID: key.AuthorID, State: key.State})
+				if err != nil {
+					return nil, rpcError(a, err, "GetAuthorPapers")
+				}
+				return resp.GetPapers(), nil
+			})
+		}),
+		authors: dataloader.New(func(ctx context.Context, queries []string) ([][]*pb.Author, []error) {
+			return dataloader.ForEach(ctx, queries, rpcParallelism, func(ctx context.Context, query string) ([]*pb.Author, error) {
+				rctx, cancel := rpcContext(ctx, a)
+				defer cancel()
+				resp, err := a.AI.GetAuthors(rctx, &pb.AuthorReq{Query: query})
+				if err != nil {
+					return nil, rpcError(a, err, "GetAuthors")
+				}
+				return resp.GetAuthors(), nil
+			})
+		}),
+		institutions: dataloader.New(func(ctx context.Context, queries []string) ([][]*pb.Institution, []error) {
+			return dataloader.ForEach(ctx, queries, rpcParallelism, func(ctx context.Context, query string) ([]*pb.Institution, error) {
+				rctx, cancel := rpcContext(ctx, a)
+				defer cancel()
+				resp, err := a.AI.GetInstitutions(rctx, &pb.InstitutionReq{Query: query})
+				if err != nil {
+					return nil, rpcError(a, err, "GetInstitutions")
+				}
+				return resp.GetInstitutions(), nil
+			})
+		}),
+	}
+}
+
+func rpcContext(ctx context.Context, a *app.App) (context.Context, context.CancelFunc) {
+	if cfg := a.Config(); cfg != nil && cfg.GRPCTimeout > 0 {
+		return context.WithTimeout(ctx, cfg.GRPCTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// rpcError logs the failure and returns the status message as the GraphQL error.
+func rpcError(a *app.App, err error, method string) error {
+	if a.Logger != nil {
+		a.Logger.WithError(err).WithField("method", method).Error("AI RPC failed")
+	}
+	if s, ok := status.FromError(err); ok {
+		return errors.New(s.Message())
+	}
+	return err
+}
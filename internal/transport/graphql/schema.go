@@ -0,0 +1,213 @@
+package graphql
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"fmt"
+	"strconv"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+// rpcFieldCost is the complexity of a field that calls the AI service, other fields cost 1.
+const rpcFieldCost = 10
+
+var fieldCosts = map[string]int{
+	"Query.chats":        rpcFieldCost,
+	"Query.chat":         rpcFieldCost,
+	"Query.authors":      rpcFieldCost,
+	"Query.institutions": rpcFieldCost,
+	"Chat.messages":      rpcFieldCost,
+	"Author.papers":      rpcFieldCost,
+}
+
+func newSchema() (gql.Schema, error) {
+	paperType := gql.NewObject(gql.ObjectConfig{
+		Name: "Paper",
+		Fields: gql.Fields{
+			"id":             stringField(func(p *pb.PaperResponse) string { return p.GetID() }),
+			"title":          stringField(func(p *pb.PaperResponse) string { return p.GetTitle() }),
+			"abstract":       stringField(func(p *pb.PaperResponse) string { return p.GetAbstract() }),
+			"year":           intField(func(p *pb.PaperResponse) int64 { return p.GetYear() }),
+			"bestOaLocation": stringField(func(p *pb.PaperResponse) string { return p.GetBestOaLocation() }),
+		},
+	})
+
+	messageType := gql.NewObject(gql.ObjectConfig{
+		Name: "ChatMessage",
+		Fields: gql.Fields{
+			"searchQuery": stringField(func(m *pb.ChatMessage) string { return m.GetSearchQuery() }),
+			"createdAt":   stringField(func(m *pb.ChatMessage) string { return m.GetCreatedAt() }),
+			"papers": &gql.Field{
+				Type: nonNullList(paperType),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					m, ok := p.Source.(*pb.ChatMessage)
+					if !ok {
+						return nil, nil
+					}
+					return m.GetPapers().GetPapers(), nil
+				},
+			},
+		},
+	})
+
+	chatType := gql.NewObject(gql.ObjectConfig{
+		Name: "Chat",
+		Fields: gql.Fields{
+			"id":        idField(func(c *pb.Chat) int64 { return c.GetChatId() }),
+			"userId":    idField(func(c *pb.Chat) int64 { return c.GetUserId() }),
+			"title":     stringField(func(c *pb.Chat) string { return c.GetTitle() }),
+			"updatedAt": stringField(func(c *pb.Chat) string { return c.GetUpdatedAt() }),
+			"messages": &gql.Field{
+				Type: nonNullList(messageType),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					c, ok := p.Source.(*pb.Chat)
+					if !ok {
+						return nil, nil
+					}
+					// Chats are only reachable through the user's own chat list
+					thunk := requestLoaders(p.Context).history.Load(p.Context, c.GetChatId())
+					return func() (interface{}, error) { return thunk() }, nil
+				},
+			},
+		},
+	})
+
+	authorType := gql.NewObject(gql.ObjectConfig{
+		Name: "Author",
+		Fields: gql.Fields{
+			"id":         idField(func(a *pb.Author) int64 { return a.GetAuthorId() }),
+			"firstName":  stringField(func(a *pb.Author) string { return a.GetFirstName() }),
+			"lastName":   stringField(func(a *pb.Author) string { return a.GetLastName() }),
+			"middleName": stringField(func(a *pb.Author) string { return a.GetMiddleName() }),
+			"orcid":      stringField(func(a *pb.Author) string { return a.GetOrcid() }),
+			"papers": &gql.Field{
+				Type: nonNullList(paperType),
+				Args: gql.FieldConfigArgument{
+					"state": &gql.ArgumentConfig{Type: gql.String},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					author, ok := p.Source.(*pb.Author)
+					if !ok {
+						return nil, nil
+					}
+					state, _ := p.Args["state"].(string)
+					thunk := requestLoaders(p.Context).authorPapers.Load(p.Context, authorPapersKey{AuthorID: author.GetAuthorId(), State: state})
+					return func() (interface{}, error) { return thunk() }, nil
+				},
+			},
+		},
+	})
+
+	institutionType := gql.NewObject(gql.ObjectConfig{
+		Name: "Institution",
+		Fields: gql.Fields{
+			"id":      idField(func(i *pb.Institution) int64 { return i.GetInstitutionId() }),
+			"name":    stringField(func(i *pb.Institution) string { return i.GetName() }),
+			"country": stringField(func(i *pb.Institution) string { return i.GetCountry() }),
+			"rorId":   stringField(func(i *pb.Institution) string { return i.GetRorId() }),
+			"gridId":  stringField(func(i *pb.Institution) string { return i.GetGridId() }),
+		},
+	})
+
+	queryType := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"chats": &gql.Field{
+				Type:        nonNullList(chatType),
+				Description: "Chats of the current user",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					thunk := requestLoaders(p.Context).chats.Load(p.Context, requestUserID(p.Context))
+					return func() (interface{}, error) { return thunk() }, nil
+				},
+			},
+			"chat": &gql.Field{
+				Type:        chatType,
+				Description: "Chat of the current user by id",
+				Args: gql.FieldConfigArgument{
+					"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					raw, _ := p.Args["id"].(string)
+					chatID, err := strconv.ParseInt(raw, 10, 64)
+					if err != nil || chatID <= 0 {
+						return nil, fmt.Errorf("id must be a positive integer")
+					}
+					thunk := requestLoaders(p.Context).chats.Load(p.Context, requestUserID(p.Context))
+					return func() (interface{}, error) {
+						chats, err := thunk()
+						if err != nil {
+							return nil, err
+						}
+						for _, c := range chats {
+							if c.GetChatId() == chatID {
+								return c, nil
+							}
+						}
+						return nil, nil
+					}, nil
+				},
+			},
+			"authors": &gql.Field{
+				Type:        nonNullList(authorType),
+				Description: "Search authors by name",
+				Args: gql.FieldConfigArgument{
+					"query": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					query, _ := p.Args["query"].(string)
+					thunk := requestLoaders(p.Context).authors.Load(p.Context, query)
+					return func() (interface{}, error) { return thunk() }, nil
+				},
+			},
+			"institutions": &gql.Field{
+				Type:        nonNullList(institutionType),
+				Description: "Search institutions by name",
+				Args: gql.FieldConfigArgument{
+					"query": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					query, _ := p.Args["query"].(string)
+					thunk := requestLoaders(p.Context).institutions.Load(p.Context, query)
+					return func() (interface{}, error) { return thunk() }, nil
+				},
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: queryType})
+}
+
+func nonNullList(t gql.Type) gql.Output {
+	return gql.NewNonNull(gql.NewList(gql.NewNonNull(t)))
+}
+
+func stringField[T any](get func(T) string) *gql.Field {
+	return &gql.Field{
+		Type: gql.NewNonNull(gql.String),
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			src, _ := p.Source.(T)
+			return get(src), nil
+		},
+	}
+}
+
+func intField[T any](get func(T) int64) *gql.Field {
+	return &gql.Field{
+		Type: gql.NewNonNull(gql.Int),
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			src, _ := p.Source.(T)
+			return get(src), nil
+		},
+	}
+}
+
+// idField exposes int64 ids as ID since GraphQL Int is limited to 32 bits.
+func idField[T any](get func(T) int64) *gql.Field {
+	return &gql.Field{
+		Type: gql.NewNonNull(gql.ID),
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			src, _ := p.Source.(T)
+			return strconv.FormatInt(get(src), 10), nil
+		},
+	}
+}
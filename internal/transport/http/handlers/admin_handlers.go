@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/events"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+// AdminGetUsers
+// @Summary List users
+// @Description List users known to the gateway with their roles (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} presenters.AdminUsersResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /admin/users [get]
+func AdminGetUsers(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.users.list", "user")
+	limit, offset, err := middlewares.ParsePage(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	users, err := a.Users.GetUsers(ctx.Request.Context(), limit, offset)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Get users failed")
+		return
+	}
+
+	out := presenters.AdminUsersResponse{
+		Users:  make([]presenters.AdminUser, 0, len(users)),
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, u := range users {
+		out.Users = append(out.Users, mapAdminUser(&u))
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// AdminSetUserRole
+// @Summary Set user role
+// @Description Assign a gateway role (admin, curator or user) to a user (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Param data body presenters.SetUserRoleRequest true "Role"
+// @Success 200 {object} presenters.AdminUser
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /admin/users/{user_id}/role [put]
+func AdminSetUserRole(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.user.set_role", "user")
+	userID, err := parsePathInt64(ctx, "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	var in presenters.SetUserRoleRequest
+	if err := bindJSON(ctx, &in); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	role, ok := domain.ParseRole(in.Role)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("role must be one of admin, curator, user")))
+		return
+	}
+
+	user, err := a.Users.SetUserRole(ctx.Request.Context(), userID, role)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Set user role failed")
+		return
+	}
+	render(ctx, http.StatusOK, mapAdminUser(user))
+}
+
+// AdminGetUserChats
+// @Summary Get chats of any user
+// @Description Get all chats of the given user (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} presenters.ChatsResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /admin/users/{user_id}/chats [get]
+func AdminGetUserChats(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.user.chats", "user")
+	userID, err := parsePathInt64(ctx, "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	resp, err := a.AI.GetUserChats(rctx, &pb.UserChatsReq{UserId: userID})
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithField("user_id", userID).Error("AI GetUserChats RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+
+	out := presenters.ChatsResponse{Chats: make([]presenters.ChatResponse, 0, len(resp.GetChats()))}
+	for _, chat := range resp.GetChats() {
+		out.Chats = append(out.Chats, presenters.MapChat(chat))
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// AdminGetChatHistory
+// @Summary Get history of any chat
+// @Description Get chat history by chat ID without ownership check (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param chat_id path int true "Chat ID"
+// @Success 200 {object} presenters.ChatHistoryResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /admin/chats/{chat_id}/history [get]
+func AdminGetChatHistory(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.chat.view", "chat")
+	chatID, err := parsePathInt64(ctx, "chat_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	resp, err := a.AI.GetChatHistory(rctx, &pb.HistoryReq{ChatId: chatID})
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithField("chat_id", chatID).Error("AI GetChatHistory RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+
+	render(ctx, http.StatusOK, presenters.ChatHistoryResponse{ChatMessages: mapChatMessages(resp.GetChatMessages())})
+}
+
+// AdminDeleteChat
+// @Summary Force delete chat
+// @Description Delete any chat. The owner user_id is required by the AI service (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param chat_id path int true "Chat ID"
+// @Param user_id query int true "Owner user ID"
+// @Success 200
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /admin/chats/{chat_id} [delete]
+func AdminDeleteChat(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.chat.delete", "chat")
+	chatID, err := parsePathInt64(ctx, "chat_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	ownerID, err := parseOptionalQueryInt64(ctx, "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	if ownerID == 0 {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("user_id of the chat owner is required")))
+		return
+	}
+	if !authorizeChatAccess(ctx, a, ownerID, chatID) {
+		return
+	}
+
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	resp, err := a.AI.DeleteChat(rctx, &pb.DeleteChatReq{ChatId: chatID, UserId: ownerID})
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithFields(map[string]interface{}{
+				"chat_id": chatID,
+				"user_id": ownerID,
+			}).Error("AI DeleteChat RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+	if resp.GetError() != "" {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("%s", resp.GetError())))
+		return
+	}
+	publishChatEvent(ctx, a, events.ChatDeleted, ownerID, chatID, nil)
+	ctx.Status(http.StatusOK)
+}
+
+func mapAdminUser(u *domain.User) presenters.AdminUser {
+	return presenters.AdminUser{
+		UserId:     u.ID,
+		Role:       string(u.Role),
+		CreatedAt:  u.CreatedAt.Format(time.RFC3339),
+		LastSeenAt: u.LastSeenAt.Format(time.RFC3339),
+	}
+}
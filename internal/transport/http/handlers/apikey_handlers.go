@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"VKR_gateway_service/pkg/securetoken"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxAPIKeyNameLength = 100
+	// Characters of the key kept in listings, the prefix included
+	apiKeyDisplayLength = len(domain.APIKeyPrefix) + 8
+	maxRotationGrace    = 7 * 24 * time.Hour
+)
+
+// CreateAPIKey
+// @Summary Create API key
+// @Description Create an API key for scripts and services, sent as the X-API-Key header. The key acts as its owner, limited to its scopes, and is returned only once. Admins may create keys for other users. API keys cannot manage keys.
+// @Tags keys
+// @Accept json
+// @Produce json
+// @Param data body presenters.CreateAPIKeyRequest true "Key options"
+// @Success 201 {object} presenters.APIKeyResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /keys [post]
+func CreateAPIKey(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "api_key.create", "api_key")
+	var in presenters.CreateAPIKeyRequest
+	if err := bindJSON(ctx, &in); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	ownerID, statusCode, err := apiKeyOwner(ctx, in.UserId)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len([]rune(name)) > maxAPIKeyNameLength {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("name must be 1-%d characters", maxAPIKeyNameLength)))
+		return
+	}
+	scopes, statusCode, err := parseScopes(ctx, in.Scopes)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	expiresAt, err := apiKeyExpiry(a, in.ExpiresInHours)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	authID, _ := authUserID(ctx)
+
+	raw, key, err := newAPIKey()
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Generate API key failed")
+		return
+	}
+	key.UserID = ownerID
+	key.Name = name
+	key.Scopes = scopes
+	key.CreatedBy = authID
+	key.ExpiresAt = &expiresAt
+	created, err := a.APIKeys.CreateAPIKey(ctx.Request.Context(), key)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Create API key failed")
+		return
+	}
+	middlewares.AuditTargets(ctx, fmt.Sprintf("key_id=%d", created.ID), fmt.Sprintf("user_id=%d", ownerID))
+	out := mapAPIKey(created)
+	out.Key = raw
+	ctx.Header("Cache-Control", "no-store")
+	render(ctx, http.StatusCreated, out)
+}
+
+// GetAPIKeys
+// @Summary Get API keys
+// @Description Get API keys of the current user including expired and revoked ones. Admins may pass user_id.
+// @Tags keys
+// @Produce json
+// @Param user_id query int false "Owner (admin only)"
+// @Success 200 {object} presenters.APIKeysResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /keys [get]
+func GetAPIKeys(ctx *gin.Context, a *app.App) {
+	userID, err := parseOptionalQueryInt64(ctx, "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	ownerID, statusCode, err := apiKeyOwner(ctx, userID)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	keys, err := a.APIKeys.GetUserAPIKeys(ctx.Request.Context(), ownerID)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Get API keys failed")
+		return
+	}
+	out := presenters.APIKeysResponse{Keys: make([]presenters.APIKeyResponse, 0, len(keys))}
+	for i := range keys {
+		out.Keys = append(out.Keys, mapAPIKey(&keys[i]))
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// RotateAPIKey
+// @Summary Rotate API key
+// @Description Issue a new key with the same name and scopes. The old key stops working after grace_hours (at once by default). The new key is returned only once.
+// @Tags keys
+// @Accept json
+// @Produce json
+// @Param key_id path int true "Key ID"
+// @Param user_id query int false "Owner (admin only)"
+// @Param data body presenters.RotateAPIKeyRequest false "Rotation options"
+// @Success 201 {object} presenters.APIKeyResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /keys/{key_id}/rotate [post]
+func RotateAPIKey(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "api_key.rotate", "api_key")
+	keyID, err := parsePathInt64(ctx, "key_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, err := parseOptionalQueryInt64(ctx, "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	ownerID, statusCode, err := apiKeyOwner(ctx, userID)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	var in presenters.RotateAPIKeyRequest
+	if err := bindJSON(ctx, &in); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	grace := time.Duration(in.GraceHours) * time.Hour
+	if grace < 0 || grace > maxRotationGrace {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("grace_hours must be 0-%d", int(maxRotationGrace/time.Hour))))
+		return
+	}
+	expiresAt, err := apiKeyExpiry(a, in.ExpiresInHours)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	authID, _ := authUserID(ctx)
+
+	raw, next, err := newAPIKey()
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Generate API key failed")
+		return
+	}
+	next.CreatedBy = authID
+	next.ExpiresAt = &expiresAt
+	rotated, err := a.APIKeys.RotateAPIKey(ctx.Request.Context(), ownerID, keyID, next, time.Now().Add(grace))
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Rotate API key failed")
+		return
+	}
+	middlewares.AuditTargets(ctx, fmt.Sprintf("new_key_id=%d", rotated.ID))
+	out := mapAPIKey(rotated)
+	out.Key = raw
+	ctx.Header("Cache-Control", "no-store")
+	render(ctx, http.StatusCreated, out)
+}
+
+// RevokeAPIKey
+// @Summary Revoke API key
+// @Description Revoke an API key so that it stops working immediately
+// @Tags keys
+// @Param key_id path int true "Key ID"
+// @Param user_id query int false "Owner (admin only)"
+// @Success 200
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /keys/{key_id} [delete]
+func RevokeAPIKey(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "api_key.revoke", "api_key")
+	keyID, err := parsePathInt64(ctx, "key_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, err := parseOptionalQueryInt64(ctx, "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	ownerID, statusCode, err := apiKeyOwner(ctx, userID)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	if err := a.APIKeys.RevokeAPIKey(ctx.Request.Context(), ownerID, keyID); err != nil {
+		respondRepositoryError(ctx, a, err, "Revoke API key failed")
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// apiKeyOwner returns whose keys the request manages: the caller's own, or
+// any user's for admins.
+func apiKeyOwner(ctx *gin.Context, userID int64) (int64, int, error) {
+	authID, ok := authUserID(ctx)
+	if !ok {
+		return 0, http.StatusUnauthorized, fmt.Errorf("user_id not found in token")
+	}
+	if userID == 0 || userID == authID {
+		return authID, 0, nil
+	}
+	if userID < 0 {
+		return 0, http.StatusBadRequest, fmt.Errorf("user_id must be positive")
+	}
+	if !middlewares.HasRole(ctx, domain.RoleAdmin) {
+		return 0, http.StatusForbidden, fmt.Errorf("only admins manage keys of other users")
+	}
+	return userID, 0, nil
+}
+
+// parseScopes validates requested scopes; a caller can only grant scopes
+// their own role allows.
+func parseScopes(ctx *gin.Context, raw []string) ([]domain.Scope, int, error) {
+	if len(raw) == 0 {
+		return nil, http.StatusBadRequest, fmt.Errorf("at least one scope is required")
+	}
+	scopes := make([]domain.Scope, 0, len(raw))
+	seen := make(map[domain.Scope]bool, len(raw))
+	for _, r := range raw {
+		s, ok := domain.ParseScope(r)
+		if !ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("unknown scope %q", r)
+		}
+		if !middlewares.HasRole(ctx, s.Role()) {
+			return nil, http.StatusForbidden, fmt.Errorf("scope %s requires role %s", s, s.Role())
+		}
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, 0, nil
+}
+
+func apiKeyExpiry(a *app.App, hours int) (time.Time, error) {
+	cfg := a.Config()
+	if hours < 0 {
+		return time.Time{}, fmt.Errorf("expires_in_hours must be positive")
+	}
+	ttl := cfg.APIKeyDefaultTTL
+	if hours > 0 {
+		ttl = time.Duration(hours) * time.Hour
+	}
+	if cfg.APIKeyMaxTTL > 0 && ttl > cfg.APIKeyMaxTTL {
+		return time.Time{}, fmt.Errorf("expires_in_hours must not exceed %d", int(cfg.APIKeyMaxTTL/time.Hour))
+	}
+	return time.Now().Add(ttl), nil
+}
+
+// newAPIKey generates a key and returns it with the hash and prefix to store.
+func newAPIKey() (string, *domain.APIKey, error) {
+	token, err := securetoken.Generate(securetoken.DefaultSize)
+	if err != nil {
+		return "", nil, err
+	}
+	raw := domain.APIKeyPrefix + token
+	return raw, &domain.APIKey{
+		Prefix:  raw[:apiKeyDisplayLength],
+		KeyHash: securetoken.Hash(raw),
+	}, nil
+}
+
+func mapAPIKey(k *domain.APIKey) presenters.APIKeyResponse {
+	out := presenters.APIKeyResponse{
+		KeyId:       k.ID,
+		UserId:      k.UserID,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Scopes:      make([]string, 0, len(k.Scopes)),
+		CreatedAt:   k.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:   formatOptionalTime(k.ExpiresAt),
+		LastUsedAt:  formatOptionalTime(k.LastUsedAt),
+		RevokedAt:   formatOptionalTime(k.RevokedAt),
+		RotatedFrom: k.RotatedFrom,
+		Active:      k.Active(time.Now()),
+	}
+	for _, s := range k.Scopes {
+		out.Scopes = append(out.Scopes, string(s))
+	}
+	return out
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var auditCSVHeader = []string{
+	"id", "created_at", "actor_id", "action", "target_type", "target_ids", "request_id",
+	"client_ip", "outcome", "status_code", "payload", "prev_hash", "hash",
+}
+
+// AdminGetAuditLog
+// @Summary Query audit log
+// @Description Query audit log entries, newest first (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param actor query int false "Actor user ID"
+// @Param action query string false "Action, e.g. admin.chat.delete or POST /api/chats/"
+// @Param from query string false "From time (RFC3339 or YYYY-MM-DD), inclusive"
+// @Param to query string false "To time (RFC3339 or YYYY-MM-DD), exclusive"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} presenters.AuditLogResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /admin/audit [get]
+func AdminGetAuditLog(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.audit.query", "audit")
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	filter.Limit, filter.Offset, err = middlewares.ParsePage(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	entries, err := a.Audit.GetAuditEntries(ctx.Request.Context(), filter)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Get audit entries failed")
+		return
+	}
+	out := presenters.AuditLogResponse{
+		Entries: make([]presenters.AuditEntry, 0, len(entries)),
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}
+	for i := range entries {
+		out.Entries = append(out.Entries, mapAuditEntry(&entries[i]))
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// AdminExportAuditLog
+// @Summary Export audit log
+// @Description Export audit log entries matching the filter as CSV in chain order (admin only)
+// @Tags admin
+// @Produce text/csv
+// @Param actor query int false "Actor user ID"
+// @Param action query string false "Action"
+// @Param from query string false "From time (RFC3339 or YYYY-MM-DD), inclusive"
+// @Param to query string false "To time (RFC3339 or YYYY-MM-DD), exclusive"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Router /admin/audit/export [get]
+func AdminExportAuditLog(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.audit.export", "audit")
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	// Rows are buffered per entry so that a database error before the first
+	// row can still be reported with a proper status code.
+	var (
+		buf     bytes.Buffer
+		started bool
+	)
+	w := csv.NewWriter(&buf)
+	flush := func() error {
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+		if !started {
+			started = true
+			ctx.Header("Content-Type", "text/csv; charset=utf-8")
+			ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-%s.csv"`, time.Now().UTC().Format("20060102T150405Z")))
+			ctx.Status(http.StatusOK)
+		}
+		_, err := ctx.Writer.Write(buf.Bytes())
+		buf.Reset()
+		return err
+	}
+
+	_ = w.Write(auditCSVHeader)
+	err = a.Audit.StreamAuditEntries(ctx.Request.Context(), filter, func(e *domain.AuditEntry) error {
+		if err := w.Write(auditCSVRecord(e)); err != nil {
+			return err
+		}
+		return flush()
+	})
+	if err != nil {
+		if !started {
+			respondRepositoryError(ctx, a, err, "Export audit entries failed")
+			return
+		}
+		a.Logger.WithError(err).Error("Audit export interrupted")
+		return
+	}
+	if err := flush(); err != nil {
+		a.Logger.WithError(err).Error("Audit export interrupted")
+	}
+}
+
+// AdminVerifyAuditLog
+// @Summary Verify audit log
+// @Description Recompute the hash chain of the audit log and report the first tampered entry (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} presenters.AuditVerifyResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /admin/audit/verify [get]
+func AdminVerifyAuditLog(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.audit.verify", "audit")
+	out := presenters.AuditVerifyResponse{Valid: true}
+	var prev []byte
+	err := a.Audit.StreamAuditEntries(ctx.Request.Context(), domain.AuditFilter{}, func(e *domain.AuditEntry) error {
+		if e.Hash == nil {
+			// Written before hash chaining was introduced.
+			return nil
+		}
+		out.Checked++
+		switch {
+		case !bytes.Equal(e.PrevHash, prev):
+			out.Reason = "previous hash does not match, an entry was removed or reordered"
+		case !bytes.Equal(e.ComputeHash(), e.Hash):
+			out.Reason = "entry hash does not match its content"
+		default:
+			prev = e.Hash
+			return nil
+		}
+		out.Valid = false
+		out.FirstInvalidId = e.ID
+		return errStopStream
+	})
+	if err != nil && !errors.Is(err, errStopStream) {
+		respondRepositoryError(ctx, a, err, "Verify audit log failed")
+		return
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+var errStopStream = errors.New("stop stream")
+
+func parseAuditFilter(ctx *gin.Context) (domain.AuditFilter, error) {
+	var (
+		filter domain.AuditFilter
+		err    error
+	)
+	if raw := ctx.Query("actor"); raw != "" {
+		if filter.ActorID, err = parsePositiveInt64(raw, "actor"); err != nil {
+			return filter, err
+		}
+	}
+	filter.Action = strings.TrimSpace(ctx.Query("action"))
+	if filter.From, err = parseTimeQuery(ctx, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(ctx, "to"); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+	return filter, nil
+}
+
+func parseTimeQuery(ctx *gin.Context, name string) (time.Time, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be RFC3339 time or YYYY-MM-DD date", name)
+}
+
+func mapAuditEntry(e *domain.AuditEntry) presenters.AuditEntry {
+	targets := e.TargetIDs
+	if targets == nil {
+		targets = []string{}
+	}
+	return presenters.AuditEntry{
+		Id:         e.ID,
+		CreatedAt:  e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		ActorId:    e.ActorID,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetIds:  targets,
+		RequestId:  e.RequestID,
+		ClientIp:   e.ClientIP,
+		Outcome:    e.Outcome,
+		StatusCode: e.StatusCode,
+		Payload:    e.Payload,
+		PrevHash:   hex.EncodeToString(e.PrevHash),
+		Hash:       hex.EncodeToString(e.Hash),
+	}
+}
+
+func auditCSVRecord(e *domain.AuditEntry) []string {
+	return []string{
+		strconv.FormatInt(e.ID, 10),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatInt(e.ActorID, 10),
+		e.Action,
+		e.TargetType,
+		strings.Join(e.TargetIDs, ";"),
+		e.RequestID,
+		e.ClientIP,
+		e.Outcome,
+		strconv.Itoa(e.StatusCode),
+		e.Payload,
+		hex.EncodeToString(e.PrevHash),
+		hex.EncodeToString(e.Hash),
+	}
+}
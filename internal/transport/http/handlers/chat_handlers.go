@@ -3,11 +3,14 @@ package handlers
 import (
 	pb "VKR_gateway_service/gen/go"
 	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/events"
+	"VKR_gateway_service/internal/transport/http/middlewares"
 	"VKR_gateway_service/internal/transport/http/presenters"
 	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc/codes"
@@ -28,7 +31,7 @@ import (
 // @Router /ai/paper/add [post]
 func PaperAdd(ctx *gin.Context, a *app.App) {
 	var in presenters.AddPaperRequest
-	if err := ctx.ShouldBindJSON(&in); err != nil {
+	if err := bindJSON(ctx, &in); err != nil {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
@@ -53,30 +56,33 @@ func PaperAdd(ctx *gin.Context, a *app.App) {
 		}
 	}
 
-	rctx := ctx.Request.Context()
-	if a.Config.GRPCTimeout > 0 {
-		var cancel context.CancelFunc
-		rctx, cancel = context.WithTimeout(rctx, a.Config.GRPCTimeout)
-		defer cancel()
+	middlewares.AuditTargets(ctx, "paper_id="+in.Id)
+	if statusCode, err := addPaper(ctx.Request.Context(), a, req); err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
 	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// addPaper sends the paper to the AI service and maps a failure to an HTTP status code.
+func addPaper(ctx context.Context, a *app.App, req *pb.AddRequest) (int, error) {
+	rctx, cancel := rpcContext(ctx, a)
+	defer cancel()
 	resp, err := a.AI.AddPaper(rctx, req)
 	if err != nil {
 		if a.Logger != nil {
-			a.Logger.WithError(err).WithField("id", in.Id).Error("AI AddPaper RPC failed")
+			a.Logger.WithError(err).WithField("id", req.GetID()).Error("AI AddPaper RPC failed")
 		}
 		if s, ok := status.FromError(err); ok {
-			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
-			return
+			return mapGRPCToHTTP(s.Code()), fmt.Errorf(s.Message())
 		}
-		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
-		return
+		return http.StatusBadGateway, err
 	}
 	if msg := resp.GetError(); msg != "" {
-		ctx.JSON(http.StatusBadRequest, &presenters.ErrorResponse{Error: msg})
-		return
+		return http.StatusBadRequest, fmt.Errorf("%s", msg)
 	}
-
-	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+	return http.StatusOK, nil
 }
 
 // CreateChat
@@ -94,11 +100,11 @@ func PaperAdd(ctx *gin.Context, a *app.App) {
 // @Router /chats [post]
 func CreateChat(ctx *gin.Context, a *app.App) {
 	var in presenters.CreateChatRequest
-	if err := ctx.ShouldBindJSON(&in); err != nil {
+	if err := bindJSON(ctx, &in); err != nil {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
-	userID, statusCode, err := resolveUserID(ctx, in.UserId)
+	userID, statusCode, err := currentUserID(ctx)
 	if err != nil {
 		ctx.JSON(statusCode, presenters.Error(err))
 		return
@@ -127,7 +133,9 @@ func CreateChat(ctx *gin.Context, a *app.App) {
 		ctx.JSON(http.StatusBadGateway, presenters.Error(fmt.Errorf("empty chat response")))
 		return
 	}
-	ctx.JSON(http.StatusOK, mapChat(chat))
+	middlewares.AuditTargets(ctx, fmt.Sprintf("chat_id=%d", chat.GetChatId()))
+	publishChatEvent(ctx, a, events.ChatCreated, userID, chat.GetChatId(), presenters.MapChat(chat))
+	render(ctx, http.StatusOK, presenters.MapChat(chat))
 }
 
 // GetUserChats
@@ -136,7 +144,6 @@ func CreateChat(ctx *gin.Context, a *app.App) {
 // @Tags chat
 // @Accept json
 // @Produce json
-// @Param user_id query int false "User ID"
 // @Success 200 {object} presenters.ChatsResponse
 // @Failure 400 {object} presenters.ErrorResponse
 // @Failure 401 {object} presenters.ErrorResponse
@@ -144,12 +151,7 @@ func CreateChat(ctx *gin.Context, a *app.App) {
 // @Failure 500 {object} presenters.ErrorResponse
 // @Router /chats [get]
 func GetUserChats(ctx *gin.Context, a *app.App) {
-	userID, err := parseOptionalQueryInt64(ctx, "user_id")
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
-		return
-	}
-	userID, statusCode, err := resolveUserID(ctx, userID)
+	userID, statusCode, err := currentUserID(ctx)
 	if err != nil {
 		ctx.JSON(statusCode, presenters.Error(err))
 		return
@@ -173,9 +175,9 @@ func GetUserChats(ctx *gin.Context, a *app.App) {
 
 	out := presenters.ChatsResponse{Chats: make([]presenters.ChatResponse, 0, len(resp.GetChats()))}
 	for _, chat := range resp.GetChats() {
-		out.Chats = append(out.Chats, mapChat(chat))
+		out.Chats = append(out.Chats, presenters.MapChat(chat))
 	}
-	ctx.JSON(http.StatusOK, out)
+	render(ctx, http.StatusOK, out)
 }
 
 // GetChatHistory
@@ -185,7 +187,6 @@ func GetUserChats(ctx *gin.Context, a *app.App) {
 // @Accept json
 // @Produce json
 // @Param chat_id path int true "Chat ID"
-// @Param user_id query int false "User ID"
 // @Success 200 {object} presenters.ChatHistoryResponse
 // @Failure 400 {object} presenters.ErrorResponse
 // @Failure 401 {object} presenters.ErrorResponse
@@ -198,12 +199,7 @@ func GetChatHistory(ctx *gin.Context, a *app.App) {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
-	userID, err := parseOptionalQueryInt64(ctx, "user_id")
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
-		return
-	}
-	userID, statusCode, err := resolveUserID(ctx, userID)
+	userID, statusCode, err := currentUserID(ctx)
 	if err != nil {
 		ctx.JSON(statusCode, presenters.Error(err))
 		return
@@ -228,15 +224,8 @@ func GetChatHistory(ctx *gin.Context, a *app.App) {
 		return
 	}
 
-	out := presenters.ChatHistoryResponse{ChatMessages: make([]presenters.ChatHistoryMessage, 0, len(resp.GetChatMessages()))}
-	for _, msg := range resp.GetChatMessages() {
-		out.ChatMessages = append(out.ChatMessages, presenters.ChatHistoryMessage{
-			SearchQuery: msg.GetSearchQuery(),
-			CreatedAt:   msg.GetCreatedAt(),
-			Papers:      mapPapers(msg.GetPapers().GetPapers()),
-		})
-	}
-	ctx.JSON(http.StatusOK, out)
+	out := presenters.ChatHistoryResponse{ChatMessages: mapChatMessages(resp.GetChatMessages())}
+	render(ctx, http.StatusOK, out)
 }
 
 // CreateChatHistory
@@ -246,7 +235,6 @@ func GetChatHistory(ctx *gin.Context, a *app.App) {
 // @Accept json
 // @Produce json
 // @Param chat_id path int true "Chat ID"
-// @Param user_id query int false "User ID"
 // @Param data body presenters.ChatHistoryCreateRequest true "Search query"
 // @Success 200 {object} presenters.SearchPaperResponse
 // @Failure 400 {object} presenters.ErrorResponse
@@ -260,12 +248,7 @@ func CreateChatHistory(ctx *gin.Context, a *app.App) {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
-	userID, err := parseOptionalQueryInt64(ctx, "user_id")
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
-		return
-	}
-	userID, statusCode, err := resolveUserID(ctx, userID)
+	userID, statusCode, err := currentUserID(ctx)
 	if err != nil {
 		ctx.JSON(statusCode, presenters.Error(err))
 		return
@@ -274,7 +257,7 @@ func CreateChatHistory(ctx *gin.Context, a *app.App) {
 		return
 	}
 	var in presenters.ChatHistoryCreateRequest
-	if err := ctx.ShouldBindJSON(&in); err != nil {
+	if err := bindJSON(ctx, &in); err != nil {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
@@ -301,8 +284,13 @@ func CreateChatHistory(ctx *gin.Context, a *app.App) {
 		return
 	}
 
-	out := presenters.SearchPaperResponse{Papers: mapPapers(resp.GetPapers())}
-	ctx.JSON(http.StatusOK, out)
+	out := presenters.SearchPaperResponse{Papers: presenters.MapPapers(resp.GetPapers())}
+	publishChatEvent(ctx, a, events.ChatHistoryCreated, userID, chatID, presenters.ChatHistoryMessage{
+		SearchQuery: in.Text,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Papers:      out.Papers,
+	})
+	render(ctx, http.StatusOK, out)
 }
 
 // UpdateChat
@@ -325,12 +313,7 @@ func UpdateChat(ctx *gin.Context, a *app.App) {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
-	userID, err := parseOptionalQueryInt64(ctx, "user_id")
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
-		return
-	}
-	userID, statusCode, err := resolveUserID(ctx, userID)
+	userID, statusCode, err := currentUserID(ctx)
 	if err != nil {
 		ctx.JSON(statusCode, presenters.Error(err))
 		return
@@ -339,7 +322,7 @@ func UpdateChat(ctx *gin.Context, a *app.App) {
 		return
 	}
 	var in presenters.CreateChatRequest
-	if err := ctx.ShouldBindJSON(&in); err != nil {
+	if err := bindJSON(ctx, &in); err != nil {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
@@ -368,7 +351,8 @@ func UpdateChat(ctx *gin.Context, a *app.App) {
 		ctx.JSON(http.StatusBadGateway, presenters.Error(fmt.Errorf("empty chat response")))
 		return
 	}
-	ctx.JSON(http.StatusOK, mapChat(chat))
+	publishChatEvent(ctx, a, events.ChatUpdated, userID, chatID, presenters.MapChat(chat))
+	render(ctx, http.StatusOK, presenters.MapChat(chat))
 }
 
 // DeleteChat
@@ -389,12 +373,7 @@ func DeleteChat(ctx *gin.Context, a *app.App) {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
 		return
 	}
-	userID, err := parseOptionalQueryInt64(ctx, "user_id")
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
-		return
-	}
-	userID, statusCode, err := resolveUserID(ctx, userID)
+	userID, statusCode, err := currentUserID(ctx)
 	if err != nil {
 		ctx.JSON(statusCode, presenters.Error(err))
 		return
@@ -422,7 +401,9 @@ func DeleteChat(ctx *gin.Context, a *app.App) {
 	}
 	if resp.Error != "" {
 		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf(resp.Error)))
+		return
 	}
+	publishChatEvent(ctx, a, events.ChatDeleted, userID, chatID, nil)
 	ctx.Status(http.StatusOK)
 }
 
@@ -444,11 +425,18 @@ func mapGRPCToHTTP(c codes.Code) int {
 }
 
 func requestContext(ctx *gin.Context, a *app.App) (context.Context, context.CancelFunc) {
-	rctx := ctx.Request.Context()
-	if a != nil && a.Config != nil && a.Config.GRPCTimeout > 0 {
-		return context.WithTimeout(rctx, a.Config.GRPCTimeout)
+	return rpcContext(ctx.Request.Context(), a)
+}
+
+// rpcContext bounds a single AI service call by GRPC_TIMEOUT.
+func rpcContext(parent context.Context, a *app.App) (context.Context, context.CancelFunc) {
+	if a == nil {
+		return parent, func() {}
+	}
+	if cfg := a.Config(); cfg != nil && cfg.GRPCTimeout > 0 {
+		return context.WithTimeout(parent, cfg.GRPCTimeout)
 	}
-	return rctx, func() {}
+	return parent, func() {}
 }
 
 func parsePathInt64(ctx *gin.Context, name string) (int64, error) {
@@ -475,44 +463,32 @@ func parsePositiveInt64(raw, field string) (int64, error) {
 	return val, nil
 }
 
-func resolveUserID(ctx *gin.Context, userID int64) (int64, int, error) {
-	authID, ok := authUserID(ctx)
-	if userID > 0 {
-		if ok && authID != userID {
-			return 0, http.StatusForbidden, fmt.Errorf("user_id does not match token")
-		}
+// currentUserID returns the user whose data the request works on. It comes
+// only from the authenticated principal, or from X-Act-As for admins.
+func currentUserID(ctx *gin.Context) (int64, int, error) {
+	if userID, ok := middlewares.SubjectID(ctx); ok {
 		return userID, 0, nil
 	}
-	if ok {
-		return authID, 0, nil
-	}
-	return 0, http.StatusUnauthorized, fmt.Errorf("user_id is required")
+	return 0, http.StatusUnauthorized, fmt.Errorf("user is not authenticated")
 }
 
+// authUserID returns the id of the authenticated principal.
 func authUserID(ctx *gin.Context) (int64, bool) {
-	val, ok := ctx.Get("user_id")
+	p, ok := middlewares.PrincipalFrom(ctx)
 	if !ok {
 		return 0, false
 	}
-	switch v := val.(type) {
-	case int64:
-		return v, true
-	case int:
-		return int64(v), true
-	case float64:
-		return int64(v), true
-	case string:
-		id, err := strconv.ParseInt(v, 10, 64)
-		if err != nil {
-			return 0, false
-		}
-		return id, true
-	default:
-		return 0, false
-	}
+	return p.UserID, true
 }
 
 func authorizeChatAccess(ctx *gin.Context, a *app.App, userID, chatID int64) bool {
+	_, ok := findUserChat(ctx, a, userID, chatID)
+	return ok
+}
+
+// findUserChat returns the chat if it belongs to the user. Otherwise it writes
+// an error response and returns false.
+func findUserChat(ctx *gin.Context, a *app.App, userID, chatID int64) (*pb.Chat, bool) {
 	req := &pb.UserChatsReq{UserId: userID}
 	rctx, cancel := requestContext(ctx, a)
 	defer cancel()
@@ -523,41 +499,27 @@ func authorizeChatAccess(ctx *gin.Context, a *app.App, userID, chatID int64) boo
 		}
 		if s, ok := status.FromError(err); ok {
 			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
-			return false
+			return nil, false
 		}
 		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
-		return false
+		return nil, false
 	}
 	for _, chat := range resp.GetChats() {
 		if chat.GetChatId() == chatID {
-			return true
+			return chat, true
 		}
 	}
 	ctx.JSON(http.StatusForbidden, presenters.Error(fmt.Errorf("chat access denied")))
-	return false
+	return nil, false
 }
 
-func mapChat(chat *pb.Chat) presenters.ChatResponse {
-	if chat == nil {
-		return presenters.ChatResponse{}
-	}
-	return presenters.ChatResponse{
-		ChatId:    chat.GetChatId(),
-		UserId:    chat.GetUserId(),
-		UpdatedAt: chat.GetUpdatedAt(),
-		Title:     chat.GetTitle(),
-	}
-}
-
-func mapPapers(papers []*pb.PaperResponse) []presenters.Paper {
-	out := make([]presenters.Paper, 0, len(papers))
-	for _, p := range papers {
-		out = append(out, presenters.Paper{
-			Id:               p.GetID(),
-			Title:            p.GetTitle(),
-			Abstract:         p.GetAbstract(),
-			Year:             int(p.GetYear()),
-			Best_oa_location: p.GetBestOaLocation(),
+func mapChatMessages(msgs []*pb.ChatMessage) []presenters.ChatHistoryMessage {
+	out := make([]presenters.ChatHistoryMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		out = append(out, presenters.ChatHistoryMessage{
+			SearchQuery: msg.GetSearchQuery(),
+			CreatedAt:   msg.GetCreatedAt(),
+			Papers:      presenters.MapPapers(msg.GetPapers().GetPapers()),
 		})
 	}
 	return out
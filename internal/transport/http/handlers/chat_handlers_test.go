@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository/repotest"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// chatRoutes are requests to every chat route for chat 7, each trying to
+// pass user 2 as the subject through the query and the body.
+var chatRoutes = []struct {
+	method, target, body string
+}{
+	{http.MethodPost, "/api/chats?user_id=2", `{"title":"t","user_id":2}`},
+	{http.MethodGet, "/api/chats?user_id=2", ""},
+	{http.MethodGet, "/api/chats/7/history?user_id=2", ""},
+	{http.MethodPost, "/api/chats/7/history?user_id=2", `{"text":"q","user_id":2}`},
+	{http.MethodPost, "/api/chats/7/history/0/rerun?user_id=2", `{"user_id":2}`},
+	{http.MethodPut, "/api/chats/7?user_id=2", `{"title":"t","user_id":2}`},
+	{http.MethodDelete, "/api/chats/7?user_id=2", `{"user_id":2}`},
+}
+
+// userIDs returns the user ids of every request the AI service received.
+func (f *fakeAI) userIDs() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []int64
+	for _, req := range f.reqs {
+		switch r := req.(type) {
+		case *pb.UserChatsReq:
+			ids = append(ids, r.GetUserId())
+		case *pb.Chat:
+			ids = append(ids, r.GetUserId())
+		case *pb.UpdateChatReq:
+			ids = append(ids, r.GetUserId())
+		case *pb.DeleteChatReq:
+			ids = append(ids, r.GetUserId())
+		}
+	}
+	return ids
+}
+
+func TestChatRoutesIgnoreUserID(t *testing.T) {
+	for _, route := range chatRoutes {
+		t.Run(route.method+" "+route.target, func(t *testing.T) {
+			// Chat 7 belongs to the caller, user 1
+			ai := &fakeAI{chatOwners: map[int64]int64{7: 1}, results: 1}
+			r := newTestRouter(newTestApp(t, ai), &auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleUser}}, middlewares.ActAs())
+
+			w := do(r, route.method, route.target, route.body)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", w.Code, w.Body)
+			}
+			ids := ai.userIDs()
+			if len(ids) == 0 {
+				t.Fatal("no request carried a user id")
+			}
+			for _, id := range ids {
+				if id != 1 {
+					t.Fatalf("AI service got user %d, want the caller 1 (calls %v)", id, ai.calls)
+				}
+			}
+		})
+	}
+}
+
+func TestChatRoutesDenyOtherUsersChats(t *testing.T) {
+	for _, route := range chatRoutes {
+		if route.target == "/api/chats?user_id=2" {
+			continue
+		}
+		t.Run(route.method+" "+route.target, func(t *testing.T) {
+			// Chat 7 belongs to user 2, passing user_id=2 must not help
+			ai := &fakeAI{chatOwners: map[int64]int64{7: 2}, results: 1}
+			r := newTestRouter(newTestApp(t, ai), &auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleUser}}, middlewares.ActAs())
+
+			if w := do(r, route.method, route.target, route.body); w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want 403: %s", w.Code, w.Body)
+			}
+			for _, m := range []string{"SearchPaper", "UpdateChat", "DeleteChat", "GetChatHistory"} {
+				if n := len(ai.called(m)); n != 0 {
+					t.Fatalf("%s called %d times", m, n)
+				}
+			}
+		})
+	}
+}
+
+func TestActAsRejectedForNonAdmins(t *testing.T) {
+	principals := map[string]*auth.Principal{
+		"user":    {UserID: 1, Roles: []domain.Role{domain.RoleUser}},
+		"curator": {UserID: 1, Roles: []domain.Role{domain.RoleCurator}},
+		// Admin role, but an API key without admin:write
+		"admin key": {UserID: 1, Roles: []domain.Role{domain.RoleAdmin}, Method: auth.MethodAPIKey, Scopes: []domain.Scope{domain.ScopeChatsRead, domain.ScopeChatsWrite}},
+	}
+	for name, p := range principals {
+		for _, route := range chatRoutes {
+			t.Run(name+" "+route.method+" "+route.target, func(t *testing.T) {
+				ai := &fakeAI{chatOwners: map[int64]int64{7: 2}, results: 1}
+				audit := &repotest.AuditRepository{}
+				a := newTestApp(t, ai)
+				a.Audit = audit
+				r := newTestRouter(a, p, middlewares.Audit(a), middlewares.ActAs())
+
+				if w := do(r, route.method, route.target, route.body, middlewares.ActAsHeader, "2"); w.Code != http.StatusForbidden {
+					t.Fatalf("status = %d, want 403: %s", w.Code, w.Body)
+				}
+				if len(ai.calls) != 0 {
+					t.Fatalf("AI service called: %v", ai.calls)
+				}
+				if entries := audit.Entries(); len(entries) != 1 || entries[0].Outcome != domain.AuditOutcomeDenied {
+					t.Fatalf("audit entries = %+v, want one denied entry", entries)
+				}
+			})
+		}
+	}
+}
+
+func TestActAsAuditedForAdmins(t *testing.T) {
+	admin := &auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleAdmin}}
+	for _, route := range chatRoutes {
+		t.Run(route.method+" "+route.target, func(t *testing.T) {
+			ai := &fakeAI{chatOwners: map[int64]int64{7: 2}, results: 1}
+			audit := &repotest.AuditRepository{}
+			a := newTestApp(t, ai)
+			a.Audit = audit
+			r := newTestRouter(a, admin, middlewares.Audit(a), middlewares.ActAs())
+
+			w := do(r, route.method, route.target, route.body, middlewares.ActAsHeader, "2")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", w.Code, w.Body)
+			}
+			for _, id := range ai.userIDs() {
+				if id != 2 {
+					t.Fatalf("AI service got user %d, want the X-Act-As user 2", id)
+				}
+			}
+			// Reads are audited too when they act for another user
+			entries := audit.Entries()
+			if len(entries) != 1 {
+				t.Fatalf("got %d audit entries, want 1", len(entries))
+			}
+			entry := entries[0]
+			if entry.ActorID != 1 || !slices.Contains(entry.TargetIDs, "act_as=2") || entry.Outcome != domain.AuditOutcomeSuccess {
+				t.Fatalf("audit entry = %+v", entry)
+			}
+		})
+	}
+}
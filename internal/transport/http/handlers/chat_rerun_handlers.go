@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/events"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+// RerunChatHistory
+// @Summary Re-run chat history entry
+// @Description Run the search query of a chat history entry again, append the result to the chat history and compare it with the original papers. index is the 0-based position in GET /chats/{chat_id}/history; entries without a search query cannot be re-run.
+// @Tags chat
+// @Produce json
+// @Param chat_id path int true "Chat ID"
+// @Param index path int true "History entry index"
+// @Success 200 {object} presenters.ChatRerunResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /chats/{chat_id}/history/{index}/rerun [post]
+func RerunChatHistory(ctx *gin.Context, a *app.App) {
+	chatID, err := parsePathInt64(ctx, "chat_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	index, err := strconv.Atoi(ctx.Param("index"))
+	if err != nil || index < 0 {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("index must be a non-negative integer")))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	if !authorizeChatAccess(ctx, a, userID, chatID) {
+		return
+	}
+
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	history, err := a.AI.GetChatHistory(rctx, &pb.HistoryReq{ChatId: chatID})
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithFields(map[string]interface{}{
+				"chat_id": chatID,
+				"user_id": userID,
+			}).Error("AI GetChatHistory RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf("%s", s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+	msgs := history.GetChatMessages()
+	if index >= len(msgs) {
+		ctx.JSON(http.StatusNotFound, presenters.Error(fmt.Errorf("history entry %d not found", index)))
+		return
+	}
+	original := msgs[index]
+	if strings.TrimSpace(original.GetSearchQuery()) == "" {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("history entry %d has no search query to run", index)))
+		return
+	}
+
+	rctx, cancel = requestContext(ctx, a)
+	defer cancel()
+	resp, err := a.AI.SearchPaper(rctx, &pb.SearchRequest{InputData: original.GetSearchQuery(), ChatId: chatID})
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithFields(map[string]interface{}{
+				"chat_id": chatID,
+				"user_id": userID,
+			}).Error("AI SearchPaper RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf("%s", s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+
+	out := presenters.ChatRerunResponse{
+		SearchQuery:       original.GetSearchQuery(),
+		OriginalCreatedAt: original.GetCreatedAt(),
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339),
+		Papers:            presenters.MapPapers(resp.GetPapers()),
+	}
+	out.Diff = diffPapers(presenters.MapPapers(original.GetPapers().GetPapers()), out.Papers)
+	publishChatEvent(ctx, a, events.ChatHistoryCreated, userID, chatID, presenters.ChatHistoryMessage{
+		SearchQuery: out.SearchQuery,
+		CreatedAt:   out.CreatedAt,
+		Papers:      out.Papers,
+	})
+	render(ctx, http.StatusOK, out)
+}
+
+// diffPapers compares two ranked result lists by paper id. Repeated ids keep
+// their first rank.
+func diffPapers(before, after []presenters.Paper) presenters.ChatRerunDiff {
+	diff := presenters.ChatRerunDiff{
+		Added:       []presenters.RankedPaper{},
+		Removed:     []presenters.RankedPaper{},
+		RankChanged: []presenters.PaperRankChange{},
+	}
+	oldRanks := paperRanks(before)
+	newRanks := paperRanks(after)
+	for i, p := range after {
+		rank := i + 1
+		if newRanks[p.Id] != rank {
+			continue
+		}
+		switch oldRank, ok := oldRanks[p.Id]; {
+		case !ok:
+			diff.Added = append(diff.Added, presenters.RankedPaper{Paper: p, Rank: rank})
+		case oldRank != rank:
+			diff.RankChanged = append(diff.RankChanged, presenters.PaperRankChange{Paper: p, PreviousRank: oldRank, Rank: rank})
+		default:
+			diff.Unchanged++
+		}
+	}
+	for i, p := range before {
+		rank := i + 1
+		if _, ok := newRanks[p.Id]; !ok && oldRanks[p.Id] == rank {
+			diff.Removed = append(diff.Removed, presenters.RankedPaper{Paper: p, Rank: rank})
+		}
+	}
+	return diff
+}
+
+func paperRanks(papers []presenters.Paper) map[string]int {
+	ranks := make(map[string]int, len(papers))
+	for i, p := range papers {
+		if _, ok := ranks[p.Id]; !ok {
+			ranks[p.Id] = i + 1
+		}
+	}
+	return ranks
+}
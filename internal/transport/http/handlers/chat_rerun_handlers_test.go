@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func papers(ids ...string) []presenters.Paper {
+	out := make([]presenters.Paper, len(ids))
+	for i, id := range ids {
+		out[i] = presenters.Paper{Id: id}
+	}
+	return out
+}
+
+func ranked(pairs ...interface{}) []presenters.RankedPaper {
+	out := []presenters.RankedPaper{}
+	for i := 0; i < len(pairs); i += 2 {
+		out = append(out, presenters.RankedPaper{Paper: presenters.Paper{Id: pairs[i].(string)}, Rank: pairs[i+1].(int)})
+	}
+	return out
+}
+
+func moved(id string, from, to int) presenters.PaperRankChange {
+	return presenters.PaperRankChange{Paper: presenters.Paper{Id: id}, PreviousRank: from, Rank: to}
+}
+
+func TestDiffPapers(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after []presenters.Paper
+		want          presenters.ChatRerunDiff
+	}{
+		{"unchanged", papers("a", "b", "c"), papers("a", "b", "c"),
+			presenters.ChatRerunDiff{Added: ranked(), Removed: ranked(), RankChanged: []presenters.PaperRankChange{}, Unchanged: 3}},
+		{"added", papers("a", "b"), papers("a", "b", "c"),
+			presenters.ChatRerunDiff{Added: ranked("c", 3), Removed: ranked(), RankChanged: []presenters.PaperRankChange{}, Unchanged: 2}},
+		{"removed", papers("a", "b", "c"), papers("a", "c"),
+			presenters.ChatRerunDiff{Added: ranked(), Removed: ranked("b", 2), RankChanged: []presenters.PaperRankChange{moved("c", 3, 2)}, Unchanged: 1}},
+		{"rank changed", papers("a", "b", "c"), papers("c", "a", "b"),
+			presenters.ChatRerunDiff{Added: ranked(), Removed: ranked(), RankChanged: []presenters.PaperRankChange{moved("c", 3, 1), moved("a", 1, 2), moved("b", 2, 3)}}},
+		{"everything replaced", papers("a", "b"), papers("c", "d"),
+			presenters.ChatRerunDiff{Added: ranked("c", 1, "d", 2), Removed: ranked("a", 1, "b", 2), RankChanged: []presenters.PaperRankChange{}}},
+		// Repeated ids keep their first rank and are counted once
+		{"duplicates before", papers("a", "a", "b"), papers("a", "b"),
+			presenters.ChatRerunDiff{Added: ranked(), Removed: ranked(), RankChanged: []presenters.PaperRankChange{moved("b", 3, 2)}, Unchanged: 1}},
+		{"duplicates after", papers("a", "b"), papers("b", "a", "b", "c", "c"),
+			presenters.ChatRerunDiff{Added: ranked("c", 4), Removed: ranked(), RankChanged: []presenters.PaperRankChange{moved("b", 2, 1), moved("a", 1, 2)}}},
+		{"duplicate removed once", papers("a", "x", "x"), papers("a"),
+			presenters.ChatRerunDiff{Added: ranked(), Removed: ranked("x", 2), RankChanged: []presenters.PaperRankChange{}, Unchanged: 1}},
+		{"empty before", nil, papers("a", "b"),
+			presenters.ChatRerunDiff{Added: ranked("a", 1, "b", 2), Removed: ranked(), RankChanged: []presenters.PaperRankChange{}}},
+		{"empty after", papers("a", "b"), nil,
+			presenters.ChatRerunDiff{Added: ranked(), Removed: ranked("a", 1, "b", 2), RankChanged: []presenters.PaperRankChange{}}},
+		{"both empty", nil, nil,
+			presenters.ChatRerunDiff{Added: ranked(), Removed: ranked(), RankChanged: []presenters.PaperRankChange{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffPapers(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("diffPapers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRerunChatHistory(t *testing.T) {
+	ai := &fakeAI{chatOwners: map[int64]int64{7: 1}, results: 3, history: []*pb.ChatMessage{
+		{SearchQuery: "graphs", CreatedAt: "2026-01-01T00:00:00Z", Papers: &pb.PapersResponse{Papers: []*pb.PaperResponse{{ID: "W2"}, {ID: "W9"}}}},
+		{SearchQuery: " ", CreatedAt: "2026-01-02T00:00:00Z"},
+	}}
+	r := newTestRouter(newTestApp(t, ai), &auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleUser}})
+
+	w := do(r, http.MethodPost, "/api/chats/7/history/0/rerun", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("rerun = %d: %s", w.Code, w.Body)
+	}
+	var out presenters.ChatRerunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	// The new results are W0, W1, W2
+	if out.SearchQuery != "graphs" || len(out.Papers) != 3 || len(out.Diff.Added) != 2 ||
+		!reflect.DeepEqual(out.Diff.Removed, []presenters.RankedPaper{{Paper: presenters.Paper{Id: "W9"}, Rank: 2}}) ||
+		len(out.Diff.RankChanged) != 1 || out.Diff.RankChanged[0].PreviousRank != 1 || out.Diff.RankChanged[0].Rank != 3 {
+		t.Fatalf("rerun = %s", w.Body)
+	}
+	searches := ai.called("SearchPaper")
+	if len(searches) != 1 || searches[0].(*pb.SearchRequest).GetInputData() != "graphs" {
+		t.Fatalf("SearchPaper calls = %v", searches)
+	}
+
+	tests := []struct {
+		name, target string
+		code         int
+		want         string
+	}{
+		{"index out of range", "/api/chats/7/history/2/rerun", http.StatusNotFound, "history entry 2 not found"},
+		{"negative index", "/api/chats/7/history/-1/rerun", http.StatusBadRequest, "non-negative integer"},
+		{"no stored query", "/api/chats/7/history/1/rerun", http.StatusBadRequest, "history entry 1 has no search query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(r, http.MethodPost, tt.target, "")
+			if w.Code != tt.code || !strings.Contains(w.Body.String(), tt.want) {
+				t.Fatalf("rerun = %d: %s, want %d %q", w.Code, w.Body, tt.code, tt.want)
+			}
+		})
+	}
+	if n := len(ai.called("SearchPaper")); n != 1 {
+		t.Fatalf("SearchPaper called %d times, refused reruns must not search", n)
+	}
+}
@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxCollectionNameLength = 200
+	maxCollectionTags       = 32
+	maxCollectionTagLength  = 64
+	maxCollectionNoteLength = 4000
+)
+
+// CreateCollection
+// @Summary Create collection
+// @Description Create a new collection of saved papers for the user
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param data body presenters.CollectionRequest true "Collection data"
+// @Success 200 {object} presenters.CollectionResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 409 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /collections [post]
+func CreateCollection(ctx *gin.Context, a *app.App) {
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	var in presenters.CollectionRequest
+	if err := bindJSON(ctx, &in); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	name, err := collectionName(in.Name)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	collection, err := a.Collections.CreateCollection(ctx.Request.Context(), userID, name)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Create collection failed")
+		return
+	}
+	middlewares.AuditTargets(ctx, fmt.Sprintf("collection_id=%d", collection.ID))
+	render(ctx, http.StatusOK, mapCollection(collection))
+}
+
+// GetUserCollections
+// @Summary Get user collections
+// @Description Get all collections of the user
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Success 200 {object} presenters.CollectionsResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /collections [get]
+func GetUserCollections(ctx *gin.Context, a *app.App) {
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+
+	collections, err := a.Collections.GetUserCollections(ctx.Request.Context(), userID)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Get user collections failed")
+		return
+	}
+	out := presenters.CollectionsResponse{Collections: make([]presenters.CollectionResponse, 0, len(collections))}
+	for i := range collections {
+		out.Collections = append(out.Collections, mapCollection(&collections[i]))
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// RenameCollection
+// @Summary Rename collection
+// @Description Rename a collection by owner
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Param data body presenters.CollectionRequest true "Collection data"
+// @Success 200 {object} presenters.CollectionResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 409 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /collections/{collection_id} [put]
+func RenameCollection(ctx *gin.Context, a *app.App) {
+	collectionID, err := parsePathInt64(ctx, "collection_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	var in presenters.CollectionRequest
+	if err := bindJSON(ctx, &in); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	name, err := collectionName(in.Name)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	collection, err := a.Collections.RenameCollection(ctx.Request.Context(), userID, collectionID, name)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Rename collection failed")
+		return
+	}
+	render(ctx, http.StatusOK, mapCollection(collection))
+}
+
+// DeleteCollection
+// @Summary Delete collection
+// @Description Delete a collection with all saved papers
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /collections/{collection_id} [delete]
+func DeleteCollection(ctx *gin.Context, a *app.App) {
+	collectionID, err := parsePathInt64(ctx, "collection_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+
+	if err := a.Collections.DeleteCollection(ctx.Request.Context(), userID, collectionID); err != nil {
+		respondRepositoryError(ctx, a, err, "Delete collection failed")
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// GetCollectionPapers
+// @Summary Get collection papers
+// @Description Get papers saved to a collection
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} presenters.CollectionPapersResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /collections/{collection_id}/papers [get]
+func GetCollectionPapers(ctx *gin.Context, a *app.App) {
+	collectionID, err := parsePathInt64(ctx, "collection_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+
+	papers, err := a.Collections.GetCollectionPapers(ctx.Request.Context(), userID, collectionID)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Get collection papers failed")
+		return
+	}
+	out := presenters.CollectionPapersResponse{
+		CollectionId: collectionID,
+		Papers:       make([]presenters.CollectionPaper, 0, len(papers)),
+	}
+	for i := range papers {
+		out.Papers = append(out.Papers, mapCollectionPaper(&papers[i]))
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// SaveCollectionPaper
+// @Summary Save paper to collection
+// @Description Save a paper of the index to a collection with a note and tags. Title, abstract, year and open access link are copied from the index. Saving the same paper again updates its note, tags and copy.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Param data body presenters.SaveCollectionPaperRequest true "Paper data"
+// @Success 200 {object} presenters.CollectionPaper
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Failure 502 {object} presenters.ErrorResponse
+// @Router /collections/{collection_id}/papers [post]
+func SaveCollectionPaper(ctx *gin.Context, a *app.App) {
+	collectionID, err := parsePathInt64(ctx, "collection_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	var in presenters.SaveCollectionPaperRequest
+	if err := bindJSON(ctx, &in); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	tags, err := collectionTags(in.Tags)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	note := strings.TrimSpace(in.Note)
+	if len([]rune(note)) > maxCollectionNoteLength {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("note must not exceed %d characters", maxCollectionNoteLength)))
+		return
+	}
+
+	// The snapshot is taken from the index, not from the client
+	paperID := strings.TrimSpace(in.Id)
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	detail, err := a.AI.GetPaper(rctx, &pb.PaperReq{ID: paperID})
+	if err != nil {
+		respondPaperRPCError(ctx, a, err, "GetPaper", paperID)
+		return
+	}
+
+	paper, err := a.Collections.SaveCollectionPaper(ctx.Request.Context(), userID, &domain.CollectionPaper{
+		CollectionID:   collectionID,
+		PaperID:        paperID,
+		Title:          detail.GetTitle(),
+		Abstract:       detail.GetAbstract(),
+		Year:           detail.GetYear(),
+		BestOaLocation: detail.GetBestOaLocation(),
+		Note:           note,
+		Tags:           tags,
+	})
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Save collection paper failed")
+		return
+	}
+	render(ctx, http.StatusOK, mapCollectionPaper(paper))
+}
+
+// RemoveCollectionPaper
+// @Summary Remove paper from collection
+// @Description Remove a saved paper from a collection
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Param paper_id path string true "Paper ID"
+// @Success 200
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /collections/{collection_id}/papers/{paper_id} [delete]
+func RemoveCollectionPaper(ctx *gin.Context, a *app.App) {
+	collectionID, err := parsePathInt64(ctx, "collection_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	// paper_id is a catch-all param because paper ids (DOIs, OpenAlex urls) may contain slashes.
+	paperID := strings.TrimPrefix(ctx.Param("paper_id"), "/")
+	if paperID == "" {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("paper_id path param is required")))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+
+	if err := a.Collections.RemoveCollectionPaper(ctx.Request.Context(), userID, collectionID, paperID); err != nil {
+		respondRepositoryError(ctx, a, err, "Remove collection paper failed")
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// respondRepositoryError maps repository sentinel errors to HTTP statuses, anything else is logged as 500.
+func respondRepositoryError(ctx *gin.Context, a *app.App, err error, msg string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, presenters.Error(err))
+	case errors.Is(err, repository.ErrAlreadyExists):
+		ctx.JSON(http.StatusConflict, presenters.Error(err))
+	default:
+		if a.Logger != nil {
+			a.Logger.WithError(err).Error(msg)
+		}
+		ctx.JSON(http.StatusInternalServerError, presenters.Error(fmt.Errorf("internal error")))
+	}
+}
+
+func collectionName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	if len([]rune(name)) > maxCollectionNameLength {
+		return "", fmt.Errorf("name must not exceed %d characters", maxCollectionNameLength)
+	}
+	return name, nil
+}
+
+// collectionTags trims tags and drops empty and duplicate ones keeping the order.
+func collectionTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]struct{}, len(raw))
+	for _, t := range raw {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		if len([]rune(t)) > maxCollectionTagLength {
+			return nil, fmt.Errorf("tags must not exceed %d characters", maxCollectionTagLength)
+		}
+		seen[t] = struct{}{}
+		tags = append(tags, t)
+	}
+	if len(tags) > maxCollectionTags {
+		return nil, fmt.Errorf("no more than %d tags are allowed", maxCollectionTags)
+	}
+	return tags, nil
+}
+
+func mapCollection(c *domain.Collection) presenters.CollectionResponse {
+	return presenters.CollectionResponse{
+		CollectionId: c.ID,
+		Name:         c.Name,
+		PaperCount:   c.PaperCount,
+		CreatedAt:    c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    c.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapCollectionPaper(p *domain.CollectionPaper) presenters.CollectionPaper {
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return presenters.CollectionPaper{
+		Id:               p.PaperID,
+		Title:            p.Title,
+		Abstract:         p.Abstract,
+		Year:             int(p.Year),
+		Best_oa_location: p.BestOaLocation,
+		Note:             p.Note,
+		Tags:             tags,
+		AddedAt:          p.AddedAt.Format(time.RFC3339),
+	}
+}
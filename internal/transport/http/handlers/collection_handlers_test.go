@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// indexAI serves the papers of the index by ID.
+type indexAI struct {
+	pb.SemanticServiceClient
+
+	papers map[string]*pb.PaperDetail
+	calls  int
+}
+
+func (f *indexAI) GetPaper(_ context.Context, in *pb.PaperReq, _ ...grpc.CallOption) (*pb.PaperDetail, error) {
+	f.calls++
+	if p, ok := f.papers[in.GetID()]; ok {
+		return p, nil
+	}
+	return nil, status.Error(codes.NotFound, "paper not found")
+}
+
+// savingCollections records saved papers of collection 5 owned by user 1.
+type savingCollections struct {
+	repository.CollectionRepository
+
+	saved []domain.CollectionPaper
+}
+
+func (r *savingCollections) SaveCollectionPaper(_ context.Context, userID int64, paper *domain.CollectionPaper) (*domain.CollectionPaper, error) {
+	if userID != 1 || paper.CollectionID != 5 {
+		return nil, repository.ErrNotFound
+	}
+	r.saved = append(r.saved, *paper)
+	out := *paper
+	out.AddedAt = time.Now()
+	return &out, nil
+}
+
+func TestSaveCollectionPaperTakesSnapshotFromIndex(t *testing.T) {
+	ai := &indexAI{papers: map[string]*pb.PaperDetail{
+		"10.1000/1": {ID: "10.1000/1", Title: "Indexed title", Abstract: "Indexed abstract", Year: 2021, BestOaLocation: "https://oa.example/1"},
+	}}
+	collections := &savingCollections{}
+	a := newTestApp(t, ai)
+	a.Collections = collections
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(withPrincipal(&auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleUser}}))
+	r.POST("/api/collections/:collection_id/papers", func(ctx *gin.Context) { SaveCollectionPaper(ctx, a) })
+
+	// Snapshot fields sent by the client are ignored
+	w := do(r, http.MethodPost, "/api/collections/5/papers",
+		`{"id":" 10.1000/1 ","title":"<script>","abstract":"`+strings.Repeat("x", 1<<16)+`","year":1,"best_oa_location":"javascript:alert(1)","note":" read ","tags":["ml"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("save = %d: %s", w.Code, w.Body)
+	}
+	want := domain.CollectionPaper{
+		CollectionID: 5, PaperID: "10.1000/1", Title: "Indexed title", Abstract: "Indexed abstract",
+		Year: 2021, BestOaLocation: "https://oa.example/1", Note: "read",
+	}
+	got := collections.saved[0]
+	if got.Title != want.Title || got.Abstract != want.Abstract || got.Year != want.Year ||
+		got.BestOaLocation != want.BestOaLocation || got.PaperID != want.PaperID || got.Note != want.Note {
+		t.Fatalf("saved %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name, body string
+		code       int
+	}{
+		{"paper not in index", `{"id":"10.1000/404"}`, http.StatusNotFound},
+		{"note too long", `{"id":"10.1000/1","note":"` + strings.Repeat("n", maxCollectionNoteLength+1) + `"}`, http.StatusBadRequest},
+		{"tag too long", `{"id":"10.1000/1","tags":["` + strings.Repeat("t", maxCollectionTagLength+1) + `"]}`, http.StatusBadRequest},
+		{"too many tags", `{"id":"10.1000/1","tags":[` + manyTags(maxCollectionTags+1) + `]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(r, http.MethodPost, "/api/collections/5/papers", tt.body); w.Code != tt.code {
+				t.Fatalf("save = %d, want %d: %s", w.Code, tt.code, w.Body)
+			}
+		})
+	}
+	if len(collections.saved) != 1 {
+		t.Fatalf("saved %d papers, want only the valid one", len(collections.saved))
+	}
+	// Invalid notes and tags are refused before the index is asked
+	if ai.calls != 2 {
+		t.Fatalf("GetPaper called %d times, want 2", ai.calls)
+	}
+}
+
+func manyTags(n int) string {
+	tags := make([]string, n)
+	for i := range tags {
+		tags[i] = `"tag` + strconv.Itoa(i) + `"`
+	}
+	return strings.Join(tags, ",")
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdminGetConfig
+// @Summary Show effective config
+// @Description Effective gateway config after merging defaults, config file and environment, with secrets redacted (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} presenters.AdminConfigResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Router /admin/config [get]
+func AdminGetConfig(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.config.get", "config")
+	ctx.JSON(http.StatusOK, presenters.AdminConfigResponse{
+		Source:     a.ConfigStore.Path(),
+		LoadedAt:   a.ConfigStore.LoadedAt().UTC().Format(time.RFC3339),
+		Reloadable: config.ReloadableKeys(),
+		Config:     a.Config().Redacted(),
+	})
+}
+
+// AdminGetDBStats
+// @Summary Show database pool stats
+// @Description Connection pool statistics of the primary and the read replica (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} presenters.DBStatsResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 503 {object} presenters.ErrorResponse
+// @Router /admin/db/stats [get]
+func AdminGetDBStats(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "admin.db.stats", "database")
+	if a.DB == nil {
+		ctx.JSON(http.StatusServiceUnavailable, presenters.Error(fmt.Errorf("database is not available")))
+		return
+	}
+	out := presenters.DBStatsResponse{Primary: mapPoolStats(a.DB.Primary.Stat())}
+	if a.DB.Replica != nil {
+		replica := mapPoolStats(a.DB.Replica.Stat())
+		out.Replica = &replica
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+func mapPoolStats(s *pgxpool.Stat) presenters.DBPoolStats {
+	return presenters.DBPoolStats{
+		MaxConns:                s.MaxConns(),
+		TotalConns:              s.TotalConns(),
+		AcquiredConns:           s.AcquiredConns(),
+		IdleConns:               s.IdleConns(),
+		ConstructingConns:       s.ConstructingConns(),
+		AcquireCount:            s.AcquireCount(),
+		AcquireDurationMs:       s.AcquireDuration().Milliseconds(),
+		EmptyAcquireCount:       s.EmptyAcquireCount(),
+		EmptyAcquireWaitTimeMs:  s.EmptyAcquireWaitTime().Milliseconds(),
+		CanceledAcquireCount:    s.CanceledAcquireCount(),
+		NewConnsCount:           s.NewConnsCount(),
+		MaxLifetimeDestroyCount: s.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     s.MaxIdleDestroyCount(),
+	}
+}
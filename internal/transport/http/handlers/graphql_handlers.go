@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	graphqltransport "VKR_gateway_service/internal/transport/graphql"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxGraphQLBodySize = 1 << 20
+
+// GraphQL
+// @Summary GraphQL query
+// @Description Read-only GraphQL API over chats, chat messages, papers, authors and institutions of the current user. Query depth and complexity are limited by GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param data body presenters.GraphQLRequest true "GraphQL request"
+// @Success 200 {object} presenters.GraphQLResponse
+// @Failure 400 {object} presenters.GraphQLResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Router /graphql [post]
+func GraphQL(ctx *gin.Context, a *app.App, schema *graphqltransport.Schema) {
+	// Queries do not change anything, keep them out of the audit log
+	middlewares.SkipAudit(ctx)
+	userID, ok := authUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, presenters.Error(fmt.Errorf("user_id not found in token")))
+		return
+	}
+
+	var in presenters.GraphQLRequest
+	if ctx.Request.Method == http.MethodGet {
+		in.Query = ctx.Query("query")
+		in.OperationName = ctx.Query("operationName")
+		if raw := ctx.Query("variables"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &in.Variables); err != nil {
+				ctx.JSON(http.StatusBadRequest, graphQLError(fmt.Errorf("variables must be a JSON object")))
+				return
+			}
+		}
+	} else {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxGraphQLBodySize)
+		if err := ctx.ShouldBindJSON(&in); err != nil {
+			ctx.JSON(http.StatusBadRequest, graphQLError(err))
+			return
+		}
+	}
+	if in.Query == "" {
+		ctx.JSON(http.StatusBadRequest, graphQLError(fmt.Errorf("query is required")))
+		return
+	}
+
+	result, executed := schema.Execute(ctx.Request.Context(), userID, graphqltransport.Request{
+		Query:         in.Query,
+		OperationName: in.OperationName,
+		Variables:     in.Variables,
+	})
+	statusCode := http.StatusOK
+	if !executed {
+		statusCode = http.StatusBadRequest
+	}
+	ctx.JSON(statusCode, result)
+}
+
+func graphQLError(err error) presenters.GraphQLResponse {
+	return presenters.GraphQLResponse{Errors: []presenters.GraphQLError{{Message: err.Error()}}}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// fakeAI serves chats owned by chatOwners and records the requests it gets.
+// Methods not overridden panic through the nil embedded client.
+type fakeAI struct {
+	pb.SemanticServiceClient
+
+	chatOwners map[int64]int64
+	results    int
+	// history replaces the default one-message chat history when set
+	history []*pb.ChatMessage
+
+	mu    sync.Mutex
+	calls []string
+	reqs  []interface{}
+}
+
+func (f *fakeAI) record(method string, req interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, method)
+	f.reqs = append(f.reqs, req)
+}
+
+func (f *fakeAI) called(method string) []interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []interface{}
+	for i, m := range f.calls {
+		if m == method {
+			out = append(out, f.reqs[i])
+		}
+	}
+	return out
+}
+
+func (f *fakeAI) GetUserChats(_ context.Context, in *pb.UserChatsReq, _ ...grpc.CallOption) (*pb.ChatsResp, error) {
+	f.record("GetUserChats", in)
+	out := &pb.ChatsResp{}
+	for chatID, owner := range f.chatOwners {
+		if owner == in.GetUserId() {
+			out.Chats = append(out.Chats, &pb.Chat{ChatId: chatID, UserId: owner, Title: "chat"})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAI) CreateNewChat(_ context.Context, in *pb.Chat, _ ...grpc.CallOption) (*pb.ChatResp, error) {
+	f.record("CreateNewChat", in)
+	return &pb.ChatResp{Chat: &pb.Chat{ChatId: 100, UserId: in.GetUserId(), Title: in.GetTitle()}}, nil
+}
+
+func (f *fakeAI) UpdateChat(_ context.Context, in *pb.UpdateChatReq, _ ...grpc.CallOption) (*pb.ChatResp, error) {
+	f.record("UpdateChat", in)
+	return &pb.ChatResp{Chat: &pb.Chat{ChatId: in.GetChatId(), UserId: in.GetUserId(), Title: in.GetTitle()}}, nil
+}
+
+func (f *fakeAI) DeleteChat(_ context.Context, in *pb.DeleteChatReq, _ ...grpc.CallOption) (*pb.ErrorResponse, error) {
+	f.record("DeleteChat", in)
+	return &pb.ErrorResponse{}, nil
+}
+
+func (f *fakeAI) GetChatHistory(_ context.Context, in *pb.HistoryReq, _ ...grpc.CallOption) (*pb.HistoryResp, error) {
+	f.record("GetChatHistory", in)
+	if f.history != nil {
+		return &pb.HistoryResp{ChatMessages: f.history}, nil
+	}
+	return &pb.HistoryResp{ChatMessages: []*pb.ChatMessage{{SearchQuery: "q", Papers: fakePapers(3)}}}, nil
+}
+
+func (f *fakeAI) SearchPaper(_ context.Context, in *pb.SearchRequest, _ ...grpc.CallOption) (*pb.PapersResponse, error) {
+	f.record("SearchPaper", in)
+	return fakePapers(f.results), nil
+}
+
+func fakePapers(n int) *pb.PapersResponse {
+	out := &pb.PapersResponse{}
+	for i := 0; i < n; i++ {
+		out.Papers = append(out.Papers, &pb.PaperResponse{ID: fmt.Sprintf("W%d", i), Title: fmt.Sprintf("Paper %d", i)})
+	}
+	return out
+}
+
+func newTestApp(t *testing.T, ai pb.SemanticServiceClient) *app.App {
+	t.Helper()
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, AI: ai}
+}
+
+// withPrincipal stands in for AuthMiddleware.
+func withPrincipal(p *auth.Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p != nil {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		}
+		c.Next()
+	}
+}
+
+// newTestRouter mounts ChatRouter-like routes under /api and /api/v2 for p.
+func newTestRouter(a *app.App, p *auth.Principal, extra ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1 := r.Group("/api", middlewares.SetAPIVersion(middlewares.APIV1, time.Time{}, time.Time{}, ""))
+	v2 := r.Group("/api/v2", middlewares.SetAPIVersion(middlewares.APIV2, time.Time{}, time.Time{}, ""), middlewares.Paging())
+	for _, g := range []*gin.RouterGroup{v1, v2} {
+		chats := g.Group("/chats", append([]gin.HandlerFunc{withPrincipal(p)}, extra...)...)
+		chats.POST("", func(ctx *gin.Context) { CreateChat(ctx, a) })
+		chats.GET("", func(ctx *gin.Context) { GetUserChats(ctx, a) })
+		chats.GET("/:chat_id/history", func(ctx *gin.Context) { GetChatHistory(ctx, a) })
+		chats.POST("/:chat_id/history", func(ctx *gin.Context) { CreateChatHistory(ctx, a) })
+		chats.POST("/:chat_id/history/:index/rerun", func(ctx *gin.Context) { RerunChatHistory(ctx, a) })
+		chats.PUT("/:chat_id", func(ctx *gin.Context) { UpdateChat(ctx, a) })
+		chats.DELETE("/:chat_id", func(ctx *gin.Context) { DeleteChat(ctx, a) })
+	}
+	return r
+}
+
+func do(r *gin.Engine, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
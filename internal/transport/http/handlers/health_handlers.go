@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz reports that the process is alive; it stays 200 while draining
+// so the orchestrator does not kill the instance mid-shutdown.
+func Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the instance should receive traffic: 503 until
+// startup completes and from the moment shutdown begins.
+func Readyz(ctx *gin.Context, a *app.App) {
+	if !a.Lifecycle.Ready() {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
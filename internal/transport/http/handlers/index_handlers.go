@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorAdd
+// @Summary Add author
+// @Description Add an author to the index (curator or admin only)
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param data body presenters.AddAuthorRequest true "Author data"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /ai/author/add [post]
+func AuthorAdd(ctx *gin.Context, a *app.App) {
+	var in presenters.AddAuthorRequest
+	if err := bindJSON(ctx, &in); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	req := &pb.Author{
+		FirstName:  in.First_name,
+		LastName:   in.Last_name,
+		MiddleName: in.Middle_name,
+		Orcid:      in.Orcid,
+	}
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	resp, err := a.AI.AddAuthor(rctx, req)
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithField("orcid", in.Orcid).Error("AI AddAuthor RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+	if msg := resp.GetError(); msg != "" {
+		ctx.JSON(http.StatusBadRequest, &presenters.ErrorResponse{Error: msg})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// InstitutionAdd
+// @Summary Add institution
+// @Description Add an institution to the index (curator or admin only)
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param data body presenters.AddInstitutionRequest true "Institution data"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /ai/institution/add [post]
+func InstitutionAdd(ctx *gin.Context, a *app.App) {
+	var in presenters.AddInstitutionRequest
+	if err := bindJSON(ctx, &in); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	req := &pb.Institution{
+		Name:    in.Name,
+		Country: in.Country,
+		RorId:   in.Ror_id,
+		GridId:  in.Grid_id,
+	}
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	resp, err := a.AI.AddInstitution(rctx, req)
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithField("name", in.Name).Error("AI AddInstitution RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+	if msg := resp.GetError(); msg != "" {
+		ctx.JSON(http.StatusBadRequest, &presenters.ErrorResponse{Error: msg})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	edgeReferences = "references"
+	edgeRelated    = "related"
+)
+
+// GetPaperGraph
+// @Summary Get citation graph
+// @Description Get the graph of papers reachable from a paper through references and/or related works, for visualization. Papers are fetched level by level in batches. depth is limited by PAPER_GRAPH_MAX_DEPTH and the number of nodes by PAPER_GRAPH_MAX_NODES, which sets truncated.
+// @Tags papers
+// @Produce json
+// @Param paper_id path string true "Paper ID"
+// @Param depth query int false "Levels below the paper (default 1)"
+// @Param direction query string false "Edges to follow: references, related or both (default references)"
+// @Success 200 {object} presenters.PaperGraphResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 502 {object} presenters.ErrorResponse
+// @Router /papers/{paper_id}/graph [get]
+func GetPaperGraph(ctx *gin.Context, a *app.App) {
+	limits := a.Config().PaperGraphConfig
+	depth, err := parseOptionalQueryInt64(ctx, "depth")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	if depth == 0 {
+		depth = 1
+	}
+	if depth > int64(limits.MaxDepth) {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("depth must not exceed %d", limits.MaxDepth)))
+		return
+	}
+	direction := ctx.DefaultQuery("direction", edgeReferences)
+	if direction != edgeReferences && direction != edgeRelated && direction != "both" {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("direction must be references, related or both")))
+		return
+	}
+
+	root, ok := fetchPaper(ctx, a)
+	if !ok {
+		return
+	}
+	g := newPaperGraph(root, int(depth), direction)
+	loader := newPaperLoader(a)
+	frontier := []*pb.PaperDetail{root}
+	for level := 1; level <= int(depth) && len(frontier) > 0; level++ {
+		ids := g.expand(frontier, direction, level, limits.MaxNodes)
+		papers, err := loadPapers(ctx.Request.Context(), loader, ids)
+		if err != nil {
+			respondPaperRPCError(ctx, a, err, "GetPapers", root.GetID())
+			return
+		}
+		frontier = frontier[:0]
+		for _, p := range papers {
+			if p != nil {
+				g.fill(p)
+				frontier = append(frontier, p)
+			}
+		}
+	}
+	render(ctx, http.StatusOK, g.out)
+}
+
+type paperGraph struct {
+	out   presenters.PaperGraphResponse
+	nodes map[string]int
+	edges map[presenters.PaperGraphEdge]bool
+}
+
+func newPaperGraph(root *pb.PaperDetail, depth int, direction string) *paperGraph {
+	g := &paperGraph{
+		out: presenters.PaperGraphResponse{
+			Root:      root.GetID(),
+			Depth:     depth,
+			Direction: direction,
+			Nodes:     []presenters.PaperGraphNode{},
+			Edges:     []presenters.PaperGraphEdge{},
+		},
+		nodes: map[string]int{},
+		edges: map[presenters.PaperGraphEdge]bool{},
+	}
+	g.out.Nodes = append(g.out.Nodes, presenters.PaperGraphNode{Id: root.GetID()})
+	g.nodes[root.GetID()] = 0
+	g.fill(root)
+	return g
+}
+
+// expand adds the edges leaving frontier and a node at level for every new
+// target while there is room for it. It returns the ids of the new nodes.
+func (g *paperGraph) expand(frontier []*pb.PaperDetail, direction string, level, maxNodes int) []string {
+	var ids []string
+	for _, p := range frontier {
+		if direction != edgeRelated {
+			ids = g.link(p.GetID(), referencedIDs(p), edgeReferences, level, maxNodes, ids)
+		}
+		if direction != edgeReferences {
+			ids = g.link(p.GetID(), relatedIDs(p), edgeRelated, level, maxNodes, ids)
+		}
+	}
+	return ids
+}
+
+func (g *paperGraph) link(source string, targets []string, kind string, level, maxNodes int, ids []string) []string {
+	for _, target := range targets {
+		if _, ok := g.nodes[target]; !ok {
+			if len(g.out.Nodes) >= maxNodes {
+				g.out.Truncated = true
+				continue
+			}
+			// Missing until the paper is loaded
+			g.nodes[target] = len(g.out.Nodes)
+			g.out.Nodes = append(g.out.Nodes, presenters.PaperGraphNode{Id: target, Depth: level, Missing: true})
+			ids = append(ids, target)
+		}
+		edge := presenters.PaperGraphEdge{Source: source, Target: target, Type: kind}
+		if !g.edges[edge] {
+			g.edges[edge] = true
+			g.out.Edges = append(g.out.Edges, edge)
+		}
+	}
+	return ids
+}
+
+func (g *paperGraph) fill(p *pb.PaperDetail) {
+	node := &g.out.Nodes[g.nodes[p.GetID()]]
+	node.Title = p.GetTitle()
+	node.Year = int(p.GetYear())
+	node.Missing = false
+}
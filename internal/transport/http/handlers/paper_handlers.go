@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"VKR_gateway_service/pkg/bibliography"
+	"VKR_gateway_service/pkg/dataloader"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	paperIDKey = "paper_id"
+
+	// Entries accepted in one bibliography import
+	maxImportEntries = 1000
+	// AddPaper calls in flight during an import
+	importParallelism = 8
+)
+
+// paperSubroutes are the routes below /papers/{paper_id}.
+var paperSubroutes = map[string]func(*gin.Context, *app.App){
+	"references": GetPaperReferences,
+	"related":    GetPaperRelated,
+	"graph":      GetPaperGraph,
+}
+
+// PaperRoute serves /papers/*paper_path. Paper ids (DOIs, OpenAlex urls) may
+// contain slashes, so the route is a catch-all and the last segment selects
+// a subroute.
+func PaperRoute(ctx *gin.Context, a *app.App) {
+	path := strings.Trim(ctx.Param("paper_path"), "/")
+	handler := GetPaper
+	if i := strings.LastIndex(path, "/"); i > 0 {
+		if sub, ok := paperSubroutes[path[i+1:]]; ok {
+			path, handler = path[:i], sub
+		}
+	}
+	if path == "" {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("paper_id path param is required")))
+		return
+	}
+	ctx.Set(paperIDKey, path)
+	handler(ctx, a)
+}
+
+// GetPaper
+// @Summary Get paper
+// @Description Get a paper from the index with the ids of its referenced and related works
+// @Tags papers
+// @Produce json
+// @Param paper_id path string true "Paper ID"
+// @Success 200 {object} presenters.PaperDetailResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 502 {object} presenters.ErrorResponse
+// @Router /papers/{paper_id} [get]
+func GetPaper(ctx *gin.Context, a *app.App) {
+	paper, ok := fetchPaper(ctx, a)
+	if !ok {
+		return
+	}
+	render(ctx, http.StatusOK, mapPaperDetail(paper))
+}
+
+// GetPaperReferences
+// @Summary Get paper references
+// @Description Get the papers referenced by a paper, in reference order. References missing from the index are left out.
+// @Tags papers
+// @Produce json
+// @Param paper_id path string true "Paper ID"
+// @Success 200 {object} presenters.SearchPaperResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 502 {object} presenters.ErrorResponse
+// @Router /papers/{paper_id}/references [get]
+func GetPaperReferences(ctx *gin.Context, a *app.App) {
+	paper, ok := fetchPaper(ctx, a)
+	if !ok {
+		return
+	}
+	renderLinkedPapers(ctx, a, referencedIDs(paper))
+}
+
+// GetPaperRelated
+// @Summary Get related papers
+// @Description Get the papers related to a paper. Related papers missing from the index are left out.
+// @Tags papers
+// @Produce json
+// @Param paper_id path string true "Paper ID"
+// @Success 200 {object} presenters.SearchPaperResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 502 {object} presenters.ErrorResponse
+// @Router /papers/{paper_id}/related [get]
+func GetPaperRelated(ctx *gin.Context, a *app.App) {
+	paper, ok := fetchPaper(ctx, a)
+	if !ok {
+		return
+	}
+	renderLinkedPapers(ctx, a, relatedIDs(paper))
+}
+
+// fetchPaper returns the paper selected by PaperRoute. Otherwise it writes an
+// error response and returns false.
+func fetchPaper(ctx *gin.Context, a *app.App) (*pb.PaperDetail, bool) {
+	paperID := ctx.GetString(paperIDKey)
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	paper, err := a.AI.GetPaper(rctx, &pb.PaperReq{ID: paperID})
+	if err != nil {
+		respondPaperRPCError(ctx, a, err, "GetPaper", paperID)
+		return nil, false
+	}
+	return paper, true
+}
+
+func renderLinkedPapers(ctx *gin.Context, a *app.App, ids []string) {
+	papers, err := loadPapers(ctx.Request.Context(), newPaperLoader(a), ids)
+	if err != nil {
+		respondPaperRPCError(ctx, a, err, "GetPapers", ctx.GetString(paperIDKey))
+		return
+	}
+	out := presenters.SearchPaperResponse{Papers: make([]presenters.Paper, 0, len(papers))}
+	for _, p := range papers {
+		if p != nil {
+			out.Papers = append(out.Papers, mapPaper(p))
+		}
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+func respondPaperRPCError(ctx *gin.Context, a *app.App, err error, rpc, paperID string) {
+	s, ok := status.FromError(err)
+	if a.Logger != nil && (!ok || s.Code() != codes.NotFound) {
+		a.Logger.WithError(err).WithField("paper_id", paperID).Errorf("AI %s RPC failed", rpc)
+	}
+	if ok {
+		ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf("%s", s.Message())))
+		return
+	}
+	ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+}
+
+// referencedIDs returns the referenced works of p without duplicates.
+func referencedIDs(p *pb.PaperDetail) []string {
+	ids := make([]string, 0, len(p.GetReferencedWorks()))
+	for _, w := range p.GetReferencedWorks() {
+		ids = append(ids, w.GetID())
+	}
+	return uniqueIDs(ids)
+}
+
+// relatedIDs returns the related works of p without duplicates.
+func relatedIDs(p *pb.PaperDetail) []string {
+	ids := make([]string, 0, len(p.GetRelatedWorks()))
+	for _, w := range p.GetRelatedWorks() {
+		ids = append(ids, w.GetID())
+	}
+	return uniqueIDs(ids)
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := ids[:0]
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func mapPaper(p *pb.PaperDetail) presenters.Paper {
+	return presenters.Paper{
+		Id:               p.GetID(),
+		Title:            p.GetTitle(),
+		Abstract:         p.GetAbstract(),
+		Year:             int(p.GetYear()),
+		Best_oa_location: p.GetBestOaLocation(),
+	}
+}
+
+func mapPaperDetail(p *pb.PaperDetail) presenters.PaperDetailResponse {
+	return presenters.PaperDetailResponse{
+		Paper:           mapPaper(p),
+		State:           p.GetState(),
+		ReferencedWorks: referencedIDs(p),
+		RelatedWorks:    relatedIDs(p),
+	}
+}
+
+// ImportPapers
+// @Summary Import papers from bibliography
+// @Description Import papers from a BibTeX (.bib) or RIS (.ris) file of at most 1000 entries. With dry_run=true entries are only parsed and validated.
+// @Tags ai
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "BibTeX or RIS file"
+// @Param format query string false "File format (bibtex or ris), detected from file name or content if omitted"
+// @Param dry_run query bool false "Validate only, do not add papers"
+// @Success 200 {object} presenters.ImportPapersResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 413 {object} presenters.ErrorResponse
+// @Router /ai/papers/import [post]
+func ImportPapers(ctx *gin.Context, a *app.App) {
+	dryRun := false
+	if raw := ctx.Query("dry_run"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("dry_run must be a boolean")))
+			return
+		}
+		dryRun = v
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("file is required")))
+		return
+	}
+	maxSize := a.Config().HttpServerConfig.MaxUploadSize
+	if fileHeader.Size > maxSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, presenters.Error(fmt.Errorf("file must not exceed %d bytes", maxSize)))
+		return
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxSize))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	var format bibliography.Format
+	if raw := ctx.Query("format"); raw != "" {
+		var ok bool
+		if format, ok = bibliography.ParseFormat(raw); !ok {
+			ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("format must be bibtex or ris")))
+			return
+		}
+	} else {
+		var ok bool
+		if format, ok = bibliography.DetectFormat(fileHeader.Filename, data); !ok {
+			ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("unable to detect file format, pass format=bibtex or format=ris")))
+			return
+		}
+	}
+
+	entries, parseErrs, err := bibliography.Parse(format, bytes.NewReader(data))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	if len(entries) > maxImportEntries {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("file has %d entries, at most %d can be imported at once", len(entries), maxImportEntries)))
+		return
+	}
+
+	out := presenters.ImportPapersResponse{
+		DryRun:  dryRun,
+		Format:  string(format),
+		Total:   len(entries) + len(parseErrs),
+		Entries: make([]presenters.ImportedPaper, 0, len(entries)),
+		Errors:  make([]presenters.ImportEntryError, 0, len(parseErrs)),
+	}
+	for _, pe := range parseErrs {
+		out.Errors = append(out.Errors, presenters.ImportEntryError{Line: pe.Line, Error: pe.Message})
+	}
+
+	// Valid entries are added by a bounded pool, results keep file order
+	type result struct {
+		line int
+		req  *pb.AddRequest
+		err  error
+	}
+	results := make([]result, 0, len(entries))
+	var pending []int
+	seen := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		req := bibliographyAddRequest(entry)
+		r := result{line: entry.Line, req: req}
+		if line, dup := seen[req.ID]; dup {
+			r.err = fmt.Errorf("duplicate of entry at line %d", line)
+		} else {
+			seen[req.ID] = entry.Line
+			r.err = validateImportedPaper(req)
+		}
+		if r.err == nil && !dryRun {
+			pending = append(pending, len(results))
+		}
+		results = append(results, r)
+	}
+	if len(pending) > 0 {
+		_, errs := dataloader.ForEach(ctx.Request.Context(), pending, importParallelism, func(rctx context.Context, i int) (struct{}, error) {
+			_, err := addPaper(rctx, a, results[i].req)
+			return struct{}{}, err
+		})
+		for n, i := range pending {
+			results[i].err = errs[n]
+		}
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			out.Errors = append(out.Errors, presenters.ImportEntryError{Line: r.line, Id: r.req.ID, Error: r.err.Error()})
+			continue
+		}
+		state := "valid"
+		if !dryRun {
+			state = "imported"
+			out.Imported++
+			middlewares.AuditTargets(ctx, "paper_id="+r.req.ID)
+		}
+		out.Entries = append(out.Entries, presenters.ImportedPaper{
+			Line:   r.line,
+			Id:     r.req.ID,
+			Title:  r.req.Title,
+			Status: state,
+		})
+	}
+	out.Failed = len(out.Errors)
+
+	if a.Logger != nil {
+		a.Logger.WithFields(map[string]interface{}{
+			"format":   format,
+			"dry_run":  dryRun,
+			"total":    out.Total,
+			"imported": out.Imported,
+			"failed":   out.Failed,
+		}).Info("Bibliography import finished")
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// bibliographyAddRequest maps a parsed entry to AddRequest. Papers without DOI get a
+// stable id derived from title and year so that re-importing a file does not duplicate them.
+func bibliographyAddRequest(entry bibliography.Entry) *pb.AddRequest {
+	req := &pb.AddRequest{
+		Title:          entry.Title,
+		Abstract:       entry.Abstract,
+		Year:           int64(entry.Year),
+		BestOaLocation: entry.URL,
+	}
+	if entry.DOI != "" {
+		req.ID = strings.ToLower(entry.DOI)
+		if req.BestOaLocation == "" {
+			req.BestOaLocation = "https://doi.org/" + entry.DOI
+		}
+		return req
+	}
+	sum := sha1.Sum([]byte(strings.ToLower(entry.Title) + "|" + strconv.Itoa(entry.Year)))
+	req.ID = "import-" + hex.EncodeToString(sum[:8])
+	return req
+}
+
+func validateImportedPaper(req *pb.AddRequest) error {
+	if strings.TrimSpace(req.GetTitle()) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if req.GetYear() != 0 && (req.GetYear() < 1000 || req.GetYear() > int64(time.Now().Year()+1)) {
+		return fmt.Errorf("year %d is out of range", req.GetYear())
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+// importAI adds papers slowly and records the most calls in flight.
+type importAI struct {
+	pb.SemanticServiceClient
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	added    []string
+}
+
+func (f *importAI) AddPaper(ctx context.Context, in *pb.AddRequest, _ ...grpc.CallOption) (*pb.ErrorResponse, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.peak = max(f.peak, f.inFlight)
+	f.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inFlight--
+	f.added = append(f.added, in.GetID())
+	if strings.Contains(in.GetTitle(), "rejected") {
+		return &pb.ErrorResponse{Error: "rejected by the index"}, nil
+	}
+	return &pb.ErrorResponse{}, nil
+}
+
+func bibtex(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		title := fmt.Sprintf("Paper %d", i)
+		if i == 3 {
+			title = "Paper rejected"
+		}
+		fmt.Fprintf(&b, "@article{p%d,\n  title = {%s},\n  year = {2020},\n  doi = {10.1000/%d}\n}\n", i, title, i)
+	}
+	return b.String()
+}
+
+func importPapers(t *testing.T, a *app.App, query, content string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "papers.bib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	mw.Close()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/ai/papers/import", func(ctx *gin.Context) { ImportPapers(ctx, a) })
+	req := httptest.NewRequest(http.MethodPost, "/api/ai/papers/import"+query, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportPapersRunsBoundedPool(t *testing.T) {
+	ai := &importAI{}
+	a := newTestApp(t, ai)
+	// A duplicate entry is reported and not added
+	content := bibtex(40) + "@article{dup,\n  title = {Paper 5},\n  year = {2020},\n  doi = {10.1000/5}\n}\n"
+
+	w := importPapers(t, a, "", content)
+	if w.Code != http.StatusOK {
+		t.Fatalf("import = %d: %s", w.Code, w.Body)
+	}
+	var out struct {
+		Imported int `json:"imported"`
+		Failed   int `json:"failed"`
+		Entries  []struct {
+			Line int    `json:"line"`
+			Id   string `json:"id"`
+		} `json:"entries"`
+		Errors []struct {
+			Id    string `json:"id"`
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Imported != 39 || out.Failed != 2 || len(ai.added) != 40 {
+		t.Fatalf("imported %d, failed %d, AddPaper called %d times: %s", out.Imported, out.Failed, len(ai.added), w.Body)
+	}
+	if ai.peak > importParallelism || ai.peak < 2 {
+		t.Fatalf("%d AddPaper calls in flight, want 2..%d", ai.peak, importParallelism)
+	}
+	for i := 1; i < len(out.Entries); i++ {
+		if out.Entries[i].Line <= out.Entries[i-1].Line {
+			t.Fatalf("entries out of file order: %+v", out.Entries)
+		}
+	}
+	if out.Errors[0].Id != "10.1000/3" || out.Errors[0].Error != "rejected by the index" ||
+		!strings.Contains(out.Errors[1].Error, "duplicate of entry") {
+		t.Fatalf("errors = %+v", out.Errors)
+	}
+}
+
+func TestImportPapersDryRunMakesNoCalls(t *testing.T) {
+	ai := &importAI{}
+	w := importPapers(t, newTestApp(t, ai), "?dry_run=true", bibtex(10))
+	if w.Code != http.StatusOK {
+		t.Fatalf("import = %d: %s", w.Code, w.Body)
+	}
+	if len(ai.added) != 0 {
+		t.Fatalf("dry run added %d papers", len(ai.added))
+	}
+}
+
+func TestImportPapersCapsEntries(t *testing.T) {
+	ai := &importAI{}
+	w := importPapers(t, newTestApp(t, ai), "", bibtex(maxImportEntries+1))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "at most 1000") {
+		t.Fatalf("import = %d: %s", w.Code, w.Body)
+	}
+	if len(ai.added) != 0 {
+		t.Fatalf("AddPaper called %d times for a refused file", len(ai.added))
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/pkg/dataloader"
+	"context"
+)
+
+const (
+	// IDs sent in one GetPapers call, the most the public RPC accepts
+	paperBatchSize = 100
+	// GetPapers calls in flight per batch
+	paperBatchParallelism = 4
+)
+
+type paperLoader = dataloader.Loader[string, *pb.PaperDetail]
+
+// newPaperLoader batches paper lookups of one request into GetPapers calls
+// of up to paperBatchSize IDs. Papers missing from the index load as nil.
+func newPaperLoader(a *app.App) *paperLoader {
+	return dataloader.New(func(ctx context.Context, ids []string) ([]*pb.PaperDetail, []error) {
+		starts := make([]int, 0, (len(ids)+paperBatchSize-1)/paperBatchSize)
+		for start := 0; start < len(ids); start += paperBatchSize {
+			starts = append(starts, start)
+		}
+		batches, batchErrs := dataloader.ForEach(ctx, starts, paperBatchParallelism, func(ctx context.Context, start int) ([]*pb.PaperDetail, error) {
+			rctx, cancel := rpcContext(ctx, a)
+			defer cancel()
+			resp, err := a.AI.GetPapers(rctx, &pb.PapersReq{IDs: ids[start:min(start+paperBatchSize, len(ids))]})
+			if err != nil {
+				return nil, err
+			}
+			return resp.GetPapers(), nil
+		})
+
+		values := make([]*pb.PaperDetail, len(ids))
+		errs := make([]error, len(ids))
+		for i, start := range starts {
+			end := min(start+paperBatchSize, len(ids))
+			if err := batchErrs[i]; err != nil {
+				for j := start; j < end; j++ {
+					errs[j] = err
+				}
+				continue
+			}
+			found := make(map[string]*pb.PaperDetail, len(batches[i]))
+			for _, p := range batches[i] {
+				found[p.GetID()] = p
+			}
+			for j := start; j < end; j++ {
+				values[j] = found[ids[j]]
+			}
+		}
+		return values, errs
+	})
+}
+
+// loadPapers fetches ids in as few batches as possible. The result is aligned
+// with ids, with nil for papers missing from the index.
+func loadPapers(ctx context.Context, loader *paperLoader, ids []string) ([]*pb.PaperDetail, error) {
+	thunks := make([]func() (*pb.PaperDetail, error), len(ids))
+	for i, id := range ids {
+		thunks[i] = loader.Load(ctx, id)
+	}
+	out := make([]*pb.PaperDetail, len(ids))
+	for i, thunk := range thunks {
+		p, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		out[i] = p
+	}
+	return out, nil
+}
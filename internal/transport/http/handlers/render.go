@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	presentersv2 "VKR_gateway_service/internal/transport/http/presenters/v2"
+
+	"github.com/gin-gonic/gin"
+)
+
+// render writes a v1 presenter in the shape of the API version of the route.
+func render(ctx *gin.Context, code int, out interface{}) {
+	if middlewares.GetAPIVersion(ctx) != middlewares.APIV2 {
+		ctx.JSON(code, out)
+		return
+	}
+	// Without a page, e.g. for the result of a POST, every item is returned
+	limit, offset, _ := middlewares.Page(ctx)
+	ctx.JSON(code, presentersv2.Present(out, limit, offset))
+}
+
+// bindJSON decodes the body into the v1 request in, reading the v2 shape on
+// v2 routes.
+func bindJSON(ctx *gin.Context, in interface{}) error {
+	if middlewares.GetAPIVersion(ctx) == middlewares.APIV2 {
+		if target, apply := presentersv2.BindTarget(in); target != nil {
+			if err := ctx.ShouldBindJSON(target); err != nil {
+				return err
+			}
+			apply()
+			return nil
+		}
+	}
+	return ctx.ShouldBindJSON(in)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/auth"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestV2PagingIsValidatedBeforeTheHandler(t *testing.T) {
+	ai := &fakeAI{chatOwners: map[int64]int64{7: 1}, results: 3}
+	r := newTestRouter(newTestApp(t, ai), &auth.Principal{UserID: 1})
+
+	for _, target := range []string{"/api/v2/chats/7/history?limit=x", "/api/v2/chats?offset=-1", "/api/v2/chats?limit=1000"} {
+		if w := do(r, http.MethodGet, target, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", target, w.Code)
+		}
+	}
+	if calls := len(ai.calls); calls != 0 {
+		t.Fatalf("AI service called %d times for invalid paging: %v", calls, ai.calls)
+	}
+}
+
+func TestV2PostResponsesAreNotPaged(t *testing.T) {
+	ai := &fakeAI{chatOwners: map[int64]int64{7: 1}, results: 80}
+	r := newTestRouter(newTestApp(t, ai), &auth.Principal{UserID: 1})
+
+	// limit is not a paging parameter of POST requests, the search runs once
+	w := do(r, http.MethodPost, "/api/v2/chats/7/history?limit=x", `{"text":"graphs"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST history = %d: %s", w.Code, w.Body)
+	}
+	var page struct {
+		Items  []json.RawMessage `json:"items"`
+		Paging struct {
+			Limit int  `json:"limit"`
+			Total *int `json:"total"`
+		} `json:"paging"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 80 || page.Paging.Total == nil || *page.Paging.Total != 80 {
+		t.Fatalf("got %d items, paging %+v, want all 80", len(page.Items), page.Paging)
+	}
+	if n := len(ai.called("SearchPaper")); n != 1 {
+		t.Fatalf("SearchPaper called %d times, want 1", n)
+	}
+}
+
+func TestV2GetIsPaged(t *testing.T) {
+	ai := &fakeAI{chatOwners: map[int64]int64{7: 1, 8: 1, 9: 1}}
+	r := newTestRouter(newTestApp(t, ai), &auth.Principal{UserID: 1})
+
+	w := do(r, http.MethodGet, "/api/v2/chats?limit=2&offset=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET chats = %d: %s", w.Code, w.Body)
+	}
+	var page struct {
+		Items  []json.RawMessage `json:"items"`
+		Paging struct {
+			Limit  int `json:"limit"`
+			Offset int `json:"offset"`
+			Total  int `json:"total"`
+		} `json:"paging"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Paging.Limit != 2 || page.Paging.Offset != 2 || page.Paging.Total != 3 {
+		t.Fatalf("page = %d items, %+v", len(page.Items), page.Paging)
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"VKR_gateway_service/pkg/securetoken"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+// errShareNotFound is returned for unknown, expired and revoked links alike
+// so that a token holder cannot learn anything about the share state.
+var errShareNotFound = errors.New("share link not found or expired")
+
+// CreateChatShare
+// @Summary Share chat
+// @Description Create a read-only link to the chat. The token is returned only once.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param chat_id path int true "Chat ID"
+// @Param data body presenters.CreateChatShareRequest false "Share options"
+// @Success 200 {object} presenters.ChatShareResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /chats/{chat_id}/share [post]
+func CreateChatShare(ctx *gin.Context, a *app.App) {
+	chatID, err := parsePathInt64(ctx, "chat_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+	var in presenters.CreateChatShareRequest
+	if err := bindJSON(ctx, &in); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	cfg := a.Config()
+	ttl := cfg.ShareDefaultTTL
+	if in.ExpiresInHours < 0 {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("expires_in_hours must be positive")))
+		return
+	}
+	if in.ExpiresInHours > 0 {
+		ttl = time.Duration(in.ExpiresInHours) * time.Hour
+	}
+	if cfg.ShareMaxTTL > 0 && ttl > cfg.ShareMaxTTL {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("expires_in_hours must not exceed %d", int(cfg.ShareMaxTTL/time.Hour))))
+		return
+	}
+	chat, ok := findUserChat(ctx, a, userID, chatID)
+	if !ok {
+		return
+	}
+
+	token, err := securetoken.Generate(securetoken.DefaultSize)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Generate share token failed")
+		return
+	}
+	share, err := a.Shares.CreateShare(ctx.Request.Context(), &domain.ChatShare{
+		ChatID:    chatID,
+		UserID:    userID,
+		Title:     chat.GetTitle(),
+		TokenHash: securetoken.Hash(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Create chat share failed")
+		return
+	}
+	middlewares.AuditTargets(ctx, fmt.Sprintf("share_id=%d", share.ID))
+	out := mapChatShare(share)
+	out.Token = token
+	out.Url = sharedChatURL(a, token)
+	render(ctx, http.StatusOK, out)
+}
+
+// GetChatShares
+// @Summary Get chat shares
+// @Description Get all share links of the chat including expired and revoked ones
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param chat_id path int true "Chat ID"
+// @Success 200 {object} presenters.ChatSharesResponse
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /chats/{chat_id}/share [get]
+func GetChatShares(ctx *gin.Context, a *app.App) {
+	chatID, err := parsePathInt64(ctx, "chat_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+
+	shares, err := a.Shares.GetChatShares(ctx.Request.Context(), userID, chatID)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Get chat shares failed")
+		return
+	}
+	out := presenters.ChatSharesResponse{Shares: make([]presenters.ChatShareResponse, 0, len(shares))}
+	for i := range shares {
+		out.Shares = append(out.Shares, mapChatShare(&shares[i]))
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// RevokeChatShare
+// @Summary Revoke chat share
+// @Description Revoke a share link so that it stops working immediately
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param chat_id path int true "Chat ID"
+// @Param share_id path int true "Share ID"
+// @Success 200
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /chats/{chat_id}/share/{share_id} [delete]
+func RevokeChatShare(ctx *gin.Context, a *app.App) {
+	chatID, err := parsePathInt64(ctx, "chat_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	shareID, err := parsePathInt64(ctx, "share_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+	userID, statusCode, err := currentUserID(ctx)
+	if err != nil {
+		ctx.JSON(statusCode, presenters.Error(err))
+		return
+	}
+
+	if err := a.Shares.RevokeShare(ctx.Request.Context(), userID, chatID, shareID); err != nil {
+		respondRepositoryError(ctx, a, err, "Revoke chat share failed")
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// GetSharedChat
+// @Summary Get shared chat
+// @Description Public read-only view of a shared chat history
+// @Tags shared
+// @Accept json
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} presenters.SharedChatResponse
+// @Failure 404 {object} presenters.ErrorResponse
+// @Failure 500 {object} presenters.ErrorResponse
+// @Router /shared/{token} [get]
+func GetSharedChat(ctx *gin.Context, a *app.App) {
+	token := strings.TrimSpace(ctx.Param("token"))
+	if token == "" {
+		ctx.JSON(http.StatusNotFound, presenters.Error(errShareNotFound))
+		return
+	}
+	share, err := a.Shares.GetShareByTokenHash(ctx.Request.Context(), securetoken.Hash(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, presenters.Error(errShareNotFound))
+			return
+		}
+		respondRepositoryError(ctx, a, err, "Get chat share failed")
+		return
+	}
+	if !share.Active(time.Now()) {
+		ctx.JSON(http.StatusNotFound, presenters.Error(errShareNotFound))
+		return
+	}
+
+	rctx, cancel := requestContext(ctx, a)
+	defer cancel()
+	resp, err := a.AI.GetChatHistory(rctx, &pb.HistoryReq{ChatId: share.ChatID})
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.WithError(err).WithField("share_id", share.ID).Error("AI GetChatHistory RPC failed")
+		}
+		if s, ok := status.FromError(err); ok {
+			ctx.JSON(mapGRPCToHTTP(s.Code()), presenters.Error(fmt.Errorf(s.Message())))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, presenters.Error(err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("X-Robots-Tag", "noindex")
+	render(ctx, http.StatusOK, presenters.SharedChatResponse{
+		Title:        share.Title,
+		ExpiresAt:    share.ExpiresAt.Format(time.RFC3339),
+		ChatMessages: mapChatMessages(resp.GetChatMessages()),
+	})
+}
+
+func sharedChatURL(a *app.App, token string) string {
+	path := "/api/shared/" + token
+	publicURL := a.Config().PublicURL
+	if publicURL == "" {
+		return path
+	}
+	return strings.TrimRight(publicURL, "/") + path
+}
+
+func mapChatShare(s *domain.ChatShare) presenters.ChatShareResponse {
+	out := presenters.ChatShareResponse{
+		ShareId:   s.ID,
+		ChatId:    s.ChatID,
+		CreatedAt: s.CreatedAt.Format(time.RFC3339),
+		ExpiresAt: s.ExpiresAt.Format(time.RFC3339),
+		Active:    s.Active(time.Now()),
+	}
+	if s.RevokedAt != nil {
+		revoked := s.RevokedAt.Format(time.RFC3339)
+		out.RevokedAt = &revoked
+	}
+	return out
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"VKR_gateway_service/pkg/securetoken"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Holds the state, PKCE verifier and redirect while the user logs in
+	oauthStateCookie = "alib_oauth"
+
+	// The refresh token and login state are only sent to the SSO routes
+	// of every API version
+	apiCookiePath = "/api"
+)
+
+var errSSODisabled = errors.New("SSO login is not configured")
+
+type oauthState struct {
+	State    string `json:"state"`
+	Verifier string `json:"verifier"`
+	Redirect string `json:"redirect"`
+}
+
+// SSOLogin
+// @Summary Start SSO login
+// @Description Redirect the browser to the SSO login page (authorization code flow with PKCE). After login the browser returns to redirect_uri with the session cookies set, or with sso_error in the query.
+// @Tags sso
+// @Param redirect_uri query string false "Where to return after login, must match ALLOWED_REDIRECT_URLS; defaults to the first of them"
+// @Success 302
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 503 {object} presenters.ErrorResponse
+// @Router /sso/login [get]
+func SSOLogin(ctx *gin.Context, a *app.App) {
+	cfg := a.Config()
+	if !cfg.SSOOAuthConfig.Enabled() {
+		ctx.JSON(http.StatusServiceUnavailable, presenters.Error(errSSODisabled))
+		return
+	}
+	redirect := ctx.Query("redirect_uri")
+	if redirect == "" && len(cfg.AllowedRedirectURLs) > 0 {
+		redirect = cfg.AllowedRedirectURLs[0]
+	}
+	if !allowedRedirect(cfg.AllowedRedirectURLs, redirect) {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("redirect_uri is not allowed")))
+		return
+	}
+	state, err := securetoken.Generate(securetoken.DefaultSize)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Generate login state failed")
+		return
+	}
+	verifier, challenge, err := auth.NewPKCE()
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Generate PKCE verifier failed")
+		return
+	}
+	raw, err := json.Marshal(oauthState{State: state, Verifier: verifier, Redirect: redirect})
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Encode login state failed")
+		return
+	}
+	setCookie(ctx, cfg, oauthStateCookie, base64.RawURLEncoding.EncodeToString(raw), apiCookiePath, cfg.SSOOAuthConfig.LoginTimeout, true)
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Redirect(http.StatusFound, auth.NewOAuthClient(cfg).AuthCodeURL(state, challenge))
+}
+
+// SSOCallback
+// @Summary Finish SSO login
+// @Description The SSO redirects here after login. The code is exchanged for tokens, which are stored in HttpOnly cookies, and the browser is sent to the redirect_uri given at login.
+// @Tags sso
+// @Param code query string false "Authorization code"
+// @Param state query string true "Login state"
+// @Param error query string false "Error reported by the SSO"
+// @Success 302
+// @Failure 400 {object} presenters.ErrorResponse
+// @Failure 503 {object} presenters.ErrorResponse
+// @Router /sso/callback [get]
+func SSOCallback(ctx *gin.Context, a *app.App) {
+	cfg := a.Config()
+	if !cfg.SSOOAuthConfig.Enabled() {
+		ctx.JSON(http.StatusServiceUnavailable, presenters.Error(errSSODisabled))
+		return
+	}
+	login, ok := readOAuthState(ctx)
+	clearCookie(ctx, cfg, oauthStateCookie, apiCookiePath, true)
+	// A missing or different state means the login was not started by this
+	// browser, e.g. a forged callback link
+	if !ok || subtle.ConstantTimeCompare([]byte(login.State), []byte(ctx.Query("state"))) != 1 {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("login state does not match, start the login again")))
+		return
+	}
+	if !allowedRedirect(cfg.AllowedRedirectURLs, login.Redirect) {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("redirect_uri is not allowed")))
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	if code := ctx.Query("error"); code != "" {
+		redirectWithError(ctx, login.Redirect, code)
+		return
+	}
+	code := ctx.Query("code")
+	if code == "" {
+		redirectWithError(ctx, login.Redirect, "invalid_request")
+		return
+	}
+
+	tokens, err := auth.NewOAuthClient(cfg).Exchange(ctx.Request.Context(), code, login.Verifier)
+	if err != nil {
+		a.Logger.WithError(err).Warn("SSO code exchange failed")
+		var oauthErr *auth.OAuthError
+		if errors.As(err, &oauthErr) {
+			redirectWithError(ctx, login.Redirect, oauthErr.Code)
+			return
+		}
+		redirectWithError(ctx, login.Redirect, "server_error")
+		return
+	}
+	csrf, err := securetoken.Generate(securetoken.DefaultSize)
+	if err != nil {
+		respondRepositoryError(ctx, a, err, "Generate CSRF token failed")
+		return
+	}
+	setSession(ctx, cfg, tokens, csrf)
+	ctx.Redirect(http.StatusFound, login.Redirect)
+}
+
+// SSORefresh
+// @Summary Refresh SSO session
+// @Description Obtain a new access token with the refresh token cookie. Requires the X-CSRF-Token header equal to the alib_csrf cookie and an allowed Origin.
+// @Tags sso
+// @Produce json
+// @Param X-CSRF-Token header string true "Value of the alib_csrf cookie"
+// @Success 200 {object} presenters.SSOSessionResponse
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 403 {object} presenters.ProblemResponse
+// @Failure 502 {object} presenters.ErrorResponse
+// @Failure 503 {object} presenters.ErrorResponse
+// @Router /sso/refresh [post]
+func SSORefresh(ctx *gin.Context, a *app.App) {
+	middlewares.AuditAction(ctx, "sso.refresh", "session")
+	cfg := a.Config()
+	if !cfg.SSOOAuthConfig.Enabled() {
+		ctx.JSON(http.StatusServiceUnavailable, presenters.Error(errSSODisabled))
+		return
+	}
+	refresh, err := ctx.Cookie(middlewares.RefreshTokenCookie)
+	if err != nil || refresh == "" {
+		ctx.JSON(http.StatusUnauthorized, presenters.Error(fmt.Errorf("no session, log in first")))
+		return
+	}
+
+	tokens, err := auth.NewOAuthClient(cfg).Refresh(ctx.Request.Context(), refresh)
+	if err != nil {
+		var oauthErr *auth.OAuthError
+		if errors.As(err, &oauthErr) && oauthErr.Code == "invalid_grant" {
+			clearSession(ctx, cfg)
+			ctx.JSON(http.StatusUnauthorized, presenters.Error(fmt.Errorf("session expired, log in again")))
+			return
+		}
+		a.Logger.WithError(err).Warn("SSO token refresh failed")
+		ctx.JSON(http.StatusBadGateway, presenters.Error(fmt.Errorf("SSO is not available")))
+		return
+	}
+	csrf, _ := ctx.Cookie(middlewares.CSRFCookie)
+	setSession(ctx, cfg, tokens, csrf)
+	ctx.Header("Cache-Control", "no-store")
+	out := presenters.SSOSessionResponse{}
+	if tokens.ExpiresIn > 0 {
+		out.ExpiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second).UTC().Format(time.RFC3339)
+	}
+	render(ctx, http.StatusOK, out)
+}
+
+// SSOLogout
+// @Summary Log out
+// @Description Revoke the refresh token at the SSO and clear the session cookies. Requires the X-CSRF-Token header equal to the alib_csrf cookie and an allowed Origin.
+// @Tags sso
+// @Param X-CSRF-Token header string true "Value of the alib_csrf cookie"
+// @Success 200
+// @Failure 403 {object} presenters.ProblemResponse
+// @Router /sso/logout [post]
+func SSOLogout(ctx *gin.Context, a *app.App) {
+	cfg := a.Config()
+	refresh, _ := ctx.Cookie(middlewares.RefreshTokenCookie)
+	if refresh == "" && middlewares.SessionToken(ctx) == "" {
+		clearSession(ctx, cfg)
+		ctx.Status(http.StatusOK)
+		return
+	}
+	// Only logouts of a session are audited
+	middlewares.AuditAction(ctx, "sso.logout", "session")
+	if refresh != "" && cfg.SSOOAuthConfig.Enabled() {
+		// The cookies are cleared anyway, a failed revocation only leaves
+		// the token valid at the SSO until it expires
+		if err := auth.NewOAuthClient(cfg).Revoke(ctx.Request.Context(), refresh); err != nil {
+			a.Logger.WithError(err).Warn("SSO token revocation failed")
+		}
+	}
+	clearSession(ctx, cfg)
+	ctx.Status(http.StatusOK)
+}
+
+// allowedRedirect reports whether raw has the scheme and host of an allowed
+// URL and a path below its path.
+func allowedRedirect(allowed []string, raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return false
+	}
+	for _, a := range allowed {
+		base, err := url.Parse(a)
+		if err != nil || base.Scheme != u.Scheme || !strings.EqualFold(base.Host, u.Host) {
+			continue
+		}
+		prefix := strings.TrimSuffix(base.Path, "/")
+		if u.Path == prefix || strings.HasPrefix(u.Path, prefix+"/") || (prefix == "" && u.Path == "") {
+			return true
+		}
+	}
+	return false
+}
+
+func redirectWithError(ctx *gin.Context, redirect, code string) {
+	u, err := url.Parse(redirect)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(fmt.Errorf("redirect_uri is not allowed")))
+		return
+	}
+	q := u.Query()
+	q.Set("sso_error", code)
+	u.RawQuery = q.Encode()
+	ctx.Redirect(http.StatusFound, u.String())
+}
+
+func readOAuthState(ctx *gin.Context) (*oauthState, bool) {
+	raw, err := ctx.Cookie(oauthStateCookie)
+	if err != nil || raw == "" {
+		return nil, false
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, false
+	}
+	var login oauthState
+	if err := json.Unmarshal(buf, &login); err != nil || login.State == "" || login.Verifier == "" {
+		return nil, false
+	}
+	return &login, true
+}
+
+// setSession stores the tokens in HttpOnly cookies and csrf in a cookie
+// scripts of the app can read.
+func setSession(ctx *gin.Context, cfg *config.Config, tokens *auth.Tokens, csrf string) {
+	oauth := cfg.SSOOAuthConfig
+	setCookie(ctx, cfg, middlewares.AccessTokenCookie, tokens.AccessToken, "/", time.Duration(tokens.ExpiresIn)*time.Second, true)
+	if tokens.RefreshToken != "" {
+		setCookie(ctx, cfg, middlewares.RefreshTokenCookie, tokens.RefreshToken, apiCookiePath, oauth.RefreshTTL, true)
+	}
+	setCookie(ctx, cfg, middlewares.CSRFCookie, csrf, "/", oauth.RefreshTTL, false)
+}
+
+func clearSession(ctx *gin.Context, cfg *config.Config) {
+	clearCookie(ctx, cfg, middlewares.AccessTokenCookie, "/", true)
+	clearCookie(ctx, cfg, middlewares.RefreshTokenCookie, apiCookiePath, true)
+	clearCookie(ctx, cfg, middlewares.CSRFCookie, "/", false)
+}
+
+// setCookie sets a SameSite=Lax cookie; maxAge 0 makes it a session cookie.
+func setCookie(ctx *gin.Context, cfg *config.Config, name, value, path string, maxAge time.Duration, httpOnly bool) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cfg.SSOOAuthConfig.CookieDomain,
+		MaxAge:   int(maxAge / time.Second),
+		Secure:   cfg.SSOOAuthConfig.CookieSecure,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearCookie(ctx *gin.Context, cfg *config.Config, name, path string, httpOnly bool) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     name,
+		Path:     path,
+		Domain:   cfg.SSOOAuthConfig.CookieDomain,
+		MaxAge:   -1,
+		Secure:   cfg.SSOOAuthConfig.CookieSecure,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/repository/repotest"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testPublicURL = "https://gateway.example"
+	testAppURL    = "https://app.example/after"
+)
+
+// fakeSSO is the authorization server of the flow. Codes are issued by
+// authorize for a PKCE challenge and can be exchanged once.
+type fakeSSO struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	codes     map[string]string // code -> challenge
+	refreshes map[string]bool   // valid refresh tokens
+	issued    int
+	grants    []string
+	revoked   []string
+}
+
+func newFakeSSO(t *testing.T) *fakeSSO {
+	f := &fakeSSO{codes: map[string]string{}, refreshes: map[string]bool{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", f.token)
+	mux.HandleFunc("/oauth/revoke", f.revoke)
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeSSO) authorize(challenge string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	code := fmt.Sprintf("code-%d", len(f.codes))
+	f.codes[code] = challenge
+	return code
+}
+
+func (f *fakeSSO) grantCount(grant string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, g := range f.grants {
+		if g == grant {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeSSO) token(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id, _, ok := r.BasicAuth(); !ok || id != "gateway" {
+		oauthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	grant := r.PostFormValue("grant_type")
+	f.grants = append(f.grants, grant)
+	switch grant {
+	case "authorization_code":
+		challenge, ok := f.codes[r.PostFormValue("code")]
+		delete(f.codes, r.PostFormValue("code"))
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge ||
+			r.PostFormValue("redirect_uri") != testPublicURL+auth.CallbackPath {
+			oauthError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+	case "refresh_token":
+		if !f.refreshes[r.PostFormValue("refresh_token")] {
+			oauthError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		// Refresh tokens are rotated
+		delete(f.refreshes, r.PostFormValue("refresh_token"))
+	default:
+		oauthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+	f.issued++
+	refresh := fmt.Sprintf("refresh-%d", f.issued)
+	f.refreshes[refresh] = true
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(auth.Tokens{
+		AccessToken:  fmt.Sprintf("access-%d", f.issued),
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    300,
+	})
+}
+
+func (f *fakeSSO) revoke(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	token := r.PostFormValue("token")
+	f.revoked = append(f.revoked, token)
+	delete(f.refreshes, token)
+}
+
+func oauthError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func newSSOTestRouter(t *testing.T, sso *fakeSSO) (*gin.Engine, *app.App) {
+	a := newTestApp(t, nil)
+	cfg := a.Config()
+	cfg.SSO_HTTP_URL = sso.URL
+	cfg.PublicURL = testPublicURL
+	cfg.AllowedRedirectURLs = []string{"https://app.example"}
+	cfg.SSOOAuthConfig.ClientID = "gateway"
+	cfg.SSOOAuthConfig.ClientSecret = "secret"
+	a.Audit = &repotest.AuditRepository{}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	g := r.Group("/api/sso", middlewares.CSRF(a), middlewares.Audit(a))
+	g.GET("/login", func(ctx *gin.Context) { SSOLogin(ctx, a) })
+	g.GET("/callback", func(ctx *gin.Context) { SSOCallback(ctx, a) })
+	g.POST("/refresh", func(ctx *gin.Context) { SSORefresh(ctx, a) })
+	g.POST("/logout", func(ctx *gin.Context) { SSOLogout(ctx, a) })
+	return r, a
+}
+
+// jar keeps the cookies a browser would send back to the gateway.
+type jar map[string]*http.Cookie
+
+func (j jar) update(w *httptest.ResponseRecorder) {
+	for _, c := range w.Result().Cookies() {
+		if c.MaxAge < 0 {
+			delete(j, c.Name)
+		} else {
+			j[c.Name] = c
+		}
+	}
+}
+
+func (j jar) header() string {
+	var parts []string
+	for _, c := range j {
+		parts = append(parts, c.Name+"="+c.Value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (j jar) value(name string) string {
+	if c, ok := j[name]; ok {
+		return c.Value
+	}
+	return ""
+}
+
+// login runs the browser through login and callback and returns its cookies.
+func login(t *testing.T, r *gin.Engine, sso *fakeSSO) jar {
+	t.Helper()
+	cookies := jar{}
+	w := do(r, http.MethodGet, "/api/sso/login?redirect_uri="+url.QueryEscape(testAppURL), "")
+	if w.Code != http.StatusFound {
+		t.Fatalf("login = %d: %s", w.Code, w.Body)
+	}
+	cookies.update(w)
+	if cookies[oauthStateCookie] == nil || !cookies[oauthStateCookie].HttpOnly {
+		t.Fatalf("login state cookie = %+v", cookies[oauthStateCookie])
+	}
+
+	authorize, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := authorize.Query()
+	if !strings.HasPrefix(authorize.String(), sso.URL+"/oauth/authorize?") ||
+		q.Get("response_type") != "code" || q.Get("client_id") != "gateway" ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" ||
+		q.Get("redirect_uri") != testPublicURL+auth.CallbackPath || q.Get("state") == "" {
+		t.Fatalf("authorize URL = %s", authorize)
+	}
+
+	code := sso.authorize(q.Get("code_challenge"))
+	w = do(r, http.MethodGet, "/api/sso/callback?code="+code+"&state="+url.QueryEscape(q.Get("state")), "", "Cookie", cookies.header())
+	if w.Code != http.StatusFound || w.Header().Get("Location") != testAppURL {
+		t.Fatalf("callback = %d to %q: %s", w.Code, w.Header().Get("Location"), w.Body)
+	}
+	cookies.update(w)
+	if _, ok := cookies[oauthStateCookie]; ok {
+		t.Fatal("login state cookie not cleared by the callback")
+	}
+	return cookies
+}
+
+func auditedActions(a *app.App) []string {
+	var actions []string
+	for _, e := range a.Audit.(*repotest.AuditRepository).Entries() {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+func TestSSOLoginFlow(t *testing.T) {
+	sso := newFakeSSO(t)
+	r, a := newSSOTestRouter(t, sso)
+
+	cookies := login(t, r, sso)
+	access, refresh, csrf := cookies[middlewares.AccessTokenCookie], cookies[middlewares.RefreshTokenCookie], cookies[middlewares.CSRFCookie]
+	if access == nil || access.Value != "access-1" || !access.HttpOnly || access.Path != "/" {
+		t.Fatalf("access cookie = %+v", access)
+	}
+	if refresh == nil || refresh.Value != "refresh-1" || !refresh.HttpOnly || refresh.Path != apiCookiePath {
+		t.Fatalf("refresh cookie = %+v", refresh)
+	}
+	if csrf == nil || csrf.Value == "" || csrf.HttpOnly {
+		t.Fatalf("CSRF cookie = %+v", csrf)
+	}
+
+	// Refresh rotates the tokens and keeps the CSRF token
+	w := do(r, http.MethodPost, "/api/sso/refresh", "", "Cookie", cookies.header(),
+		"Origin", testPublicURL, middlewares.CSRFHeader, csrf.Value)
+	if w.Code != http.StatusOK {
+		t.Fatalf("refresh = %d: %s", w.Code, w.Body)
+	}
+	cookies.update(w)
+	if cookies.value(middlewares.AccessTokenCookie) != "access-2" || cookies.value(middlewares.RefreshTokenCookie) != "refresh-2" ||
+		cookies.value(middlewares.CSRFCookie) != csrf.Value {
+		t.Fatalf("cookies after refresh = %s", cookies.header())
+	}
+
+	// Logout revokes the refresh token and clears the session
+	w = do(r, http.MethodPost, "/api/sso/logout", "", "Cookie", cookies.header(),
+		"Origin", testPublicURL, middlewares.CSRFHeader, csrf.Value)
+	if w.Code != http.StatusOK {
+		t.Fatalf("logout = %d: %s", w.Code, w.Body)
+	}
+	cookies.update(w)
+	if len(cookies) != 0 {
+		t.Fatalf("cookies after logout = %s", cookies.header())
+	}
+	if len(sso.revoked) != 1 || sso.revoked[0] != "refresh-2" {
+		t.Fatalf("revoked = %v, want [refresh-2]", sso.revoked)
+	}
+	// Logging out again without a session is not audited
+	if w := do(r, http.MethodPost, "/api/sso/logout", ""); w.Code != http.StatusOK {
+		t.Fatalf("logout without session = %d", w.Code)
+	}
+	if got := auditedActions(a); !reflect.DeepEqual(got, []string{"sso.refresh", "sso.logout"}) {
+		t.Fatalf("audited %v, want the refresh and the logout", got)
+	}
+
+	// The revoked token no longer refreshes
+	old := jar{
+		middlewares.RefreshTokenCookie: {Name: middlewares.RefreshTokenCookie, Value: "refresh-2"},
+		middlewares.CSRFCookie:         csrf,
+	}
+	w = do(r, http.MethodPost, "/api/sso/refresh", "", "Cookie", old.header(),
+		"Origin", testPublicURL, middlewares.CSRFHeader, csrf.Value)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("refresh after logout = %d: %s", w.Code, w.Body)
+	}
+	if got := auditedActions(a); len(got) != 2 {
+		t.Fatalf("failed anonymous refresh audited: %v", got)
+	}
+}
+
+func TestSSOCallbackRejectsForgedLogins(t *testing.T) {
+	sso := newFakeSSO(t)
+	r, _ := newSSOTestRouter(t, sso)
+
+	start := func() (jar, url.Values) {
+		cookies := jar{}
+		w := do(r, http.MethodGet, "/api/sso/login", "")
+		cookies.update(w)
+		authorize, _ := url.Parse(w.Header().Get("Location"))
+		return cookies, authorize.Query()
+	}
+
+	t.Run("state mismatch", func(t *testing.T) {
+		cookies, q := start()
+		code := sso.authorize(q.Get("code_challenge"))
+		w := do(r, http.MethodGet, "/api/sso/callback?code="+code+"&state=forged", "", "Cookie", cookies.header())
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("callback = %d, want 400", w.Code)
+		}
+	})
+	t.Run("no login state cookie", func(t *testing.T) {
+		_, q := start()
+		code := sso.authorize(q.Get("code_challenge"))
+		w := do(r, http.MethodGet, "/api/sso/callback?code="+code+"&state="+url.QueryEscape(q.Get("state")), "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("callback = %d, want 400", w.Code)
+		}
+	})
+	t.Run("code issued for another verifier", func(t *testing.T) {
+		cookies, q := start()
+		_, otherChallenge, _ := auth.NewPKCE()
+		code := sso.authorize(otherChallenge)
+		w := do(r, http.MethodGet, "/api/sso/callback?code="+code+"&state="+url.QueryEscape(q.Get("state")), "", "Cookie", cookies.header())
+		if w.Code != http.StatusFound || w.Header().Get("Location") != "https://app.example?sso_error=invalid_grant" {
+			t.Fatalf("callback = %d to %q", w.Code, w.Header().Get("Location"))
+		}
+		cookies.update(w)
+		if cookies.value(middlewares.AccessTokenCookie) != "" {
+			t.Fatal("session set for a failed exchange")
+		}
+	})
+	t.Run("SSO error", func(t *testing.T) {
+		cookies, q := start()
+		w := do(r, http.MethodGet, "/api/sso/callback?error=access_denied&state="+url.QueryEscape(q.Get("state")), "", "Cookie", cookies.header())
+		if w.Code != http.StatusFound || w.Header().Get("Location") != "https://app.example?sso_error=access_denied" {
+			t.Fatalf("callback = %d to %q", w.Code, w.Header().Get("Location"))
+		}
+	})
+	t.Run("redirect not allowed", func(t *testing.T) {
+		w := do(r, http.MethodGet, "/api/sso/login?redirect_uri="+url.QueryEscape("https://evil.example/"), "")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("login = %d, want 400", w.Code)
+		}
+	})
+}
+
+func TestSSORoutesRequireCSRFToken(t *testing.T) {
+	sso := newFakeSSO(t)
+	r, a := newSSOTestRouter(t, sso)
+	cookies := login(t, r, sso)
+	csrf := cookies.value(middlewares.CSRFCookie)
+
+	tests := []struct {
+		name    string
+		headers []string
+	}{
+		{"no token", []string{"Origin", testPublicURL}},
+		{"wrong token", []string{"Origin", testPublicURL, middlewares.CSRFHeader, csrf + "x"}},
+		{"no origin", []string{middlewares.CSRFHeader, csrf}},
+		{"null origin", []string{"Origin", "null", middlewares.CSRFHeader, csrf}},
+		{"foreign origin", []string{"Origin", "https://evil.example", middlewares.CSRFHeader, csrf}},
+		{"foreign referer", []string{"Referer", "https://evil.example/page", middlewares.CSRFHeader, csrf}},
+	}
+	for _, path := range []string{"/api/sso/refresh", "/api/sso/logout"} {
+		for _, tt := range tests {
+			t.Run(path+" "+tt.name, func(t *testing.T) {
+				w := do(r, http.MethodPost, path, "", append([]string{"Cookie", cookies.header()}, tt.headers...)...)
+				if w.Code != http.StatusForbidden {
+					t.Fatalf("status = %d, want 403: %s", w.Code, w.Body)
+				}
+				if len(w.Result().Cookies()) != 0 {
+					t.Fatal("session cookies changed by a rejected request")
+				}
+			})
+		}
+	}
+	if n := sso.grantCount("refresh_token"); n != 0 || len(sso.revoked) != 0 {
+		t.Fatalf("SSO got %d refreshes and %d revocations for rejected requests", n, len(sso.revoked))
+	}
+	if got := auditedActions(a); len(got) != 0 {
+		t.Fatalf("rejected requests audited: %v", got)
+	}
+
+	// The Referer is accepted when a browser omits Origin
+	w := do(r, http.MethodPost, "/api/sso/refresh", "", "Cookie", cookies.header(),
+		"Referer", testPublicURL+"/library", middlewares.CSRFHeader, csrf)
+	if w.Code != http.StatusOK {
+		t.Fatalf("refresh with Referer = %d: %s", w.Code, w.Body)
+	}
+}
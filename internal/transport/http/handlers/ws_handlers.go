@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/events"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Clients only receive events, anything larger than a close frame is unexpected
+	wsReadLimit      = 1 << 10
+	wsPublishTimeout = 2 * time.Second
+)
+
+// ChatEvents
+// @Summary Live chat updates
+// @Description Upgrade to a WebSocket that receives JSON events (chat.created, chat.updated, chat.deleted, chat.history.created) of the current user from all devices. Browsers pass the token as subprotocols ["bearer", token].
+// @Tags chat
+// @Success 101
+// @Failure 401 {object} presenters.ErrorResponse
+// @Failure 429 {object} presenters.ErrorResponse
+// @Failure 503 {object} presenters.ErrorResponse
+// @Router /ws [get]
+func ChatEvents(ctx *gin.Context, a *app.App) {
+	userID, ok := authUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, presenters.Error(fmt.Errorf("user_id not found in token")))
+		return
+	}
+	if a.Events == nil {
+		ctx.JSON(http.StatusServiceUnavailable, presenters.Error(fmt.Errorf("live updates are not available")))
+		return
+	}
+	cfg := a.Config().WebSocketConfig
+	sub, err := a.Events.Subscribe(userID, cfg.SendBuffer, cfg.MaxConnectionsPerUser)
+	if err != nil {
+		if errors.Is(err, events.ErrTooManySubscriptions) {
+			ctx.JSON(http.StatusTooManyRequests, presenters.Error(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, presenters.Error(err))
+		return
+	}
+	defer sub.Close()
+
+	conn, err := websocket.Accept(ctx.Writer, ctx.Request, &websocket.AcceptOptions{
+		Subprotocols:   []string{middlewares.WebSocketBearerProtocol},
+		OriginPatterns: wsOriginPatterns(a.Config().AllowedCORSOrigins),
+	})
+	if err != nil {
+		// Accept has already written the error response
+		a.Logger.WithError(err).WithField("user_id", userID).Debug("WebSocket handshake failed")
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(wsReadLimit)
+	// CloseRead answers pings and close frames; the context is done once the
+	// client goes away. It is detached from the request context, which is
+	// cancelled on shutdown, so a close frame can still be sent then.
+	rctx := conn.CloseRead(context.WithoutCancel(ctx.Request.Context()))
+	drain := ctx.Request.Context().Done()
+
+	pingInterval := cfg.PingInterval
+	if pingInterval <= 0 {
+		pingInterval = 30 * time.Second
+	}
+	writeTimeout := cfg.WriteTimeout
+	if writeTimeout <= 0 {
+		writeTimeout = 10 * time.Second
+	}
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rctx.Done():
+			return
+		case <-drain:
+			// Clients reconnect to another replica on 1001
+			conn.Close(websocket.StatusGoingAway, "server is shutting down")
+			return
+		case <-sub.Overflow():
+			a.Logger.WithField("user_id", userID).Warn("WebSocket client is too slow, closing connection")
+			conn.Close(websocket.StatusTryAgainLater, "client is too slow")
+			return
+		case msg := <-sub.Messages():
+			wctx, cancel := context.WithTimeout(rctx, writeTimeout)
+			err := conn.Write(wctx, websocket.MessageText, msg)
+			cancel()
+			if err != nil {
+				return
+			}
+		case <-ticker.C:
+			pctx, cancel := context.WithTimeout(rctx, writeTimeout)
+			err := conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// publishChatEvent notifies the user's sockets. Failures are only logged:
+// the change itself has already succeeded.
+func publishChatEvent(ctx *gin.Context, a *app.App, eventType string, userID, chatID int64, data interface{}) {
+	if a.Events == nil {
+		return
+	}
+	ev, err := events.NewEvent(eventType, userID, chatID, data)
+	if err == nil {
+		pctx, cancel := context.WithTimeout(context.WithoutCancel(ctx.Request.Context()), wsPublishTimeout)
+		defer cancel()
+		err = a.Events.Publish(pctx, ev)
+	}
+	if err != nil {
+		a.Logger.WithError(err).WithFields(map[string]interface{}{
+			"event":   eventType,
+			"user_id": userID,
+			"chat_id": chatID,
+		}).Error("Failed to publish chat event")
+	}
+}
+
+// wsOriginPatterns converts CORS origins to host patterns for the handshake origin check.
+func wsOriginPatterns(origins []string) []string {
+	out := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		if origin == "*" {
+			out = append(out, "*")
+			continue
+		}
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			out = append(out, u.Host)
+		}
+	}
+	return out
+}
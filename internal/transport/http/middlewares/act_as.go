@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ActAsHeader = "X-Act-As"
+
+	actAsKey = "act_as"
+)
+
+// ActAs lets admins act on behalf of another user by sending X-Act-As with
+// the user's id. Other callers get 403 when they send the header. Such
+// requests are always written to the audit log, reads included. It must be
+// applied after AuthMiddleware.
+func ActAs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader(ActAsHeader)
+		if raw == "" || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		p, ok := PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+			c.Abort()
+			return
+		}
+		if !p.HasRole(domain.RoleAdmin) || !p.HasScope(domain.ScopeAdminWrite) {
+			c.JSON(http.StatusForbidden, gin.H{"error": ActAsHeader + " is allowed for admins only"})
+			c.Abort()
+			return
+		}
+		userID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || userID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ActAsHeader + " must be a positive integer"})
+			c.Abort()
+			return
+		}
+		if userID != p.UserID {
+			c.Set(actAsKey, userID)
+		}
+		c.Next()
+	}
+}
+
+// SubjectID returns the user the request acts for: the X-Act-As user for
+// admins, otherwise the authenticated principal.
+func SubjectID(c *gin.Context) (int64, bool) {
+	if id := c.GetInt64(actAsKey); id > 0 {
+		return id, true
+	}
+	p, ok := PrincipalFrom(c)
+	if !ok {
+		return 0, false
+	}
+	return p.UserID, true
+}
+
+// ActingAs returns the X-Act-As user, 0 if the request acts for the caller.
+func ActingAs(c *gin.Context) int64 {
+	return c.GetInt64(actAsKey)
+}
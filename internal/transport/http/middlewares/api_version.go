@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIVersion int
+
+const (
+	APIV1 APIVersion = 1
+	APIV2 APIVersion = 2
+)
+
+const apiVersionKey = "api_version"
+
+// Deprecation is described in RFC 9745, Sunset in RFC 8594.
+const (
+	deprecationHeader = "Deprecation"
+	sunsetHeader      = "Sunset"
+)
+
+// SetAPIVersion marks requests of a route group with the API version the
+// handlers render. Deprecated versions announce their deprecation and sunset
+// dates and link to the same path under the successor prefix; zero dates are
+// not sent.
+func SetAPIVersion(version APIVersion, deprecatedAt, sunsetAt time.Time, successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiVersionKey, version)
+		if !deprecatedAt.IsZero() {
+			c.Header(deprecationHeader, "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+		}
+		if !sunsetAt.IsZero() {
+			c.Header(sunsetHeader, sunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if successor != "" {
+			path := successor + strings.TrimPrefix(UnversionedRoute(c.Request.URL.Path), "/api")
+			c.Header("Link", "<"+path+">; rel=\"successor-version\"")
+		}
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the version of the route group, v1 if none is set.
+func GetAPIVersion(c *gin.Context) APIVersion {
+	if v, ok := c.Get(apiVersionKey); ok {
+		return v.(APIVersion)
+	}
+	return APIV1
+}
+
+// UnversionedRoute maps /api/v1/... and /api/v2/... routes to /api/..., so
+// audit actions and route checks do not depend on the version prefix.
+func UnversionedRoute(route string) string {
+	for _, prefix := range []string{"/api/v1/", "/api/v2/"} {
+		if strings.HasPrefix(route, prefix) {
+			return "/api/" + strings.TrimPrefix(route, prefix)
+		}
+	}
+	return route
+}
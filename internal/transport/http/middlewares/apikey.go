@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/repository"
+	"VKR_gateway_service/pkg/securetoken"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	// last_used_at is written at most this often per key
+	apiKeyTouchInterval = time.Minute
+)
+
+// errInvalidAPIKey is returned for unknown, expired and revoked keys alike.
+var errInvalidAPIKey = errors.New("invalid API key")
+
+// AuthenticateAPIKey resolves an X-API-Key value to its owner. Roles are
+// those the owner holds now, so demoting the owner also narrows the key.
+// On error status is the HTTP status to respond with.
+func AuthenticateAPIKey(ctx context.Context, a *app.App, raw string) (*auth.Principal, int, error) {
+	raw = strings.TrimSpace(raw)
+	if a == nil || a.APIKeys == nil {
+		return nil, http.StatusUnauthorized, errors.New("API keys are not available")
+	}
+	if !strings.HasPrefix(raw, domain.APIKeyPrefix) {
+		return nil, http.StatusUnauthorized, errInvalidAPIKey
+	}
+	key, err := a.APIKeys.GetAPIKeyByHash(ctx, securetoken.Hash(raw))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, http.StatusUnauthorized, errInvalidAPIKey
+	}
+	if err != nil {
+		a.Logger.WithError(err).Error("Failed to look up API key")
+		return nil, http.StatusInternalServerError, errors.New("internal error")
+	}
+	if !key.Active(time.Now()) {
+		return nil, http.StatusUnauthorized, errInvalidAPIKey
+	}
+	if err := a.APIKeys.TouchAPIKey(ctx, key.ID, apiKeyTouchInterval); err != nil {
+		a.Logger.WithError(err).WithField("api_key_id", key.ID).Warn("Failed to record API key use")
+	}
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []domain.Scope{}
+	}
+	p := &auth.Principal{
+		UserID:   key.UserID,
+		Roles:    resolveRoles(ctx, a, key.UserID, nil),
+		Scopes:   scopes,
+		Method:   auth.MethodAPIKey,
+		APIKeyID: key.ID,
+	}
+	if key.ExpiresAt != nil {
+		p.ExpiresAt = *key.ExpiresAt
+	}
+	return p, http.StatusOK, nil
+}
+
+// HasScope reports whether the request may use scope. Bearer tokens act
+// with the full rights of the user, API keys only with their scopes.
+func HasScope(c *gin.Context, scope domain.Scope) bool {
+	p, _ := PrincipalFrom(c)
+	return p.HasScope(scope)
+}
+
+// RequireScope allows API keys only if they hold every scope. It must be
+// applied after AuthMiddleware.
+func RequireScope(scopes ...domain.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, s := range scopes {
+			if !checkScope(c, s) {
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireResourceScope allows API keys with <resource>:read for safe
+// methods and <resource>:write for the others.
+func RequireResourceScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if checkScope(c, domain.ResourceScope(resource, isMutating(c.Request.Method))) {
+			c.Next()
+		}
+	}
+}
+
+// DenyAPIKeys limits a route to users signed in with a bearer token, e.g.
+// so a leaked key cannot mint further keys.
+func DenyAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p, ok := PrincipalFrom(c); ok && p.Method == auth.MethodAPIKey {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not allowed with an API key"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func checkScope(c *gin.Context, scope domain.Scope) bool {
+	if c.Request.Method == http.MethodOptions || HasScope(c, scope) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "API key lacks scope " + string(scope)})
+	c.Abort()
+	return false
+}
@@ -0,0 +1,216 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditActionKey     = "audit_action"
+	auditTargetTypeKey = "audit_target_type"
+	auditTargetsKey    = "audit_targets"
+	auditSkipKey       = "audit_skip"
+
+	maxAuditBodySize  = 64 << 10
+	auditWriteTimeout = 5 * time.Second
+	redactedValue     = "[REDACTED]"
+)
+
+// sensitiveKeys are matched as substrings of lower-cased payload keys.
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "api_key", "apikey", "cookie", "credential"}
+
+// Audit records every mutating request, every request to the admin API and
+// every request sent with X-Act-As in the audit log after the handler has
+// finished. It must run after BodyLimit and AuthMiddleware: each entry
+// takes the audit chain lock, so requests without a principal are only
+// recorded when a handler named the action and they succeeded.
+func Audit(a *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := UnversionedRoute(c.FullPath())
+		if a == nil || a.Audit == nil || route == "" || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		if !isMutating(c.Request.Method) && !strings.HasPrefix(route, "/api/admin/") && c.GetHeader(ActAsHeader) == "" {
+			c.Next()
+			return
+		}
+		payload := auditPayload(c)
+
+		c.Next()
+		if c.GetBool(auditSkipKey) {
+			return
+		}
+		p, authenticated := PrincipalFrom(c)
+		if !authenticated && (c.GetString(auditActionKey) == "" || c.Writer.Status() >= http.StatusBadRequest) {
+			return
+		}
+
+		entry := &domain.AuditEntry{
+			Action:     c.GetString(auditActionKey),
+			TargetType: c.GetString(auditTargetTypeKey),
+			RequestID:  GetRequestID(c),
+			ClientIP:   c.ClientIP(),
+			StatusCode: c.Writer.Status(),
+			Payload:    payload,
+		}
+		if entry.Action == "" {
+			entry.Action = c.Request.Method + " " + route
+		}
+		if authenticated {
+			entry.ActorID = p.UserID
+		}
+		for _, p := range c.Params {
+			entry.TargetIDs = append(entry.TargetIDs, p.Key+"="+strings.TrimPrefix(p.Value, "/"))
+		}
+		if id := ActingAs(c); id > 0 {
+			entry.TargetIDs = append(entry.TargetIDs, "act_as="+strconv.FormatInt(id, 10))
+		}
+		if extra, ok := c.Get(auditTargetsKey); ok {
+			entry.TargetIDs = append(entry.TargetIDs, extra.([]string)...)
+		}
+		switch status := c.Writer.Status(); {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			entry.Outcome = domain.AuditOutcomeDenied
+		case status >= http.StatusBadRequest:
+			entry.Outcome = domain.AuditOutcomeFailure
+		default:
+			entry.Outcome = domain.AuditOutcomeSuccess
+		}
+
+		// The client may already be gone, the entry must be written anyway.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditWriteTimeout)
+		defer cancel()
+		if err := a.Audit.CreateAuditEntry(ctx, entry); err != nil {
+			a.Logger.WithError(err).WithFields(map[string]interface{}{
+				"action":     entry.Action,
+				"actor_id":   entry.ActorID,
+				"request_id": entry.RequestID,
+			}).Error("Failed to write audit entry")
+		}
+	}
+}
+
+// AuditAction names the audited action instead of the default "METHOD /route".
+func AuditAction(c *gin.Context, action, targetType string) {
+	c.Set(auditActionKey, action)
+	c.Set(auditTargetTypeKey, targetType)
+}
+
+// AuditTargets adds ids of entities affected by the request which are not
+// in the route, e.g. ids of created entities.
+func AuditTargets(c *gin.Context, ids ...string) {
+	var targets []string
+	if v, ok := c.Get(auditTargetsKey); ok {
+		targets = v.([]string)
+	}
+	c.Set(auditTargetsKey, append(targets, ids...))
+}
+
+// SkipAudit excludes a request from the audit log. Only for POST endpoints
+// that do not change anything, such as queries.
+func SkipAudit(c *gin.Context) {
+	c.Set(auditSkipKey, true)
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// auditPayload returns the redacted query and JSON body of the request. Large
+// and non-JSON bodies are described by content type and size only.
+func auditPayload(c *gin.Context) string {
+	payload := map[string]interface{}{}
+	if q := c.Request.URL.Query(); len(q) > 0 {
+		query := make(map[string]interface{}, len(q))
+		for k, v := range q {
+			if isSensitiveKey(k) {
+				query[k] = redactedValue
+			} else if len(v) == 1 {
+				query[k] = v[0]
+			} else {
+				query[k] = v
+			}
+		}
+		payload["query"] = query
+	}
+
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		contentType := c.ContentType()
+		buf, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBodySize+1))
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(buf), c.Request.Body))
+		switch {
+		case err != nil:
+			payload["body_error"] = err.Error()
+		case len(buf) == 0:
+		case contentType == "application/json" && len(buf) <= maxAuditBodySize:
+			var body interface{}
+			if err := json.Unmarshal(buf, &body); err != nil {
+				payload["body"] = map[string]interface{}{"content_type": contentType, "size": len(buf), "invalid_json": true}
+			} else {
+				payload["body"] = redact(body)
+			}
+		default:
+			size := c.Request.ContentLength
+			if size < 0 {
+				size = int64(len(buf))
+			}
+			payload["body"] = map[string]interface{}{"content_type": contentType, "size": size}
+		}
+	}
+
+	if len(payload) == 0 {
+		return ""
+	}
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if isSensitiveKey(k) {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = redact(val)
+		}
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
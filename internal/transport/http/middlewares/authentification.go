@@ -2,20 +2,26 @@ package middlewares
 
 import (
 	"VKR_gateway_service/internal/app"
-	"encoding/base64"
-	"encoding/json"
+	"VKR_gateway_service/internal/auth"
+	"context"
+	"errors"
 	"io"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT via external SSO HTTP endpoint.
-// It sends GET SSO_HTTP_URL + "/api/auth/validate" with the same Authorization header.
-// On non-200 it aborts request and returns JSON: {"error": "string"}.
+const maxSSOResponseSize = 64 << 10
+
+// AuthMiddleware authenticates the request with a JWT, an API key or the
+// session cookie set by the SSO login. A JWT is validated via the external
+// SSO HTTP endpoint: it sends GET SSO_HTTP_URL + "/api/auth/validate" with
+// the same Authorization header; the session cookie carries such a JWT.
+// An X-API-Key is looked up in the gateway's key table. All store the same
+// *auth.Principal in the request context, read it with PrincipalFrom.
+// On failure it aborts request and returns JSON: {"error": "string"}.
 func AuthMiddleware(a *app.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Avoid recursive validation if someone points SSO to this same service
@@ -27,148 +33,104 @@ func AuthMiddleware(a *app.App) gin.HandlerFunc {
 			c.Next()
 			return
 		}
+		var (
+			principal *auth.Principal
+			status    int
+			err       error
+		)
 		tokenString := c.GetHeader("Authorization")
-		if tokenString == "" || !strings.HasPrefix(tokenString, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
-			c.Abort()
-			return
-		}
-		if a == nil || a.Config == nil || a.Config.SSO_HTTP_URL == "" {
-			c.JSON(http.StatusBadGateway, gin.H{"error": "SSO url not configured"})
-			c.Abort()
-			return
-		}
-
-		target := strings.TrimRight(a.Config.SSO_HTTP_URL, "/") + "/api/auth/validate"
-		a.Logger.Debug("Send request to ", target)
-		req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, target, nil)
-		if err != nil {
-			a.Logger.Debug("Error", err)
-			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
-			c.Abort()
-			return
-		}
-		req.Header.Set("Authorization", tokenString)
-		req.Header.Set("Accept", "application/json")
-		timeout := 5 * time.Second
-		if a.Config.GRPCTimeout > 0 {
-			timeout = a.Config.GRPCTimeout
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+			if tokenString != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "send either Authorization or " + APIKeyHeader + ", not both"})
+				c.Abort()
+				return
+			}
+			principal, status, err = AuthenticateAPIKey(c.Request.Context(), a, apiKey)
+		} else if session := SessionToken(c); tokenString == "" && session != "" {
+			principal, status, err = Authenticate(c.Request.Context(), a, "Bearer "+session)
+			if err == nil {
+				principal.Method = auth.MethodSession
+			}
+		} else {
+			if tokenString == "" || !strings.HasPrefix(tokenString, "Bearer ") {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+				c.Abort()
+				return
+			}
+			principal, status, err = Authenticate(c.Request.Context(), a, tokenString)
 		}
-		httpClient := &http.Client{Timeout: timeout}
-		resp, err := httpClient.Do(req)
 		if err != nil {
-			a.Logger.Debug("Error", err)
-			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			c.JSON(status, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-			msg := strings.TrimSpace(string(body))
-			if msg == "" {
-				msg = "invalid token"
-			}
-			c.JSON(resp.StatusCode, gin.H{"error": msg})
-			c.Abort()
-			return
-		}
-		userID, ok := extractUserIDFromHeader(resp.Header)
-		if !ok {
-			userID, ok = extractUserIDFromToken(tokenString)
-		}
-		if ok && userID > 0 {
-			c.Set("user_id", userID)
-		}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
 		c.Next()
 	}
 }
 
-func extractUserID(body []byte) (int64, bool) {
-	if len(body) == 0 {
-		return 0, false
-	}
-	var payload interface{}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		return 0, false
-	}
-	return findUserIDRecursive(payload)
+// PrincipalFrom returns the caller authenticated by AuthMiddleware.
+func PrincipalFrom(c *gin.Context) (*auth.Principal, bool) {
+	return auth.FromContext(c.Request.Context())
 }
 
-func findUserIDRecursive(payload interface{}) (int64, bool) {
-	switch v := payload.(type) {
-	case map[string]interface{}:
-		keys := []string{"user_id", "userId", "userID", "User_id", "UserId", "id", "uid", "sub"}
-		for _, key := range keys {
-			if raw, ok := v[key]; ok {
-				if id, ok := normalizeID(raw); ok {
-					return id, true
-				}
-			}
-		}
-		for _, raw := range v {
-			if id, ok := findUserIDRecursive(raw); ok {
-				return id, true
-			}
-		}
-	case []interface{}:
-		for _, raw := range v {
-			if id, ok := findUserIDRecursive(raw); ok {
-				return id, true
-			}
-		}
+// Authenticate validates the "Bearer <token>" value via SSO and builds the
+// principal from the claims of the validate response, mapped as configured
+// in SSOClaimsConfig. On error status is the HTTP status to respond with.
+func Authenticate(ctx context.Context, a *app.App, tokenString string) (*auth.Principal, int, error) {
+	if a == nil || a.Config() == nil || a.Config().SSO_HTTP_URL == "" {
+		return nil, http.StatusBadGateway, errors.New("SSO url not configured")
 	}
-	return 0, false
-}
+	cfg := a.Config()
 
-func extractUserIDFromHeader(header http.Header) (int64, bool) {
-	keys := []string{"X-User-Id", "X-UserId", "X-UserID"}
-	for _, key := range keys {
-		value := strings.TrimSpace(header.Get(key))
-		if value == "" {
-			continue
-		}
-		id, err := strconv.ParseInt(value, 10, 64)
-		if err == nil && id > 0 {
-			return id, true
-		}
+	target := strings.TrimRight(cfg.SSO_HTTP_URL, "/") + "/api/auth/validate"
+	a.Logger.Debug("Send request to ", target)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		a.Logger.Debug("Error", err)
+		return nil, http.StatusBadGateway, err
 	}
-	return 0, false
-}
-
-func extractUserIDFromToken(tokenString string) (int64, bool) {
-	tokenString = strings.TrimSpace(tokenString)
-	if strings.HasPrefix(tokenString, "Bearer ") {
-		tokenString = strings.TrimSpace(strings.TrimPrefix(tokenString, "Bearer "))
+	req.Header.Set("Authorization", tokenString)
+	req.Header.Set("Accept", "application/json")
+	timeout := 5 * time.Second
+	if cfg.GRPCTimeout > 0 {
+		timeout = cfg.GRPCTimeout
 	}
-	parts := strings.Split(tokenString, ".")
-	if len(parts) < 2 {
-		return 0, false
+	httpClient := &http.Client{Timeout: timeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		a.Logger.Debug("Error", err)
+		return nil, http.StatusBadGateway, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = "invalid token"
+		}
+		return nil, resp.StatusCode, errors.New(msg)
 	}
-	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSSOResponseSize+1))
 	if err != nil {
-		return 0, false
+		return nil, http.StatusBadGateway, err
 	}
-	return extractUserID(payload)
-}
-
-func normalizeID(v interface{}) (int64, bool) {
-	switch t := v.(type) {
-	case float64:
-		return int64(t), true
-	case string:
-		id, err := strconv.ParseInt(t, 10, 64)
-		if err != nil {
-			return 0, false
-		}
-		return id, true
-	case json.Number:
-		id, err := t.Int64()
-		if err != nil {
-			return 0, false
-		}
-		return id, true
-	default:
-		return 0, false
+	if len(body) > maxSSOResponseSize {
+		return nil, http.StatusBadGateway, errors.New("SSO response is too large")
+	}
+	claims, err := auth.ParseClaims(body, cfg.SSOClaimsConfig)
+	if err != nil {
+		// The token was accepted, so this is a mapping or SSO problem
+		a.Logger.WithError(err).Error("Failed to read identity from SSO response")
+		return nil, http.StatusBadGateway, errors.New("SSO response does not identify the user")
+	}
+	if !claims.ExpiresAt.IsZero() && !time.Now().Before(claims.ExpiresAt) {
+		return nil, http.StatusUnauthorized, errors.New("token expired")
 	}
+	return &auth.Principal{
+		UserID:    claims.UserID,
+		Roles:     resolveRoles(ctx, a, claims.UserID, claims.Roles),
+		ExpiresAt: claims.ExpiresAt,
+		Method:    auth.MethodBearer,
+	}, http.StatusOK, nil
 }
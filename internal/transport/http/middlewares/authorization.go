@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// last_seen_at of a user is written at most this often
+const userTouchInterval = time.Minute
+
+// RequireRole allows the request only if the authenticated user has a role
+// including min. It must be applied after AuthMiddleware.
+func RequireRole(min domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		if _, ok := PrincipalFrom(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+			c.Abort()
+			return
+		}
+		if !HasRole(c, min) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient role, " + string(min) + " required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// HasRole reports whether any role of the authenticated user includes role.
+func HasRole(c *gin.Context, role domain.Role) bool {
+	p, _ := PrincipalFrom(c)
+	return p.HasRole(role)
+}
+
+func Roles(c *gin.Context) []domain.Role {
+	if p, ok := PrincipalFrom(c); ok {
+		return p.Roles
+	}
+	return nil
+}
+
+// resolveRoles merges roles from SSO claims, bootstrap admins from config and
+// the gateway role table. Every authenticated user has at least RoleUser.
+func resolveRoles(ctx context.Context, a *app.App, userID int64, ssoRoles []domain.Role) []domain.Role {
+	roles := []domain.Role{domain.RoleUser}
+	add := func(r domain.Role) {
+		for _, have := range roles {
+			if have == r {
+				return
+			}
+		}
+		roles = append(roles, r)
+	}
+	for _, r := range ssoRoles {
+		add(r)
+	}
+	for _, id := range a.Config().AdminUserIDs {
+		if id == userID {
+			add(domain.RoleAdmin)
+		}
+	}
+	if a.Users != nil {
+		role, err := a.Users.TouchUser(ctx, userID, userTouchInterval)
+		if err != nil {
+			a.Logger.WithError(err).WithField("user_id", userID).Error("Failed to load user role")
+		} else if r, ok := domain.ParseRole(string(role)); ok {
+			add(r)
+		}
+	}
+	return roles
+}
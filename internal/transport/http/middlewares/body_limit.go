@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit caps request bodies at limit(c) bytes, so routes such as
+// uploads can allow more than the rest. Bodies declaring a larger
+// Content-Length are refused with 413; reading past the limit of a chunked
+// body fails with *http.MaxBytesError.
+func BodyLimit(limit func(c *gin.Context) int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		n := limit(c)
+		if c.Request.ContentLength > n {
+			c.Header("Connection", "close")
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, presenters.Error(fmt.Errorf("request body must not exceed %d bytes", n)))
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, n)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireClientCert allows only requests whose TLS client certificate was
+// verified against TLS_CLIENT_CA_FILE. Behind a TLS-terminating proxy no
+// request passes.
+func RequireClientCert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "a client certificate is required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/config"
+	"strings"
+	"sync"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORSPolicy builds the CORS settings of a group of routes from the config.
+type CORSPolicy func(cfg *config.Config) cors.Config
+
+// CORS applies the policy whose prefix the unversioned request path starts
+// with, or fallback; prefixes must not overlap. It must be used on the engine rather than on
+// route groups: preflight requests match no route and would not reach
+// group middleware. Policies are rebuilt when the config is reloaded.
+func CORS(a *app.App, fallback CORSPolicy, prefixes map[string]CORSPolicy) gin.HandlerFunc {
+	apply := cachedCORS(a, fallback)
+	applyPrefix := make(map[string]gin.HandlerFunc, len(prefixes))
+	for prefix, policy := range prefixes {
+		applyPrefix[prefix] = cachedCORS(a, policy)
+	}
+	return func(c *gin.Context) {
+		path := UnversionedRoute(c.Request.URL.Path)
+		for prefix, h := range applyPrefix {
+			if strings.HasPrefix(path, prefix) {
+				h(c)
+				return
+			}
+		}
+		apply(c)
+	}
+}
+
+func cachedCORS(a *app.App, policy CORSPolicy) gin.HandlerFunc {
+	var (
+		mu      sync.Mutex
+		builtAt *config.Config
+		handler gin.HandlerFunc
+	)
+	return func(c *gin.Context) {
+		cfg := a.Config()
+		mu.Lock()
+		if builtAt != cfg {
+			builtAt, handler = cfg, cors.New(policy(cfg))
+		}
+		h := handler
+		mu.Unlock()
+		h(c)
+	}
+}
+
+// OriginAllowed reports whether origin matches one of patterns, ignoring
+// case and a trailing slash. "*" matches any origin and a pattern like
+// https://*.example.com any subdomain of example.com with that scheme and
+// port, but not example.com itself.
+func OriginAllowed(patterns []string, origin string) bool {
+	for _, p := range patterns {
+		p = strings.TrimRight(p, "/")
+		if p == "*" || strings.EqualFold(p, origin) || matchSubdomain(p, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSubdomain(pattern, origin string) bool {
+	scheme, domain, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	prefix, suffix := scheme+"://", "."+domain
+	if len(origin) <= len(prefix)+len(suffix) || !strings.EqualFold(origin[:len(prefix)], prefix) {
+		return false
+	}
+	host := origin[len(prefix):]
+	return strings.EqualFold(host[len(host)-len(suffix):], suffix) && !strings.ContainsAny(host, "/@")
+}
@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const csrfProblemType = "/problems/csrf"
+
+// CSRF protects state-changing requests authenticated by the session
+// cookies. They must come from PUBLIC_URL or an allowed CORS origin, judged
+// by Origin or else Referer, and echo the CSRF cookie in CSRFHeader
+// (double-submit). Requests with Authorization or X-API-Key are not
+// checked, a cross-site page cannot make the browser send those.
+func CSRF(a *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutating(c.Request.Method) || !usesSession(c) {
+			c.Next()
+			return
+		}
+		cfg := a.Config()
+		origin, ok := requestOrigin(c)
+		if !ok {
+			csrfProblem(c, "Origin or Referer header is required")
+			return
+		}
+		if !OriginAllowed(cfg.AllowedCORSOrigins, origin) && !sameOrigin(cfg.PublicURL, origin) {
+			csrfProblem(c, "origin "+origin+" is not allowed")
+			return
+		}
+		if !validCSRFToken(c) {
+			csrfProblem(c, "missing or invalid "+CSRFHeader+", send the value of the "+CSRFCookie+" cookie")
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestOrigin returns the Origin header or, when a browser omitted it,
+// the origin of Referer. The opaque origin "null" is not accepted.
+func requestOrigin(c *gin.Context) (string, bool) {
+	if origin := c.GetHeader("Origin"); origin != "" {
+		return origin, origin != "null"
+	}
+	referer, err := url.Parse(c.GetHeader("Referer"))
+	if err != nil || referer.Scheme == "" || referer.Host == "" {
+		return "", false
+	}
+	return referer.Scheme + "://" + referer.Host, true
+}
+
+func sameOrigin(publicURL, origin string) bool {
+	u, err := url.Parse(publicURL)
+	if publicURL == "" || err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme+"://"+u.Host, origin)
+}
+
+func validCSRFToken(c *gin.Context) bool {
+	cookie, err := c.Cookie(CSRFCookie)
+	header := c.GetHeader(CSRFHeader)
+	if err != nil || cookie == "" || header == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+func csrfProblem(c *gin.Context, detail string) {
+	c.Header("Content-Type", "application/problem+json")
+	c.JSON(http.StatusForbidden, &presenters.ProblemResponse{
+		Type:   csrfProblemType,
+		Title:  "CSRF check failed",
+		Status: http.StatusForbidden,
+		Detail: detail,
+		Error:  detail,
+	})
+	c.Abort()
+}
@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+const pageKey = "page"
+
+type page struct {
+	limit, offset int
+}
+
+// Paging validates ?limit= and ?offset= of GET requests before the handler
+// runs, so a bad value is refused before any work is done. Other methods are
+// not paged.
+func Paging() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		limit, offset, err := ParsePage(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		c.Set(pageKey, page{limit: limit, offset: offset})
+		c.Next()
+	}
+}
+
+// Page returns the page validated by Paging. ok is false for requests that
+// are not paged.
+func Page(c *gin.Context) (limit, offset int, ok bool) {
+	v, ok := c.Get(pageKey)
+	if !ok {
+		return 0, 0, false
+	}
+	p := v.(page)
+	return p.limit, p.offset, true
+}
+
+// ParsePage reads ?limit= (default DefaultPageLimit) and ?offset=.
+func ParsePage(c *gin.Context) (limit, offset int, err error) {
+	limit = DefaultPageLimit
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > MaxPageLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+		}
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-Id"
+	requestIDKey    = "request_id"
+)
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID keeps a sane X-Request-Id from the client or generates a new one
+// and echoes it in the response, so that logs and audit entries can be correlated.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := NormalizeRequestID(c.GetHeader(RequestIDHeader))
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// NormalizeRequestID returns id if it is safe to log and store, otherwise a new id.
+func NormalizeRequestID(id string) string {
+	if validRequestID.MatchString(id) {
+		return id
+	}
+	return newRequestID()
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/app"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerCSP allows the inline scripts and styles of the Swagger UI.
+const swaggerCSP = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+// SecurityHeaders sets X-Content-Type-Options, X-Frame-Options,
+// Referrer-Policy and Content-Security-Policy on every response, and
+// Strict-Transport-Security on responses served over HTTPS.
+func SecurityHeaders(a *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := a.Config()
+		srv := cfg.HttpServerConfig
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		if srv.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", srv.ReferrerPolicy)
+		}
+		if strings.HasPrefix(c.Request.URL.Path, "/swagger/") {
+			h.Set("Content-Security-Policy", swaggerCSP)
+		} else if srv.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", srv.ContentSecurityPolicy)
+		}
+		if srv.HSTSMaxAge > 0 && isHTTPS(c, cfg.PublicURL) {
+			h.Set("Strict-Transport-Security", "max-age="+strconv.FormatInt(int64(srv.HSTSMaxAge.Seconds()), 10))
+		}
+		c.Next()
+	}
+}
+
+// isHTTPS reports whether the client reached the gateway over TLS, directly
+// or through a proxy terminating it for PUBLIC_URL.
+func isHTTPS(c *gin.Context, publicURL string) bool {
+	if c.Request.TLS != nil {
+		return true
+	}
+	u, err := url.Parse(publicURL)
+	return err == nil && u.Scheme == "https"
+}
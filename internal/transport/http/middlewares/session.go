@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Cookies set by the SSO login. The tokens are HttpOnly, so scripts never
+// see them; the CSRF cookie is readable and must be echoed in CSRFHeader.
+const (
+	AccessTokenCookie  = "alib_access"
+	RefreshTokenCookie = "alib_refresh"
+	CSRFCookie         = "alib_csrf"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+// SessionToken returns the access token from the session cookie, "" if
+// there is none.
+func SessionToken(c *gin.Context) string {
+	token, err := c.Cookie(AccessTokenCookie)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// usesSession reports whether the request is authenticated by the session
+// cookies rather than by a header a cross-site page cannot set.
+func usesSession(c *gin.Context) bool {
+	if c.GetHeader("Authorization") != "" || c.GetHeader(APIKeyHeader) != "" {
+		return false
+	}
+	if SessionToken(c) != "" {
+		return true
+	}
+	refresh, err := c.Cookie(RefreshTokenCookie)
+	return err == nil && refresh != ""
+}
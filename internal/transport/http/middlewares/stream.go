@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"VKR_gateway_service/pkg/lifecycle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrackStream registers long-lived responses (WebSockets, SSE) with the
+// lifecycle manager: shutdown waits for them, their request context is
+// cancelled when draining starts and new ones are refused while draining.
+func TrackStream(lc *lifecycle.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if lc == nil {
+			c.Next()
+			return
+		}
+		ctx, done, ok := lc.Stream(c.Request.Context())
+		if !ok {
+			c.Header("Connection", "close")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, presenters.Error(errors.New("server is shutting down")))
+			return
+		}
+		defer done()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// NoTimeouts lifts the server read and write timeouts for responses that
+// legitimately run longer, such as WebSockets and exports.
+func NoTimeouts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rc := http.NewResponseController(c.Writer)
+		// Errors mean the writer does not support deadlines, e.g. in tests
+		_ = rc.SetReadDeadline(time.Time{})
+		_ = rc.SetWriteDeadline(time.Time{})
+		c.Next()
+	}
+}
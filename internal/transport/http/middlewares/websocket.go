@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketBearerProtocol is the subprotocol browsers use to pass the access
+// token, since they cannot set headers on a WebSocket handshake:
+//
+//	new WebSocket(url, ["bearer", token])
+const WebSocketBearerProtocol = "bearer"
+
+// WebSocketAuth copies the token from Sec-WebSocket-Protocol to the
+// Authorization header so that AuthMiddleware validates it as usual. The
+// token is not accepted from the query string to keep it out of access logs.
+func WebSocketAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+		var protocols []string
+		for _, h := range c.Request.Header.Values("Sec-WebSocket-Protocol") {
+			for _, p := range strings.Split(h, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					protocols = append(protocols, p)
+				}
+			}
+		}
+		for i := 0; i+1 < len(protocols); i++ {
+			if strings.EqualFold(protocols[i], WebSocketBearerProtocol) {
+				c.Request.Header.Set("Authorization", "Bearer "+protocols[i+1])
+				break
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package presenters
+
+type AdminUser struct {
+	UserId     int64  `json:"user_id"`
+	Role       string `json:"role"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+}
+
+type AdminUsersResponse struct {
+	Users  []AdminUser `json:"users"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+type SetUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type AdminConfigResponse struct {
+	// Config file the settings were read from, empty if only env is used
+	Source   string `json:"source"`
+	LoadedAt string `json:"loaded_at"`
+	// Settings applied on reload without a restart
+	Reloadable []string `json:"reloadable"`
+	// Effective settings keyed like the config file, secrets redacted
+	Config map[string]interface{} `json:"config" swaggertype:"object"`
+}
+
+type DBPoolStats struct {
+	MaxConns          int32 `json:"max_conns"`
+	TotalConns        int32 `json:"total_conns"`
+	AcquiredConns     int32 `json:"acquired_conns"`
+	IdleConns         int32 `json:"idle_conns"`
+	ConstructingConns int32 `json:"constructing_conns"`
+	// Cumulative since startup
+	AcquireCount            int64 `json:"acquire_count"`
+	AcquireDurationMs       int64 `json:"acquire_duration_ms"`
+	EmptyAcquireCount       int64 `json:"empty_acquire_count"`
+	EmptyAcquireWaitTimeMs  int64 `json:"empty_acquire_wait_time_ms"`
+	CanceledAcquireCount    int64 `json:"canceled_acquire_count"`
+	NewConnsCount           int64 `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64 `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64 `json:"max_idle_destroy_count"`
+}
+
+type DBStatsResponse struct {
+	Primary DBPoolStats `json:"primary"`
+	// Omitted without a read replica
+	Replica *DBPoolStats `json:"replica,omitempty"`
+}
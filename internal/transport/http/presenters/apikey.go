@@ -0,0 +1,39 @@
+package presenters
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+	// e.g. papers:write, chats:read
+	Scopes []string `json:"scopes" binding:"required"`
+	// 0 means API_KEY_DEFAULT_TTL
+	ExpiresInHours int `json:"expires_in_hours"`
+	// Owner of the key, admins only; defaults to the caller
+	UserId int64 `json:"user_id"`
+}
+
+type RotateAPIKeyRequest struct {
+	// 0 means API_KEY_DEFAULT_TTL
+	ExpiresInHours int `json:"expires_in_hours"`
+	// How long the old key keeps working, 0 revokes it at once
+	GraceHours int `json:"grace_hours"`
+}
+
+type APIKeyResponse struct {
+	KeyId  int64  `json:"key_id"`
+	UserId int64  `json:"user_id"`
+	Name   string `json:"name"`
+	// First characters of the key, to tell keys apart
+	Prefix string `json:"prefix"`
+	// The key itself, returned only on create and rotate
+	Key         string   `json:"key,omitempty"`
+	Scopes      []string `json:"scopes"`
+	CreatedAt   string   `json:"created_at"`
+	ExpiresAt   *string  `json:"expires_at,omitempty"`
+	LastUsedAt  *string  `json:"last_used_at,omitempty"`
+	RevokedAt   *string  `json:"revoked_at,omitempty"`
+	RotatedFrom *int64   `json:"rotated_from,omitempty"`
+	Active      bool     `json:"active"`
+}
+
+type APIKeysResponse struct {
+	Keys []APIKeyResponse `json:"keys"`
+}
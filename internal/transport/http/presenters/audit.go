@@ -0,0 +1,30 @@
+package presenters
+
+type AuditEntry struct {
+	Id         int64    `json:"id"`
+	CreatedAt  string   `json:"created_at"`
+	ActorId    int64    `json:"actor_id"`
+	Action     string   `json:"action"`
+	TargetType string   `json:"target_type"`
+	TargetIds  []string `json:"target_ids"`
+	RequestId  string   `json:"request_id"`
+	ClientIp   string   `json:"client_ip"`
+	Outcome    string   `json:"outcome"`
+	StatusCode int      `json:"status_code"`
+	Payload    string   `json:"payload"`
+	PrevHash   string   `json:"prev_hash"`
+	Hash       string   `json:"hash"`
+}
+
+type AuditLogResponse struct {
+	Entries []AuditEntry `json:"entries"`
+	Limit   int          `json:"limit"`
+	Offset  int          `json:"offset"`
+}
+
+type AuditVerifyResponse struct {
+	Valid          bool   `json:"valid"`
+	Checked        int    `json:"checked"`
+	FirstInvalidId int64  `json:"first_invalid_id,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+}
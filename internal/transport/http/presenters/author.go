@@ -0,0 +1,15 @@
+package presenters
+
+type AddAuthorRequest struct {
+	First_name  string `json:"first_name" binding:"required"`
+	Last_name   string `json:"last_name" binding:"required"`
+	Middle_name string `json:"middle_name"`
+	Orcid       string `json:"orcid"`
+}
+
+type AddInstitutionRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Country string `json:"country"`
+	Ror_id  string `json:"ror_id"`
+	Grid_id string `json:"grid_id"`
+}
@@ -1,8 +1,7 @@
 package presenters
 
 type CreateChatRequest struct {
-	UserId int64  `json:"user_id"`
-	Title  string `json:"title"`
+	Title string `json:"title"`
 }
 
 type Chat struct {
@@ -36,3 +35,59 @@ type ChatHistoryMessage struct {
 type ChatHistoryResponse struct {
 	ChatMessages []ChatHistoryMessage `json:"chat_messages"`
 }
+
+type CreateChatShareRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"`
+}
+
+type ChatShareResponse struct {
+	ShareId   int64   `json:"share_id"`
+	ChatId    int64   `json:"chat_id"`
+	Token     string  `json:"token,omitempty"`
+	Url       string  `json:"url,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	ExpiresAt string  `json:"expires_at"`
+	RevokedAt *string `json:"revoked_at,omitempty"`
+	Active    bool    `json:"active"`
+}
+
+type ChatSharesResponse struct {
+	Shares []ChatShareResponse `json:"shares"`
+}
+
+type SharedChatResponse struct {
+	Title        string               `json:"title"`
+	ExpiresAt    string               `json:"expires_at"`
+	ChatMessages []ChatHistoryMessage `json:"chat_messages"`
+}
+
+type ChatRerunResponse struct {
+	SearchQuery string `json:"search_query"`
+	// created_at of the message that was run again
+	OriginalCreatedAt string        `json:"original_created_at"`
+	CreatedAt         string        `json:"created_at"`
+	Papers            []Paper       `json:"papers"`
+	Diff              ChatRerunDiff `json:"diff"`
+}
+
+// ChatRerunDiff compares the new results with the original message. Ranks
+// start at 1.
+type ChatRerunDiff struct {
+	Added       []RankedPaper     `json:"added"`
+	Removed     []RankedPaper     `json:"removed"`
+	RankChanged []PaperRankChange `json:"rank_changed"`
+	Unchanged   int               `json:"unchanged"`
+}
+
+// RankedPaper has the new rank for added papers and the original rank for
+// removed ones.
+type RankedPaper struct {
+	Paper
+	Rank int `json:"rank"`
+}
+
+type PaperRankChange struct {
+	Paper
+	PreviousRank int `json:"previous_rank"`
+	Rank         int `json:"rank"`
+}
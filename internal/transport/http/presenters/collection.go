@@ -0,0 +1,39 @@
+package presenters
+
+type CollectionRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CollectionResponse struct {
+	CollectionId int64  `json:"collection_id"`
+	Name         string `json:"name"`
+	PaperCount   int    `json:"paper_count"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type CollectionsResponse struct {
+	Collections []CollectionResponse `json:"collections"`
+}
+
+type SaveCollectionPaperRequest struct {
+	Id   string   `json:"id" binding:"required"`
+	Note string   `json:"note"`
+	Tags []string `json:"tags"`
+}
+
+type CollectionPaper struct {
+	Id               string   `json:"id"`
+	Title            string   `json:"title"`
+	Abstract         string   `json:"abstract"`
+	Year             int      `json:"year"`
+	Best_oa_location string   `json:"best_oa_location"`
+	Note             string   `json:"note"`
+	Tags             []string `json:"tags"`
+	AddedAt          string   `json:"added_at"`
+}
+
+type CollectionPapersResponse struct {
+	CollectionId int64             `json:"collection_id"`
+	Papers       []CollectionPaper `json:"papers"`
+}
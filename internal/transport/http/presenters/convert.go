@@ -0,0 +1,32 @@
+package presenters
+
+import pb "VKR_gateway_service/gen/go"
+
+// MapChat converts a chat of the AI service, shared by the REST and RPC
+// APIs so chat events look the same whichever one changed the chat.
+func MapChat(chat *pb.Chat) ChatResponse {
+	if chat == nil {
+		return ChatResponse{}
+	}
+	return ChatResponse{
+		ChatId:    chat.GetChatId(),
+		UserId:    chat.GetUserId(),
+		UpdatedAt: chat.GetUpdatedAt(),
+		Title:     chat.GetTitle(),
+	}
+}
+
+// MapPapers converts search results of the AI service.
+func MapPapers(papers []*pb.PaperResponse) []Paper {
+	out := make([]Paper, 0, len(papers))
+	for _, p := range papers {
+		out = append(out, Paper{
+			Id:               p.GetID(),
+			Title:            p.GetTitle(),
+			Abstract:         p.GetAbstract(),
+			Year:             int(p.GetYear()),
+			Best_oa_location: p.GetBestOaLocation(),
+		})
+	}
+	return out
+}
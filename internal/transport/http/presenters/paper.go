@@ -17,3 +17,60 @@ type ReferencedPaper struct {
 type RelatedPaper struct {
 	Id string `json:"id"`
 }
+
+type ImportPapersResponse struct {
+	DryRun   bool               `json:"dry_run"`
+	Format   string             `json:"format"`
+	Total    int                `json:"total"`
+	Imported int                `json:"imported"`
+	Failed   int                `json:"failed"`
+	Entries  []ImportedPaper    `json:"entries"`
+	Errors   []ImportEntryError `json:"errors"`
+}
+
+type ImportedPaper struct {
+	Line   int    `json:"line"`
+	Id     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+type ImportEntryError struct {
+	Line  int    `json:"line"`
+	Id    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+type PaperDetailResponse struct {
+	Paper
+	State           string   `json:"state,omitempty"`
+	ReferencedWorks []string `json:"referenced_works"`
+	RelatedWorks    []string `json:"related_works"`
+}
+
+type PaperGraphResponse struct {
+	Root      string `json:"root"`
+	Depth     int    `json:"depth"`
+	Direction string `json:"direction"`
+	// Set when PAPER_GRAPH_MAX_NODES cut off part of the graph
+	Truncated bool             `json:"truncated"`
+	Nodes     []PaperGraphNode `json:"nodes"`
+	Edges     []PaperGraphEdge `json:"edges"`
+}
+
+type PaperGraphNode struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+	// Distance from the root paper
+	Depth int `json:"depth"`
+	// Referenced but not in the index
+	Missing bool `json:"missing,omitempty"`
+}
+
+type PaperGraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	// "references" or "related"
+	Type string `json:"type"`
+}
@@ -0,0 +1,12 @@
+package presenters
+
+// ProblemResponse is a problem detail (RFC 9457), sent as
+// application/problem+json. Error repeats Detail for clients that read
+// ErrorResponse.
+type ProblemResponse struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	Error  string `json:"error"`
+}
@@ -0,0 +1,6 @@
+package presenters
+
+type SSOSessionResponse struct {
+	// When the access token expires, empty if the SSO did not say
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,160 @@
+package v2
+
+import (
+	"VKR_gateway_service/internal/transport/http/presenters"
+)
+
+// Present converts a v1 response to its v2 shape. Lists built in memory are
+// cut to limit and offset, a limit of 0 keeps every item; lists the database
+// already paged keep their paging. Responses without a v2 shape are returned unchanged.
+func Present(out interface{}, limit, offset int) interface{} {
+	switch v := out.(type) {
+	case presenters.ChatsResponse:
+		return page(v.Chats, limit, offset)
+	case presenters.ChatHistoryResponse:
+		return page(mapMessages(v.ChatMessages), limit, offset)
+	case presenters.SearchPaperResponse:
+		return page(mapPapers(v.Papers), limit, offset)
+	case presenters.CollectionsResponse:
+		return page(v.Collections, limit, offset)
+	case presenters.CollectionPapersResponse:
+		items := make([]CollectionPaper, 0, len(v.Papers))
+		for _, p := range v.Papers {
+			items = append(items, mapCollectionPaper(p))
+		}
+		return page(items, limit, offset)
+	case presenters.ChatSharesResponse:
+		return page(v.Shares, limit, offset)
+	case presenters.AdminUsersResponse:
+		return AdminUsersPage{Items: v.Users, Paging: Paging{Limit: v.Limit, Offset: v.Offset}}
+	case presenters.AuditLogResponse:
+		return AuditEntriesPage{Items: v.Entries, Paging: Paging{Limit: v.Limit, Offset: v.Offset}}
+	case presenters.CollectionPaper:
+		return mapCollectionPaper(v)
+	case presenters.PaperDetailResponse:
+		return PaperDetail{
+			Paper:           mapPapers([]presenters.Paper{v.Paper})[0],
+			State:           v.State,
+			ReferencedWorks: v.ReferencedWorks,
+			RelatedWorks:    v.RelatedWorks,
+		}
+	case presenters.SharedChatResponse:
+		return SharedChat{Title: v.Title, ExpiresAt: v.ExpiresAt, ChatMessages: mapMessages(v.ChatMessages)}
+	case presenters.ChatRerunResponse:
+		return mapRerun(v)
+	}
+	return out
+}
+
+// BindTarget returns the v2 request to decode instead of the v1 request in,
+// and a function copying the decoded values into in. It returns nil if the
+// request has the same shape in both versions.
+func BindTarget(in interface{}) (interface{}, func()) {
+	switch v := in.(type) {
+	case *presenters.AddPaperRequest:
+		req := &AddPaperRequest{}
+		return req, func() {
+			*v = presenters.AddPaperRequest{
+				Id:               req.Id,
+				Title:            req.Title,
+				Abstract:         req.Abstract,
+				Year:             req.Year,
+				Best_oa_location: req.OpenAccessUrl,
+			}
+			for _, ref := range req.ReferencedPapers {
+				v.ReferencedPapers = append(v.ReferencedPapers, presenters.ReferencedPaper{Id: ref.Id})
+			}
+			for _, ref := range req.RelatedPapers {
+				v.RelatedPaper = append(v.RelatedPaper, presenters.RelatedPaper{Id: ref.Id})
+			}
+		}
+	case *presenters.SaveCollectionPaperRequest:
+		req := &SaveCollectionPaperRequest{}
+		return req, func() {
+			*v = presenters.SaveCollectionPaperRequest{
+				Id:   req.Id,
+				Note: req.Note,
+				Tags: req.Tags,
+			}
+		}
+	}
+	return nil, nil
+}
+
+func page[T any](items []T, limit, offset int) Page[T] {
+	total := len(items)
+	if limit <= 0 {
+		limit = total
+	}
+	start := min(offset, total)
+	end := min(start+limit, total)
+	out := make([]T, end-start)
+	copy(out, items[start:end])
+	return Page[T]{Items: out, Paging: Paging{Limit: limit, Offset: offset, Total: &total}}
+}
+
+func mapPapers(papers []presenters.Paper) []Paper {
+	out := make([]Paper, 0, len(papers))
+	for _, p := range papers {
+		out = append(out, Paper{
+			Id:            p.Id,
+			Title:         p.Title,
+			Abstract:      p.Abstract,
+			Year:          p.Year,
+			OpenAccessUrl: p.Best_oa_location,
+		})
+	}
+	return out
+}
+
+func mapMessages(msgs []presenters.ChatHistoryMessage) []ChatMessage {
+	out := make([]ChatMessage, 0, len(msgs))
+	for _, m := range msgs {
+		out = append(out, ChatMessage{SearchQuery: m.SearchQuery, CreatedAt: m.CreatedAt, Papers: mapPapers(m.Papers)})
+	}
+	return out
+}
+
+func mapCollectionPaper(p presenters.CollectionPaper) CollectionPaper {
+	return CollectionPaper{
+		Paper: Paper{
+			Id:            p.Id,
+			Title:         p.Title,
+			Abstract:      p.Abstract,
+			Year:          p.Year,
+			OpenAccessUrl: p.Best_oa_location,
+		},
+		Note:    p.Note,
+		Tags:    p.Tags,
+		AddedAt: p.AddedAt,
+	}
+}
+
+func mapRerun(v presenters.ChatRerunResponse) ChatRerun {
+	out := ChatRerun{
+		SearchQuery:       v.SearchQuery,
+		OriginalCreatedAt: v.OriginalCreatedAt,
+		CreatedAt:         v.CreatedAt,
+		Papers:            mapPapers(v.Papers),
+		Diff: ChatRerunDiff{
+			Added:       make([]RankedPaper, 0, len(v.Diff.Added)),
+			Removed:     make([]RankedPaper, 0, len(v.Diff.Removed)),
+			RankChanged: make([]PaperRankChange, 0, len(v.Diff.RankChanged)),
+			Unchanged:   v.Diff.Unchanged,
+		},
+	}
+	for _, p := range v.Diff.Added {
+		out.Diff.Added = append(out.Diff.Added, RankedPaper{Paper: mapPapers([]presenters.Paper{p.Paper})[0], Rank: p.Rank})
+	}
+	for _, p := range v.Diff.Removed {
+		out.Diff.Removed = append(out.Diff.Removed, RankedPaper{Paper: mapPapers([]presenters.Paper{p.Paper})[0], Rank: p.Rank})
+	}
+	for _, p := range v.Diff.RankChanged {
+		out.Diff.RankChanged = append(out.Diff.RankChanged, PaperRankChange{
+			Paper:        mapPapers([]presenters.Paper{p.Paper})[0],
+			PreviousRank: p.PreviousRank,
+			Rank:         p.Rank,
+		})
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+// Package v2 holds the response and request shapes of /api/v2. Handlers
+// build v1 presenters; Present and BindTarget convert between the versions,
+// so only the shapes that differ are declared here.
+//
+// Differences from v1:
+//   - lists are wrapped in Page with limit/offset paging;
+//   - papers expose best_oa_location as open_access_url;
+//   - AddPaperRequest uses referenced_papers and related_papers.
+package v2
+
+import (
+	"VKR_gateway_service/internal/transport/http/presenters"
+)
+
+type Paging struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// Total is omitted for lists paged by the database
+	Total *int `json:"total,omitempty"`
+}
+
+type Page[T any] struct {
+	Items  []T    `json:"items"`
+	Paging Paging `json:"paging"`
+}
+
+type Paper struct {
+	Id            string `json:"id"`
+	Title         string `json:"title"`
+	Abstract      string `json:"abstract"`
+	Year          int    `json:"year"`
+	OpenAccessUrl string `json:"open_access_url"`
+}
+
+type PaperDetail struct {
+	Paper
+	State           string   `json:"state,omitempty"`
+	ReferencedWorks []string `json:"referenced_works"`
+	RelatedWorks    []string `json:"related_works"`
+}
+
+type ChatMessage struct {
+	SearchQuery string  `json:"search_query"`
+	CreatedAt   string  `json:"created_at"`
+	Papers      []Paper `json:"papers"`
+}
+
+type ChatRerun struct {
+	SearchQuery       string        `json:"search_query"`
+	OriginalCreatedAt string        `json:"original_created_at"`
+	CreatedAt         string        `json:"created_at"`
+	Papers            []Paper       `json:"papers"`
+	Diff              ChatRerunDiff `json:"diff"`
+}
+
+type ChatRerunDiff struct {
+	Added       []RankedPaper     `json:"added"`
+	Removed     []RankedPaper     `json:"removed"`
+	RankChanged []PaperRankChange `json:"rank_changed"`
+	Unchanged   int               `json:"unchanged"`
+}
+
+type RankedPaper struct {
+	Paper
+	Rank int `json:"rank"`
+}
+
+type PaperRankChange struct {
+	Paper
+	PreviousRank int `json:"previous_rank"`
+	Rank         int `json:"rank"`
+}
+
+type SharedChat struct {
+	Title        string        `json:"title"`
+	ExpiresAt    string        `json:"expires_at"`
+	ChatMessages []ChatMessage `json:"chat_messages"`
+}
+
+type CollectionPaper struct {
+	Paper
+	Note    string   `json:"note"`
+	Tags    []string `json:"tags"`
+	AddedAt string   `json:"added_at"`
+}
+
+type SaveCollectionPaperRequest struct {
+	Id   string   `json:"id" binding:"required"`
+	Note string   `json:"note"`
+	Tags []string `json:"tags"`
+}
+
+type PaperRef struct {
+	Id string `json:"id"`
+}
+
+type AddPaperRequest struct {
+	Id               string     `json:"id"`
+	Title            string     `json:"title"`
+	Abstract         string     `json:"abstract"`
+	Year             int        `json:"year"`
+	OpenAccessUrl    string     `json:"open_access_url"`
+	ReferencedPapers []PaperRef `json:"referenced_papers"`
+	RelatedPapers    []PaperRef `json:"related_papers"`
+}
+
+type (
+	ChatsPage            = Page[presenters.ChatResponse]
+	ChatMessagesPage     = Page[ChatMessage]
+	PapersPage           = Page[Paper]
+	CollectionsPage      = Page[presenters.CollectionResponse]
+	CollectionPapersPage = Page[CollectionPaper]
+	ChatSharesPage       = Page[presenters.ChatShareResponse]
+	AdminUsersPage       = Page[presenters.AdminUser]
+	AuditEntriesPage     = Page[presenters.AuditEntry]
+)
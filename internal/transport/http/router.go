@@ -2,25 +2,89 @@ package http
 
 import (
 	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/domain"
+	graphqltransport "VKR_gateway_service/internal/transport/graphql"
 	"VKR_gateway_service/internal/transport/http/handlers"
+	"VKR_gateway_service/internal/transport/http/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
 func AIRouter(r *gin.RouterGroup, a *app.App) {
-	r.POST("/paper/add", func(ctx *gin.Context) { handlers.PaperAdd(ctx, a) })
+	// Writes into the shared index are limited to curators and admins
+	curator := middlewares.RequireRole(domain.RoleCurator)
+	r.POST("/paper/add", curator, func(ctx *gin.Context) { handlers.PaperAdd(ctx, a) })
+	// An import makes an AddPaper call per entry and may outlast HTTP_WRITE_TIMEOUT
+	r.POST("/papers/import", curator, middlewares.NoTimeouts(), func(ctx *gin.Context) { handlers.ImportPapers(ctx, a) })
+	r.POST("/author/add", curator, func(ctx *gin.Context) { handlers.AuthorAdd(ctx, a) })
+	r.POST("/institution/add", curator, func(ctx *gin.Context) { handlers.InstitutionAdd(ctx, a) })
 	// r.GET("/search/papers", func(ctx *gin.Context) { handlers.SearchPapers(ctx, a) })
 }
 
+func PaperRouter(r *gin.RouterGroup, a *app.App) {
+	// Serves /{paper_id}, /{paper_id}/references, /{paper_id}/related and /{paper_id}/graph
+	r.GET("/*paper_path", func(ctx *gin.Context) { handlers.PaperRoute(ctx, a) })
+}
+
 func ChatRouter(r *gin.RouterGroup, a *app.App) {
 	r.POST("", func(ctx *gin.Context) { handlers.CreateChat(ctx, a) })
 	r.GET("", func(ctx *gin.Context) { handlers.GetUserChats(ctx, a) })
 	r.GET("/:chat_id/history", func(ctx *gin.Context) { handlers.GetChatHistory(ctx, a) })
 	r.POST("/:chat_id/history", func(ctx *gin.Context) { handlers.CreateChatHistory(ctx, a) })
+	r.POST("/:chat_id/history/:index/rerun", func(ctx *gin.Context) { handlers.RerunChatHistory(ctx, a) })
 	r.PUT("/:chat_id", func(ctx *gin.Context) { handlers.UpdateChat(ctx, a) })
 	r.DELETE("/:chat_id", func(ctx *gin.Context) { handlers.DeleteChat(ctx, a) })
+	r.POST("/:chat_id/share", func(ctx *gin.Context) { handlers.CreateChatShare(ctx, a) })
+	r.GET("/:chat_id/share", func(ctx *gin.Context) { handlers.GetChatShares(ctx, a) })
+	r.DELETE("/:chat_id/share/:share_id", func(ctx *gin.Context) { handlers.RevokeChatShare(ctx, a) })
 }
 
-func SSORouter(r *gin.RouterGroup, a *app.App) {
+func WSRouter(r *gin.RouterGroup, a *app.App) {
+	r.GET("", func(ctx *gin.Context) { handlers.ChatEvents(ctx, a) })
+}
+
+func GraphQLRouter(r *gin.RouterGroup, a *app.App, schema *graphqltransport.Schema) {
+	r.POST("", func(ctx *gin.Context) { handlers.GraphQL(ctx, a, schema) })
+	r.GET("", func(ctx *gin.Context) { handlers.GraphQL(ctx, a, schema) })
+}
+
+func SharedRouter(r *gin.RouterGroup, a *app.App) {
+	r.GET("/:token", func(ctx *gin.Context) { handlers.GetSharedChat(ctx, a) })
+}
 
+func CollectionRouter(r *gin.RouterGroup, a *app.App) {
+	r.POST("", func(ctx *gin.Context) { handlers.CreateCollection(ctx, a) })
+	r.GET("", func(ctx *gin.Context) { handlers.GetUserCollections(ctx, a) })
+	r.PUT("/:collection_id", func(ctx *gin.Context) { handlers.RenameCollection(ctx, a) })
+	r.DELETE("/:collection_id", func(ctx *gin.Context) { handlers.DeleteCollection(ctx, a) })
+	r.GET("/:collection_id/papers", func(ctx *gin.Context) { handlers.GetCollectionPapers(ctx, a) })
+	r.POST("/:collection_id/papers", func(ctx *gin.Context) { handlers.SaveCollectionPaper(ctx, a) })
+	r.DELETE("/:collection_id/papers/*paper_id", func(ctx *gin.Context) { handlers.RemoveCollectionPaper(ctx, a) })
+}
+
+func APIKeyRouter(r *gin.RouterGroup, a *app.App) {
+	r.POST("", func(ctx *gin.Context) { handlers.CreateAPIKey(ctx, a) })
+	r.GET("", func(ctx *gin.Context) { handlers.GetAPIKeys(ctx, a) })
+	r.POST("/:key_id/rotate", func(ctx *gin.Context) { handlers.RotateAPIKey(ctx, a) })
+	r.DELETE("/:key_id", func(ctx *gin.Context) { handlers.RevokeAPIKey(ctx, a) })
+}
+
+func AdminRouter(r *gin.RouterGroup, a *app.App) {
+	r.GET("/users", func(ctx *gin.Context) { handlers.AdminGetUsers(ctx, a) })
+	r.PUT("/users/:user_id/role", func(ctx *gin.Context) { handlers.AdminSetUserRole(ctx, a) })
+	r.GET("/users/:user_id/chats", func(ctx *gin.Context) { handlers.AdminGetUserChats(ctx, a) })
+	r.GET("/chats/:chat_id/history", func(ctx *gin.Context) { handlers.AdminGetChatHistory(ctx, a) })
+	r.DELETE("/chats/:chat_id", func(ctx *gin.Context) { handlers.AdminDeleteChat(ctx, a) })
+	r.GET("/audit", func(ctx *gin.Context) { handlers.AdminGetAuditLog(ctx, a) })
+	r.GET("/audit/export", middlewares.NoTimeouts(), func(ctx *gin.Context) { handlers.AdminExportAuditLog(ctx, a) })
+	r.GET("/audit/verify", func(ctx *gin.Context) { handlers.AdminVerifyAuditLog(ctx, a) })
+	r.GET("/config", func(ctx *gin.Context) { handlers.AdminGetConfig(ctx, a) })
+	r.GET("/db/stats", func(ctx *gin.Context) { handlers.AdminGetDBStats(ctx, a) })
+}
+
+func SSORouter(r *gin.RouterGroup, a *app.App) {
+	r.GET("/login", func(ctx *gin.Context) { handlers.SSOLogin(ctx, a) })
+	r.GET("/callback", func(ctx *gin.Context) { handlers.SSOCallback(ctx, a) })
+	r.POST("/refresh", func(ctx *gin.Context) { handlers.SSORefresh(ctx, a) })
+	r.POST("/logout", func(ctx *gin.Context) { handlers.SSOLogout(ctx, a) })
 }
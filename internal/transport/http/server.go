@@ -1,13 +1,22 @@
 package http
 
 import (
+	pb "VKR_gateway_service/gen/go"
 	"VKR_gateway_service/internal/app"
 	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/domain"
+	"VKR_gateway_service/internal/transport/connectrpc"
+	graphqltransport "VKR_gateway_service/internal/transport/graphql"
+	"VKR_gateway_service/internal/transport/http/handlers"
 	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/transcode"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	docs "VKR_gateway_service/docs"
 
@@ -15,25 +24,58 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
+// uploadRoutes accept bodies up to HTTP_MAX_UPLOAD_SIZE instead of
+// HTTP_MAX_BODY_SIZE, keyed by the unversioned route.
+var uploadRoutes = map[string]bool{
+	"/api/ai/papers/import": true,
+}
+
 type Server struct {
 	domain     string
 	port       string
 	app        *gin.Engine
 	httpServer *http.Server
+	// Plain HTTP listener redirecting to HTTPS, nil without TLS_REDIRECT_PORT
+	redirectServer *http.Server
 }
 
-func NewHTTPServer(conf *config.Config, a *app.App) *Server {
+// NewHTTPServer builds the router and the listeners. It returns an error
+// instead of exiting, so the caller can release what was opened before.
+func NewHTTPServer(conf *config.Config, a *app.App) (*Server, error) {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 	r.Use(
 		gin.Recovery(),
-		gin.Logger(),
+		gin.LoggerWithConfig(gin.LoggerConfig{SkipPaths: []string{"/healthz", "/readyz"}}),
+		middlewares.RequestID(),
+		middlewares.SecurityHeaders(a),
+		middlewares.BodyLimit(func(c *gin.Context) int64 { return bodyLimit(a, c) }),
 	)
+	// Without trusted proxies ClientIP is the peer address, X-Forwarded-For
+	// is only honoured when it comes from one of them
+	if err := r.SetTrustedProxies(conf.HttpServerConfig.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	var handler http.Handler = r
+	srv := conf.HttpServerConfig
+	// HTTP/2 is negotiated with ALPN over TLS; without TLS h2c lets gRPC
+	// clients use it
+	h2 := &http2.Server{IdleTimeout: srv.IdleTimeout}
+	if !conf.TLSConfig.Enabled() {
+		handler = h2c.NewHandler(handler, h2)
+	}
 	httpServer := &http.Server{
-		Addr:    ":" + conf.HttpServerConfig.Port,
-		Handler: r,
+		Addr:              ":" + srv.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: srv.ReadHeaderTimeout,
+		ReadTimeout:       srv.ReadTimeout,
+		WriteTimeout:      srv.WriteTimeout,
+		IdleTimeout:       srv.IdleTimeout,
+		MaxHeaderBytes:    srv.MaxHeaderBytes,
 	}
 	s := Server{
 		domain:     conf.Domain,
@@ -41,13 +83,32 @@ func NewHTTPServer(conf *config.Config, a *app.App) *Server {
 		app:        r,
 		httpServer: httpServer,
 	}
-
-	allowed := conf.AllowedCORSOrigins
-	if len(allowed) == 0 {
-		a.Logger.Fatalf("no allowed CORS origins configured")
-		return nil
+	if conf.TLSConfig.Enabled() {
+		tlsConf, challenge, err := newTLSConfig(conf, a)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		httpServer.TLSConfig = tlsConf
+		if err := http2.ConfigureServer(httpServer, h2); err != nil {
+			return nil, fmt.Errorf("failed to configure HTTP/2: %w", err)
+		}
+		if port := conf.TLSConfig.RedirectPort; port != "" {
+			s.redirectServer = &http.Server{
+				Addr:              ":" + port,
+				Handler:           challenge(redirectToHTTPS(conf)),
+				ReadHeaderTimeout: srv.ReadHeaderTimeout,
+				ReadTimeout:       srv.ReadTimeout,
+				WriteTimeout:      srv.WriteTimeout,
+				IdleTimeout:       srv.IdleTimeout,
+				MaxHeaderBytes:    srv.MaxHeaderBytes,
+			}
+		}
 	}
 
+	// Probes for orchestrators and load balancers, outside the API
+	r.GET("/healthz", handlers.Healthz)
+	r.GET("/readyz", func(ctx *gin.Context) { handlers.Readyz(ctx, a) })
+
 	docs.SwaggerInfo.BasePath = "/api"
 
 	if conf.PublicURL != "" {
@@ -61,47 +122,212 @@ func NewHTTPServer(conf *config.Config, a *app.App) *Server {
 		}
 	} else {
 		docs.SwaggerInfo.Host = conf.Domain + ":" + conf.HttpServerConfig.Port
-		if len(docs.SwaggerInfo.Schemes) == 0 {
+		if conf.TLSConfig.Enabled() {
+			docs.SwaggerInfo.Schemes = []string{"https"}
+		} else if len(docs.SwaggerInfo.Schemes) == 0 {
 			docs.SwaggerInfo.Schemes = []string{"http"}
 		}
 	}
 
-	s.app.Use(cors.New(cors.Config{
-		AllowOrigins:     allowed,
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
-		AllowCredentials: true,
+	s.app.Use(middlewares.CORS(a, apiCORS, map[string]middlewares.CORSPolicy{
+		"/api/shared/":                     sharedCORS,
+		"/" + connectrpc.ServiceName + "/": rpcCORS,
 	}))
 
+	// Connect, gRPC-Web and gRPC share the port and the request id, security
+	// header and CORS middleware with REST; authentication is done by the
+	// Connect interceptor
+	if conf.PublicRPCEnabled {
+		path, rpc := connectrpc.NewHandler(a)
+		s.app.POST(path+"*procedure", serveRPC(rpc))
+		s.app.GET(path+"*procedure", serveRPC(rpc))
+	}
+
 	if conf.SwaggerEnabled {
+		// v2 is derived from the generated v1 document before the v1 routes are
+		// marked deprecated and the transcoded routes are merged into it
+		if err := registerSwaggerV2(docs.SwaggerInfo); err != nil {
+			return nil, fmt.Errorf("failed to build v2 swagger: %w", err)
+		}
+		if err := deprecateSwaggerV1(docs.SwaggerInfo); err != nil {
+			return nil, fmt.Errorf("failed to build v1 swagger: %w", err)
+		}
+		docsHandler := swaggerHandler(
+			ginSwagger.WrapHandler(swaggerFiles.Handler),
+			ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName(swaggerV2Instance)),
+		)
 		if conf.SwaggerUser != "" && conf.SwaggerPassword != "" {
-			authorized := s.app.Group("/swagger", gin.BasicAuth(gin.Accounts{
-				conf.SwaggerUser: conf.SwaggerPassword,
-			}))
-			authorized.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+			authorized := s.app.Group("/swagger", swaggerBasicAuth(a))
+			authorized.GET("/*any", docsHandler)
 		} else {
-			s.app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+			s.app.GET("/swagger/*any", docsHandler)
+		}
+	}
+	schema, err := graphqltransport.NewSchema(a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+
+	// /api stays an alias of v1 for clients pinned to the original shapes
+	versions := conf.APIVersionConfig
+	v1 := middlewares.SetAPIVersion(middlewares.APIV1, versions.V1DeprecatedAt, versions.V1SunsetAt, "/api/v2")
+	v2 := middlewares.SetAPIVersion(middlewares.APIV2, time.Time{}, time.Time{}, "")
+	APIRouters(s.app.Group("/api", v1), a, schema)
+	APIRouters(s.app.Group("/api/v1", v1), a, schema)
+	APIRouters(s.app.Group("/api/v2", v2, middlewares.Paging()), a, schema)
+
+	// REST routes declared with google.api.http annotations in the proto
+	if conf.PublicRPCEnabled {
+		rules, err := transcode.Rules(pb.File_service_proto.Services().ByName("SemanticService"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HTTP annotations: %w", err)
+		}
+		rpc := s.app.Group("/")
+		rpc.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a))
+		if err := transcode.Register(rpc, a, rules); err != nil {
+			return nil, fmt.Errorf("failed to register transcoded routes: %w", err)
+		}
+		if err := transcode.MergeSwagger(docs.SwaggerInfo, rules); err != nil {
+			return nil, fmt.Errorf("failed to merge transcoded routes into swagger: %w", err)
 		}
 	}
+	return &s, nil
+}
+
+// APIRouters mounts the REST API under one version prefix.
+func APIRouters(api *gin.RouterGroup, a *app.App, schema *graphqltransport.Schema) {
 	// Public routers
-	SSORouter(s.app.Group("/api/sso/"), a)
+	sso := api.Group("/sso/")
+	sso.Use(middlewares.CSRF(a), middlewares.Audit(a))
+	SSORouter(sso, a)
+	SharedRouter(api.Group("/shared/"), a)
 
-	// Protected routers
-	ai := s.app.Group("/api/ai/")
-	ai.Use(middlewares.AuthMiddleware(a))
+	// Protected routers; API keys additionally need the scope of the resource.
+	// CSRF runs first so a forged request is refused before the token is
+	// validated, Audit after authentication so anonymous requests are not
+	// written to the audit log.
+	ai := api.Group("/ai/")
+	ai.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a), middlewares.RequireResourceScope("papers"))
 	AIRouter(ai, a)
 
-	chat := s.app.Group("/api/chats/")
-	chat.Use(middlewares.AuthMiddleware(a))
+	papers := api.Group("/papers/")
+	papers.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a), middlewares.RequireResourceScope("papers"))
+	PaperRouter(papers, a)
+
+	chat := api.Group("/chats/")
+	chat.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a), middlewares.RequireResourceScope("chats"), middlewares.ActAs())
 	ChatRouter(chat, a)
-	return &s
+
+	collections := api.Group("/collections/")
+	collections.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a), middlewares.RequireResourceScope("collections"), middlewares.ActAs())
+	CollectionRouter(collections, a)
+
+	ws := api.Group("/ws")
+	ws.Use(middlewares.NoTimeouts(), middlewares.WebSocketAuth(), middlewares.AuthMiddleware(a), middlewares.RequireScope(domain.ScopeChatsRead), middlewares.TrackStream(a.Lifecycle))
+	WSRouter(ws, a)
+
+	// GraphQL is read-only and spans chats and the paper index
+	graphql := api.Group("/graphql")
+	graphql.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a), middlewares.RequireScope(domain.ScopeChatsRead, domain.ScopePapersRead))
+	GraphQLRouter(graphql, a, schema)
+
+	keys := api.Group("/keys")
+	keys.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a), middlewares.DenyAPIKeys())
+	APIKeyRouter(keys, a)
+
+	admin := api.Group("/admin/")
+	if a.Config().TLSConfig.ClientCAFile != "" {
+		admin.Use(middlewares.RequireClientCert())
+	}
+	admin.Use(middlewares.CSRF(a), middlewares.AuthMiddleware(a), middlewares.Audit(a), middlewares.RequireRole(domain.RoleAdmin), middlewares.RequireResourceScope("admin"))
+	AdminRouter(admin, a)
 }
 
+// Listen serves until Stop or the first listener error.
 func (s *Server) Listen() error {
-	fmt.Printf("Server is running on %s:%s\n", s.domain, s.port)
-	return s.httpServer.ListenAndServe()
+	errs := make(chan error, 2)
+	if s.redirectServer != nil {
+		fmt.Printf("Redirecting to HTTPS on %s:%s\n", s.domain, strings.TrimPrefix(s.redirectServer.Addr, ":"))
+		go func() { errs <- s.redirectServer.ListenAndServe() }()
+	}
+	go func() {
+		if s.httpServer.TLSConfig != nil {
+			fmt.Printf("Server is running on https://%s:%s\n", s.domain, s.port)
+			errs <- s.httpServer.ListenAndServeTLS("", "")
+			return
+		}
+		fmt.Printf("Server is running on %s:%s\n", s.domain, s.port)
+		errs <- s.httpServer.ListenAndServe()
+	}()
+	return <-errs
 }
 
 func (s *Server) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	err := s.httpServer.Shutdown(ctx)
+	if s.redirectServer != nil {
+		err = errors.Join(err, s.redirectServer.Shutdown(ctx))
+	}
+	return err
+}
+
+func bodyLimit(a *app.App, c *gin.Context) int64 {
+	srv := a.Config().HttpServerConfig
+	if uploadRoutes[middlewares.UnversionedRoute(c.FullPath())] {
+		return srv.MaxUploadSize
+	}
+	return srv.MaxBodySize
+}
+
+// apiCORS lets the allowed origins call the API with cookies.
+func apiCORS(cfg *config.Config) cors.Config {
+	return cors.Config{
+		AllowOriginFunc: func(origin string) bool { return middlewares.OriginAllowed(cfg.AllowedCORSOrigins, origin) },
+		AllowMethods:    []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{
+			"Content-Type", "Authorization", "Idempotency-Key", middlewares.RequestIDHeader,
+			middlewares.ActAsHeader, middlewares.CSRFHeader,
+		},
+		ExposeHeaders:    cfg.CORSConfig.ExposedHeaders,
+		AllowCredentials: true,
+		MaxAge:           cfg.CORSConfig.MaxAge,
+	}
+}
+
+// serveRPC hands the request to the Connect handler with the request id
+// chosen by the RequestID middleware, so the interceptor logs and audits
+// the id the client sees.
+func serveRPC(h http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Header.Set(middlewares.RequestIDHeader, middlewares.GetRequestID(c))
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// rpcCORS lets the allowed origins call the Connect and gRPC-Web API from
+// the browser. Calls are authenticated with a token or API key header,
+// never with cookies.
+func rpcCORS(cfg *config.Config) cors.Config {
+	return cors.Config{
+		AllowOriginFunc: func(origin string) bool { return middlewares.OriginAllowed(cfg.AllowedCORSOrigins, origin) },
+		AllowMethods:    []string{"GET", "POST", "OPTIONS"},
+		AllowHeaders: []string{
+			"Content-Type", "Authorization", middlewares.APIKeyHeader, middlewares.RequestIDHeader,
+			"Connect-Protocol-Version", "Connect-Timeout-Ms", "Grpc-Timeout", "X-Grpc-Web", "X-User-Agent",
+		},
+		ExposeHeaders: append([]string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin", middlewares.RequestIDHeader},
+			cfg.CORSConfig.ExposedHeaders...),
+		MaxAge: cfg.CORSConfig.MaxAge,
+	}
+}
+
+// sharedCORS lets pages embed public share links; they are read-only and
+// need no credentials.
+func sharedCORS(cfg *config.Config) cors.Config {
+	return cors.Config{
+		AllowOriginFunc: func(origin string) bool { return middlewares.OriginAllowed(cfg.CORSConfig.SharedOrigins, origin) },
+		AllowMethods:    []string{"GET", "HEAD", "OPTIONS"},
+		AllowHeaders:    []string{"Content-Type", middlewares.RequestIDHeader},
+		ExposeHeaders:   cfg.CORSConfig.ExposedHeaders,
+		MaxAge:          cfg.CORSConfig.MaxAge,
+	}
 }
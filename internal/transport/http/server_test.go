@@ -0,0 +1,122 @@
+package http
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/repository/repotest"
+	"VKR_gateway_service/pkg/lifecycle"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNewHTTPServerReturnsErrors(t *testing.T) {
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.HttpServerConfig.TrustedProxies = []string{"not-an-ip"}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	a := &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, Lifecycle: lifecycle.New(logger)}
+
+	s, err := NewHTTPServer(cfg, a)
+	if err == nil || s != nil {
+		t.Fatalf("NewHTTPServer() = %v, %v, want an error", s, err)
+	}
+	if !strings.Contains(err.Error(), "invalid trusted proxies") {
+		t.Fatalf("NewHTTPServer() error = %v", err)
+	}
+}
+
+func TestAnonymousRequestsAreNotAudited(t *testing.T) {
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SwaggerEnabled = false
+	cfg.PublicRPCEnabled = false
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	audit := &repotest.AuditRepository{}
+	a := &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, Lifecycle: lifecycle.New(logger), Audit: audit}
+	s, err := NewHTTPServer(cfg, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/chats/", strings.NewReader(`{"title":"t"}`)),
+		httptest.NewRequest(http.MethodDelete, "/api/v2/collections/1", nil),
+		httptest.NewRequest(http.MethodPut, "/api/admin/users/1/role", strings.NewReader(`{"role":"admin"}`)),
+		httptest.NewRequest(http.MethodPost, "/api/sso/refresh", nil),
+		httptest.NewRequest(http.MethodPost, "/api/sso/logout", nil),
+		httptest.NewRequest(http.MethodPost, "/api/no-such-route", nil),
+		// Refused by BodyLimit before anything reads the body
+		httptest.NewRequest(http.MethodPost, "/api/chats/", strings.NewReader(strings.Repeat("x", 64<<20))),
+	}
+	for _, req := range requests {
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		s.app.ServeHTTP(w, req)
+		if w.Code < http.StatusBadRequest && req.URL.Path != "/api/sso/logout" {
+			t.Errorf("%s %s = %d, want an error", req.Method, req.URL.Path, w.Code)
+		}
+	}
+	if entries := audit.Entries(); len(entries) != 0 {
+		t.Fatalf("got %d audit entries for anonymous requests: %+v", len(entries), entries)
+	}
+}
+
+func TestConnectRoutesShareRESTMiddleware(t *testing.T) {
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SwaggerEnabled = false
+	cfg.PublicRPCEnabled = true
+	cfg.AllowedCORSOrigins = []string{"https://app.example"}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	a := &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, Lifecycle: lifecycle.New(logger)}
+	s, err := NewHTTPServer(cfg, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const procedure = "/semantic.SemanticService/GetPaper"
+
+	// gRPC-Web preflight from the browser
+	req := httptest.NewRequest(http.MethodOptions, procedure, nil)
+	req.Header.Set("Origin", "https://app.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web,x-user-agent,authorization")
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example" {
+		t.Fatalf("preflight = %d, headers %v", w.Code, w.Header())
+	}
+	req.Header.Set("Origin", "https://evil.example")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("preflight from a foreign origin allowed: %v", w.Header())
+	}
+
+	// The call itself is refused without a token, and carries the id the
+	// client sent, the security headers and the exposed gRPC headers
+	req = httptest.NewRequest(http.MethodPost, procedure, strings.NewReader(`{"ID":"10.1000/1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "https://app.example")
+	req.Header.Set("X-Request-Id", "rpc-test-1")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	h := w.Header()
+	if w.Code != http.StatusUnauthorized || h.Get("X-Request-Id") != "rpc-test-1" || h.Get("X-Content-Type-Options") != "nosniff" ||
+		h.Get("Access-Control-Allow-Origin") != "https://app.example" || !strings.Contains(h.Get("Access-Control-Expose-Headers"), "Grpc-Status") {
+		t.Fatalf("call = %d, headers %v: %s", w.Code, h, w.Body)
+	}
+}
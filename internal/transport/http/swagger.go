@@ -0,0 +1,245 @@
+package http
+
+import (
+	"VKR_gateway_service/internal/app"
+	presentersv2 "VKR_gateway_service/internal/transport/http/presenters/v2"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+)
+
+const swaggerV2Instance = "v2"
+
+type v2Definition struct {
+	name  string
+	value interface{}
+	paged bool
+}
+
+// v2Definitions maps swag definitions of v1 presenters to the v2 shapes
+// that replace them, see presenters/v2.
+var v2Definitions = map[string]v2Definition{
+	"presenters.ChatsResponse":              {"v2.ChatsPage", presentersv2.ChatsPage{}, true},
+	"presenters.ChatHistoryResponse":        {"v2.ChatMessagesPage", presentersv2.ChatMessagesPage{}, true},
+	"presenters.SearchPaperResponse":        {"v2.PapersPage", presentersv2.PapersPage{}, true},
+	"presenters.CollectionsResponse":        {"v2.CollectionsPage", presentersv2.CollectionsPage{}, true},
+	"presenters.CollectionPapersResponse":   {"v2.CollectionPapersPage", presentersv2.CollectionPapersPage{}, true},
+	"presenters.ChatSharesResponse":         {"v2.ChatSharesPage", presentersv2.ChatSharesPage{}, true},
+	"presenters.AdminUsersResponse":         {"v2.AdminUsersPage", presentersv2.AdminUsersPage{}, false},
+	"presenters.AuditLogResponse":           {"v2.AuditEntriesPage", presentersv2.AuditEntriesPage{}, false},
+	"presenters.CollectionPaper":            {"v2.CollectionPaper", presentersv2.CollectionPaper{}, false},
+	"presenters.PaperDetailResponse":        {"v2.PaperDetail", presentersv2.PaperDetail{}, false},
+	"presenters.SharedChatResponse":         {"v2.SharedChat", presentersv2.SharedChat{}, false},
+	"presenters.ChatRerunResponse":          {"v2.ChatRerun", presentersv2.ChatRerun{}, false},
+	"presenters.AddPaperRequest":            {"v2.AddPaperRequest", presentersv2.AddPaperRequest{}, false},
+	"presenters.SaveCollectionPaperRequest": {"v2.SaveCollectionPaperRequest", presentersv2.SaveCollectionPaperRequest{}, false},
+}
+
+// swaggerHandler serves the v1 document under /swagger/ and the v2 document
+// under /swagger/v2/.
+func swaggerHandler(v1, v2 gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Param("any"), "/v2/") {
+			v2(c)
+			return
+		}
+		v1(c)
+	}
+}
+
+// swaggerBasicAuth checks credentials against the current config, so a
+// rotated SWAGGER_PASSWORD applies without a restart.
+func swaggerBasicAuth(a *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := a.Config()
+		user, password, ok := c.Request.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(user), []byte(cfg.SwaggerUser)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(cfg.SwaggerPassword)) != 1 {
+			c.Header("WWW-Authenticate", `Basic realm="Authorization Required"`)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
+
+// deprecateSwaggerV1 marks every operation of the v1 document as deprecated.
+func deprecateSwaggerV1(v1 *swag.Spec) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(v1.ReadDoc()), &doc); err != nil {
+		return fmt.Errorf("failed to parse swagger spec: %w", err)
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, item := range paths {
+		ops, _ := item.(map[string]interface{})
+		for _, raw := range ops {
+			if op, ok := raw.(map[string]interface{}); ok {
+				op["deprecated"] = true
+			}
+		}
+	}
+	out, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return err
+	}
+	v1.SwaggerTemplate = strings.ReplaceAll(string(out), "{{", `{{"{{"}}`)
+	return nil
+}
+
+// registerSwaggerV2 derives the /api/v2 document from the swag generated v1
+// document and registers it as the "v2" swag instance.
+func registerSwaggerV2(v1 *swag.Spec) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(v1.ReadDoc()), &doc); err != nil {
+		return fmt.Errorf("failed to parse swagger spec: %w", err)
+	}
+	doc["basePath"] = "/api/v2"
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		info["version"] = "2.0"
+	}
+
+	defs, _ := doc["definitions"].(map[string]interface{})
+	if defs == nil {
+		defs = map[string]interface{}{}
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, item := range paths {
+		ops, _ := item.(map[string]interface{})
+		for method, raw := range ops {
+			if op, ok := raw.(map[string]interface{}); ok {
+				v2Operation(method, op)
+			}
+		}
+	}
+	for v1Name, def := range v2Definitions {
+		delete(defs, v1Name)
+		definition(def.name, reflect.TypeOf(def.value), defs)
+	}
+	doc["definitions"] = defs
+
+	out, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return err
+	}
+	spec := &swag.Spec{
+		InfoInstanceName: swaggerV2Instance,
+		SwaggerTemplate:  strings.ReplaceAll(string(out), "{{", `{{"{{"}}`),
+	}
+	if swag.GetSwagger(swaggerV2Instance) == nil {
+		swag.Register(swaggerV2Instance, spec)
+	}
+	return nil
+}
+
+// v2Operation replaces references to v1 presenters and adds paging
+// parameters to GET operations returning paged lists.
+func v2Operation(method string, op map[string]interface{}) {
+	paged := false
+	replace := func(schema interface{}) {
+		s, ok := schema.(map[string]interface{})
+		if !ok {
+			return
+		}
+		ref, _ := s["$ref"].(string)
+		if def, ok := v2Definitions[strings.TrimPrefix(ref, "#/definitions/")]; ok {
+			s["$ref"] = "#/definitions/" + def.name
+			paged = paged || def.paged
+		}
+	}
+	if responses, ok := op["responses"].(map[string]interface{}); ok {
+		for _, raw := range responses {
+			if resp, ok := raw.(map[string]interface{}); ok {
+				replace(resp["schema"])
+			}
+		}
+	}
+	params, _ := op["parameters"].([]interface{})
+	names := map[string]bool{}
+	for _, raw := range params {
+		if p, ok := raw.(map[string]interface{}); ok {
+			replace(p["schema"])
+			name, _ := p["name"].(string)
+			names[name] = true
+		}
+	}
+	if paged && method == "get" && !names["limit"] {
+		params = append(params,
+			map[string]interface{}{"type": "integer", "description": "Page size, 50 by default", "name": "limit", "in": "query"},
+			map[string]interface{}{"type": "integer", "description": "Items to skip", "name": "offset", "in": "query"},
+		)
+		op["parameters"] = params
+	}
+}
+
+// definition adds the schema of struct type t to defs under name together
+// with the named structs it uses.
+func definition(name string, t reflect.Type, defs map[string]interface{}) {
+	if _, ok := defs[name]; ok {
+		return
+	}
+	properties := map[string]interface{}{}
+	def := map[string]interface{}{"type": "object", "properties": properties}
+	defs[name] = def
+	var required []string
+	structFields(t, properties, &required, defs)
+	if len(required) > 0 {
+		def["required"] = required
+	}
+}
+
+func structFields(t reflect.Type, properties map[string]interface{}, required *[]string, defs map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			structFields(f.Type, properties, required, defs)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = typeSchema(f.Type, defs)
+		if strings.Contains(f.Tag.Get("binding"), "required") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func typeSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), defs)
+	case reflect.Struct:
+		name := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:] + "." + t.Name()
+		definition(name, t, defs)
+		return map[string]interface{}{"$ref": "#/definitions/" + name}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), defs)}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	default:
+		return map[string]interface{}{"type": "object"}
+	}
+}
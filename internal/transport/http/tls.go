@@ -0,0 +1,109 @@
+package http
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/pkg/certreload"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// newTLSConfig returns the server TLS config, with a certificate from
+// TLS_CERT_FILE that is reread as it changes or from the ACME CA. challenge
+// wraps the redirect handler to answer ACME HTTP-01 challenges.
+func newTLSConfig(conf *config.Config, a *app.App) (tlsConf *tls.Config, challenge func(http.Handler) http.Handler, err error) {
+	cfg := conf.TLSConfig
+	challenge = func(h http.Handler) http.Handler { return h }
+	if cfg.ACMEEnabled() {
+		client := &acme.Client{DirectoryURL: cfg.ACMEDirectoryURL}
+		if cfg.ACMECAFile != "" {
+			roots, err := loadCertPool(cfg.ACMECAFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("ACME CA: %w", err)
+			}
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+			client.HTTPClient = &http.Client{Transport: transport}
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.ACMEDomains...),
+			Cache:      autocert.DirCache(cfg.ACMECacheDir),
+			Email:      cfg.ACMEEmail,
+			Client:     client,
+		}
+		// Offers h2, http/1.1 and the TLS-ALPN-01 challenge
+		tlsConf = manager.TLSConfig()
+		challenge = manager.HTTPHandler
+	} else {
+		certs, err := certreload.New(cfg.CertFile, cfg.KeyFile, a.Logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		a.Lifecycle.Go(func(ctx context.Context) { certs.Run(ctx, cfg.ReloadInterval) })
+		tlsConf = &tls.Config{
+			GetCertificate: certs.GetCertificate,
+			NextProtos:     []string{"h2", "http/1.1"},
+		}
+	}
+	tlsConf.MinVersion = tls.VersionTLS12
+	if cfg.ClientCAFile != "" {
+		pool, err := loadCertPool(cfg.ClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("client CA: %w", err)
+		}
+		// Certificates are only required on admin routes, see RequireClientCert
+		tlsConf.ClientCAs = pool
+		tlsConf.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConf, challenge, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates in %s", path)
+	}
+	return pool, nil
+}
+
+// redirectToHTTPS sends plain HTTP requests to the same path on PUBLIC_URL
+// if it is https, otherwise on the request host at HTTP_PORT.
+func redirectToHTTPS(conf *config.Config) http.Handler {
+	origin := ""
+	if u, err := url.Parse(conf.PublicURL); err == nil && u.Scheme == "https" {
+		origin = "https://" + u.Host
+	}
+	port := conf.HttpServerConfig.Port
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := origin
+		if target == "" {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			host = strings.Trim(host, "[]")
+			if port != "443" {
+				host = net.JoinHostPort(host, port)
+			} else if strings.Contains(host, ":") {
+				host = "[" + host + "]"
+			}
+			target = "https://" + host
+		}
+		http.Redirect(w, r, target+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/pkg/lifecycle"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newPair returns a PEM self-signed certificate for name and its key.
+func newPair(t *testing.T, name string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTLSTestApp(t *testing.T) (*config.Config, *app.App) {
+	t.Helper()
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	a := &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, Lifecycle: lifecycle.New(logger)}
+	t.Cleanup(func() { a.Lifecycle.StopWorkers(context.Background()) })
+	return cfg, a
+}
+
+// handshake connects to a TLS server with tlsConf and returns the common
+// name of the certificate it presented for serverName.
+func handshake(t *testing.T, tlsConf *tls.Config, serverName string) (string, error) {
+	t.Helper()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", tlsConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", ln.Addr().String(),
+		&tls.Config{ServerName: serverName, InsecureSkipVerify: true})
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestTLSConfigReloadsCertificateFiles(t *testing.T) {
+	cfg, a := newTLSTestApp(t)
+	dir := t.TempDir()
+	cfg.TLSConfig.CertFile = filepath.Join(dir, "tls.crt")
+	cfg.TLSConfig.KeyFile = filepath.Join(dir, "tls.key")
+	cfg.TLSConfig.ReloadInterval = 5 * time.Millisecond
+	oldCert, oldKey := newPair(t, "old.example")
+	writeFile(t, cfg.TLSConfig.CertFile, oldCert)
+	writeFile(t, cfg.TLSConfig.KeyFile, oldKey)
+
+	tlsConf, _, err := newTLSConfig(cfg, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConf.MinVersion != tls.VersionTLS12 || !slices.Equal(tlsConf.NextProtos, []string{"h2", "http/1.1"}) {
+		t.Fatalf("MinVersion = %x, NextProtos = %v", tlsConf.MinVersion, tlsConf.NextProtos)
+	}
+	if name, err := handshake(t, tlsConf, "old.example"); err != nil || name != "old.example" {
+		t.Fatalf("handshake = %q, %v", name, err)
+	}
+
+	// A certificate replaced before its key is not loaded
+	newCert, newKey := newPair(t, "new.example")
+	writeFile(t, cfg.TLSConfig.CertFile, newCert)
+	time.Sleep(50 * time.Millisecond)
+	if name, err := handshake(t, tlsConf, "new.example"); err != nil || name != "old.example" {
+		t.Fatalf("handshake with a mismatched pair on disk = %q, %v, want old.example kept", name, err)
+	}
+
+	writeFile(t, cfg.TLSConfig.KeyFile, newKey)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		name, err := handshake(t, tlsConf, "new.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name == "new.example" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("renewed certificate not served")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTLSConfigRejectsInvalidFiles(t *testing.T) {
+	cfg, a := newTLSTestApp(t)
+	dir := t.TempDir()
+	cfg.TLSConfig.CertFile = filepath.Join(dir, "tls.crt")
+	cfg.TLSConfig.KeyFile = filepath.Join(dir, "tls.key")
+	certPEM, _ := newPair(t, "a.example")
+	_, otherKey := newPair(t, "b.example")
+	writeFile(t, cfg.TLSConfig.CertFile, certPEM)
+	writeFile(t, cfg.TLSConfig.KeyFile, otherKey)
+	if _, _, err := newTLSConfig(cfg, a); err == nil {
+		t.Fatal("newTLSConfig() accepted a mismatched pair")
+	}
+
+	certPEM, keyPEM := newPair(t, "a.example")
+	cfg.TLSConfig.ClientCAFile = filepath.Join(dir, "ca.pem")
+	writeFile(t, cfg.TLSConfig.CertFile, certPEM)
+	writeFile(t, cfg.TLSConfig.KeyFile, keyPEM)
+	writeFile(t, cfg.TLSConfig.ClientCAFile, []byte("no certificates here"))
+	if _, _, err := newTLSConfig(cfg, a); err == nil || !strings.Contains(err.Error(), "client CA") {
+		t.Fatalf("newTLSConfig() with a bad client CA = %v", err)
+	}
+}
+
+func TestTLSConfigACME(t *testing.T) {
+	cfg, a := newTLSTestApp(t)
+	cfg.TLSConfig.ACMEDomains = []string{"gateway.example"}
+	cfg.TLSConfig.ACMECacheDir = t.TempDir()
+	// Nothing may reach a real CA
+	cfg.TLSConfig.ACMEDirectoryURL = "https://127.0.0.1:1/directory"
+
+	// A certificate already in the cache is served without contacting the CA
+	certPEM, keyPEM := newPair(t, "gateway.example")
+	writeFile(t, filepath.Join(cfg.TLSConfig.ACMECacheDir, "gateway.example"), append(keyPEM, certPEM...))
+
+	tlsConf, challenge, err := newTLSConfig(cfg, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConf.MinVersion != tls.VersionTLS12 || !slices.Contains(tlsConf.NextProtos, "acme-tls/1") {
+		t.Fatalf("MinVersion = %x, NextProtos = %v", tlsConf.MinVersion, tlsConf.NextProtos)
+	}
+	if name, err := handshake(t, tlsConf, "gateway.example"); err != nil || name != "gateway.example" {
+		t.Fatalf("handshake = %q, %v", name, err)
+	}
+	// Hosts outside TLS_ACME_DOMAINS get no certificate
+	if _, err := handshake(t, tlsConf, "other.example"); err == nil {
+		t.Fatal("handshake for a host outside TLS_ACME_DOMAINS succeeded")
+	}
+
+	// The HTTP handler answers challenges and redirects everything else
+	cfg.PublicURL = "https://gateway.example"
+	h := challenge(redirectToHTTPS(cfg))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://gateway.example/api/papers?q=1", nil))
+	if w.Code != http.StatusPermanentRedirect || w.Header().Get("Location") != "https://gateway.example/api/papers?q=1" {
+		t.Fatalf("plain request = %d to %q", w.Code, w.Header().Get("Location"))
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://gateway.example/.well-known/acme-challenge/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unknown challenge = %d, want 404", w.Code)
+	}
+}
+
+func TestTLSConfigACMECAFile(t *testing.T) {
+	cfg, a := newTLSTestApp(t)
+	cfg.TLSConfig.ACMEDomains = []string{"gateway.example"}
+	cfg.TLSConfig.ACMECacheDir = t.TempDir()
+	cfg.TLSConfig.ACMECAFile = filepath.Join(t.TempDir(), "ca.pem")
+
+	writeFile(t, cfg.TLSConfig.ACMECAFile, []byte("not PEM"))
+	if _, _, err := newTLSConfig(cfg, a); err == nil || !strings.Contains(err.Error(), "ACME CA") {
+		t.Fatalf("newTLSConfig() with a bad ACME CA = %v", err)
+	}
+
+	caPEM, _ := newPair(t, "ca.example")
+	writeFile(t, cfg.TLSConfig.ACMECAFile, caPEM)
+	if _, _, err := newTLSConfig(cfg, a); err != nil {
+		t.Fatalf("newTLSConfig() with a valid ACME CA = %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/events"
+	"VKR_gateway_service/pkg/lifecycle"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// newWSServer serves the gateway with a fake SSO that accepts the tokens
+// "user-1" and "user-2" and returns the URL of /api/ws.
+func newWSServer(t *testing.T, configure func(*config.Config)) (string, *app.App) {
+	t.Helper()
+	sso := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer user-1":
+			io.WriteString(w, `{"user_id": 1}`)
+		case "Bearer user-2":
+			io.WriteString(w, `{"user_id": 2}`)
+		default:
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(sso.Close)
+
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SwaggerEnabled = false
+	cfg.PublicRPCEnabled = false
+	cfg.SSO_HTTP_URL = sso.URL
+	if configure != nil {
+		configure(cfg)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	a := &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, Lifecycle: lifecycle.New(logger), Events: events.NewHub(nil, logger)}
+	s, err := NewHTTPServer(cfg, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.httpServer.Handler)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/ws", a
+}
+
+// dialWS connects with the token passed as the browser does, in the bearer
+// subprotocol.
+func dialWS(t *testing.T, url, token string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, resp, err := websocket.Dial(ctx, url, &websocket.DialOptions{Subprotocols: []string{"bearer", token}})
+	if err == nil {
+		t.Cleanup(func() { conn.CloseNow() })
+	}
+	return conn, resp, err
+}
+
+func publishEvent(t *testing.T, a *app.App, userID, chatID int64, data interface{}) {
+	t.Helper()
+	ev, err := events.NewEvent(events.ChatUpdated, userID, chatID, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Events.Publish(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) events.Event {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ev events.Event
+	if err := json.Unmarshal(msg, &ev); err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func TestWebSocketBearerProtocol(t *testing.T) {
+	url, _ := newWSServer(t, nil)
+
+	for _, token := range []string{"bad-token", ""} {
+		_, resp, err := dialWS(t, url, token)
+		if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("token %q: err = %v, response %v, want 401", token, err, resp)
+		}
+	}
+
+	conn, _, err := dialWS(t, url, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.Subprotocol() != "bearer" {
+		t.Fatalf("subprotocol = %q, want bearer", conn.Subprotocol())
+	}
+}
+
+func TestWebSocketDeliversOnlyOwnEvents(t *testing.T) {
+	url, a := newWSServer(t, nil)
+	first, _, err := dialWS(t, url, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := dialWS(t, url, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _, err := dialWS(t, url, "user-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publishEvent(t, a, 1, 10, nil)
+	// Published last, so a leaked event of user 1 would be read first
+	publishEvent(t, a, 2, 20, nil)
+	for _, conn := range []*websocket.Conn{first, second} {
+		if ev := readEvent(t, conn); ev.UserID != 1 || ev.ChatID != 10 {
+			t.Fatalf("user 1 got %+v", ev)
+		}
+	}
+	if ev := readEvent(t, other); ev.UserID != 2 || ev.ChatID != 20 {
+		t.Fatalf("user 2 got %+v", ev)
+	}
+}
+
+func TestWebSocketMaxConnectionsPerUser(t *testing.T) {
+	url, _ := newWSServer(t, func(cfg *config.Config) { cfg.WebSocketConfig.MaxConnectionsPerUser = 1 })
+	first, _, err := dialWS(t, url, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, resp, err := dialWS(t, url, "user-1"); err == nil || resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("second connection: err = %v, response %v, want 429", err, resp)
+	}
+	if _, _, err := dialWS(t, url, "user-2"); err != nil {
+		t.Fatalf("other user: %v", err)
+	}
+
+	// The slot is freed once the server notices the close
+	first.Close(websocket.StatusNormalClosure, "")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, resp, err := dialWS(t, url, "user-1")
+		if err == nil {
+			break
+		}
+		if resp == nil || resp.StatusCode != http.StatusTooManyRequests || time.Now().After(deadline) {
+			t.Fatalf("connection after close: err = %v, response %v", err, resp)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebSocketSlowClientIsClosed(t *testing.T) {
+	url, a := newWSServer(t, func(cfg *config.Config) { cfg.WebSocketConfig.SendBuffer = 1 })
+	conn, _, err := dialWS(t, url, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadLimit(-1)
+
+	// The client does not read, so the server blocks on writes once the
+	// socket buffers are full and the send buffer overflows
+	data := strings.Repeat("x", 64<<10)
+	for i := int64(1); i <= 256; i++ {
+		publishEvent(t, a, 1, i, data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	received := 0
+	for {
+		_, _, err := conn.Read(ctx)
+		if err == nil {
+			received++
+			continue
+		}
+		if status := websocket.CloseStatus(err); status != websocket.StatusTryAgainLater {
+			t.Fatalf("connection ended with %v after %d events, want close status %d", err, received, websocket.StatusTryAgainLater)
+		}
+		break
+	}
+	if received >= 256 {
+		t.Fatalf("slow client received all %d events", received)
+	}
+}
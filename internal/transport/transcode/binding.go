@@ -0,0 +1,156 @@
+package transcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// bind fills msg from the request: the body first, then path variables, then
+// query parameters for fields the body does not cover. The body size is
+// capped by the BodyLimit middleware.
+func bind(c *gin.Context, rule *Rule, msg protoreflect.Message) error {
+	if rule.BodyAll || rule.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		if len(strings.TrimSpace(string(body))) > 0 {
+			target := msg
+			if rule.Body != nil {
+				target = msg.Mutable(rule.Body).Message()
+			}
+			if err := protojson.Unmarshal(body, target.Interface()); err != nil {
+				return fmt.Errorf("invalid request body: %w", err)
+			}
+		}
+	}
+
+	for name, fields := range rule.PathParams {
+		value := c.Param(name)
+		if name == rule.RestParam {
+			// gin keeps the leading slash of a catch-all parameter
+			value = strings.TrimPrefix(value, "/")
+		}
+		if value == "" {
+			return fmt.Errorf("path parameter %s must not be empty", name)
+		}
+		if err := setField(msg, fields, value); err != nil {
+			return fmt.Errorf("invalid path parameter %s: %w", name, err)
+		}
+	}
+
+	if rule.BodyAll {
+		return nil
+	}
+	return bindQuery(msg, rule, c.Request.URL.Query())
+}
+
+func bindQuery(msg protoreflect.Message, rule *Rule, query url.Values) error {
+	for key, values := range query {
+		fields, err := fieldPath(msg.Descriptor(), key)
+		if err != nil {
+			return fmt.Errorf("unknown query parameter %s", key)
+		}
+		if rule.Body != nil && fields[0] == rule.Body {
+			continue
+		}
+		if isPathField(rule, fields) {
+			continue
+		}
+		for _, v := range values {
+			if err := setField(msg, fields, v); err != nil {
+				return fmt.Errorf("invalid query parameter %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func isPathField(rule *Rule, fields []protoreflect.FieldDescriptor) bool {
+	for _, path := range rule.PathParams {
+		if len(path) != len(fields) {
+			continue
+		}
+		same := true
+		for i := range path {
+			same = same && path[i] == fields[i]
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+// setField parses raw into the scalar field at the end of fields, creating
+// intermediate messages. Repeated fields get raw appended.
+func setField(msg protoreflect.Message, fields []protoreflect.FieldDescriptor, raw string) error {
+	for _, fd := range fields[:len(fields)-1] {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return fmt.Errorf("%s is not a message field", fd.Name())
+		}
+		msg = msg.Mutable(fd).Message()
+	}
+	fd := fields[len(fields)-1]
+	if fd.IsMap() || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		return fmt.Errorf("%s cannot be set from a string", fd.Name())
+	}
+	value, err := parseScalar(fd, raw)
+	if err != nil {
+		return err
+	}
+	if fd.IsList() {
+		msg.Mutable(fd).List().Append(value)
+		return nil
+	}
+	msg.Set(fd, value)
+	return nil
+}
+
+func parseScalar(fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(raw)), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(raw)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(raw, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(raw, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(raw, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(raw, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(raw)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, errors.New("unknown enum value " + raw)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+}
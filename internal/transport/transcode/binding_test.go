@@ -0,0 +1,211 @@
+package transcode
+
+import (
+	pb "VKR_gateway_service/gen/go"
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/auth"
+	"VKR_gateway_service/internal/config"
+	"VKR_gateway_service/internal/domain"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// bindRequest binds a request to rule and returns the message or the error.
+func bindRequest(t *testing.T, rule *Rule, method, target, body string) (*dynamicpb.Message, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	msg := dynamicpb.NewMessage(rule.Input)
+	var bindErr error
+	r.Handle(rule.Method, rule.Route, func(c *gin.Context) { bindErr = bind(c, rule, msg) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("%s %s = %d, route %s not matched", method, target, w.Code, rule.Route)
+	}
+	return msg, bindErr
+}
+
+func TestBind(t *testing.T) {
+	bodyField := &annotations.HttpRule{Pattern: &annotations.HttpRule_Put{Put: "/api/rpc/items/{Chat_id}/filter"}, Body: "filter"}
+
+	tests := []struct {
+		name   string
+		rule   *annotations.HttpRule
+		method string
+		target string
+		body   string
+		// want is the bound message in protojson
+		want string
+	}{
+		{"path variable", get("/api/rpc/items/{Chat_id}"), http.MethodGet, "/api/rpc/items/7", "",
+			`{"Chat_id":"7"}`},
+		{"nested path variable", get("/api/rpc/items/{Chat_id}/filters/{filter.State}"), http.MethodGet, "/api/rpc/items/7/filters/open?filter.Year=2020", "",
+			`{"Chat_id":"7","filter":{"State":"open","Year":"2020"}}`},
+		{"multi-segment path variable", get("/api/rpc/papers/{ID=**}"), http.MethodGet, "/api/rpc/papers/10.1000/xyz.1/v2", "",
+			`{"ID":"10.1000/xyz.1/v2"}`},
+		{"escaped slash", get("/api/rpc/papers/{ID=**}"), http.MethodGet, "/api/rpc/papers/10.1000%2Fxyz", "",
+			`{"ID":"10.1000/xyz"}`},
+		{"repeated query", get("/api/rpc/items"), http.MethodGet, "/api/rpc/items?Tags=a&Tags=b&Tags=c", "",
+			`{"Tags":["a","b","c"]}`},
+		{"enum query", get("/api/rpc/items"), http.MethodGet, "/api/rpc/items?kind=BOOK&Kinds=ARTICLE&Kinds=2&Open=true", "",
+			`{"kind":"BOOK","Kinds":["ARTICLE","BOOK"],"Open":true}`},
+		{"body star", post("/api/rpc/items/{Chat_id}", "*"), http.MethodPost, "/api/rpc/items/7?Tags=ignored", `{"ID":"x","Chat_id":"9","filter":{"State":"s"}}`,
+			`{"Chat_id":"7","ID":"x","filter":{"State":"s"}}`},
+		{"empty body", post("/api/rpc/items/{Chat_id}", "*"), http.MethodPost, "/api/rpc/items/7", " ",
+			`{"Chat_id":"7"}`},
+		{"body field", bodyField, http.MethodPut, "/api/rpc/items/7/filter?ID=q&filter.State=ignored", `{"State":"s","Year":"2021"}`,
+			`{"Chat_id":"7","ID":"q","filter":{"State":"s","Year":"2021"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := testRule(t, tt.rule)
+			got, err := bindRequest(t, rule, tt.method, tt.target, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := dynamicpb.NewMessage(rule.Input)
+			if err := protojson.Unmarshal([]byte(tt.want), want); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, want) {
+				t.Fatalf("bound %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBindRejectsBadValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   *annotations.HttpRule
+		method string
+		target string
+		body   string
+		want   string
+	}{
+		{"non-numeric path variable", get("/api/rpc/items/{Chat_id}"), http.MethodGet, "/api/rpc/items/abc", "", "invalid path parameter Chat_id"},
+		{"empty multi-segment variable", get("/api/rpc/papers/{ID=**}"), http.MethodGet, "/api/rpc/papers/", "", "path parameter ID must not be empty"},
+		{"unknown query parameter", get("/api/rpc/items"), http.MethodGet, "/api/rpc/items?color=red", "", "unknown query parameter color"},
+		{"unknown enum value", get("/api/rpc/items"), http.MethodGet, "/api/rpc/items?Kinds=BOOK&Kinds=NOPE", "", "unknown enum value NOPE"},
+		{"bad bool", get("/api/rpc/items"), http.MethodGet, "/api/rpc/items?Open=maybe", "", "invalid query parameter Open"},
+		{"message query parameter", get("/api/rpc/items"), http.MethodGet, "/api/rpc/items?filter=x", "", "filter cannot be set from a string"},
+		{"invalid body", post("/api/rpc/items", "*"), http.MethodPost, "/api/rpc/items", `{"Chat_id":`, "invalid request body"},
+		{"unknown body field", post("/api/rpc/items", "filter"), http.MethodPost, "/api/rpc/items", `{"Color":"red"}`, "invalid request body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bindRequest(t, testRule(t, tt.rule), tt.method, tt.target, tt.body)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("bind() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// paperAI serves GetPaper for the papers in papers.
+type paperAI struct {
+	pb.SemanticServiceClient
+
+	papers map[string]*pb.PaperDetail
+	calls  []string
+}
+
+func (f *paperAI) GetPaper(_ context.Context, in *pb.PaperReq, _ ...grpc.CallOption) (*pb.PaperDetail, error) {
+	f.calls = append(f.calls, in.GetID())
+	if p, ok := f.papers[in.GetID()]; ok {
+		return p, nil
+	}
+	return nil, status.Error(codes.NotFound, "paper not found")
+}
+
+func newTranscodeRouter(t *testing.T, ai pb.SemanticServiceClient) *gin.Engine {
+	t.Helper()
+	cfg, err := config.Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	a := &app.App{ConfigStore: config.NewStore("", cfg), Logger: logger, AI: ai}
+
+	rules, err := Rules(pb.File_service_proto.Services().ByName("SemanticService"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		p := &auth.Principal{UserID: 1, Roles: []domain.Role{domain.RoleUser}}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+	})
+	if err := Register(r, a, rules); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestTranscodedGetPaperTakesDOI(t *testing.T) {
+	ai := &paperAI{papers: map[string]*pb.PaperDetail{"10.1000/xyz.1": {ID: "10.1000/xyz.1", Title: "Paper", Year: 2020}}}
+	r := newTranscodeRouter(t, ai)
+
+	tests := []struct {
+		name   string
+		target string
+		code   int
+	}{
+		{"doi", "/api/rpc/papers/10.1000/xyz.1", http.StatusOK},
+		{"missing paper", "/api/rpc/papers/10.1000/missing", http.StatusNotFound},
+		// No ID reaches GetPaper's own validation
+		{"empty id", "/api/rpc/papers/", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if w.Code != tt.code {
+				t.Fatalf("GET %s = %d, want %d: %s", tt.target, w.Code, tt.code, w.Body)
+			}
+		})
+	}
+	if len(ai.calls) != 2 || ai.calls[0] != "10.1000/xyz.1" {
+		t.Fatalf("GetPaper called with %v", ai.calls)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/rpc/papers/10.1000/xyz.1", nil))
+	var paper map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &paper); err != nil {
+		t.Fatal(err)
+	}
+	if paper["ID"] != "10.1000/xyz.1" || paper["Year"] != "2020" {
+		t.Fatalf("response = %s", w.Body)
+	}
+}
+
+func TestTranscodedBadValueIs400(t *testing.T) {
+	ai := &paperAI{}
+	r := newTranscodeRouter(t, ai)
+	for _, target := range []string{"/api/rpc/chats/abc/history", "/api/rpc/authors?query=a&unknown=1"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "parameter") {
+			t.Fatalf("GET %s = %d: %s", target, w.Code, w.Body)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package transcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swaggo/swag"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const errorDefinition = "#/definitions/presenters.ErrorResponse"
+
+// OpenAPI describes rules as Swagger 2.0 paths relative to basePath, the
+// same format swag generates, together with the definitions of every
+// message they use.
+func OpenAPI(rules []Rule, basePath string) (map[string]map[string]interface{}, map[string]interface{}, error) {
+	paths := map[string]map[string]interface{}{}
+	defs := map[string]interface{}{}
+	for i := range rules {
+		rule := &rules[i]
+		path := strings.TrimSuffix(basePath, "/")
+		if !strings.HasPrefix(rule.Template, path+"/") {
+			return nil, nil, fmt.Errorf("%s: path %s is outside of base path %s", rule.Procedure, rule.Template, basePath)
+		}
+		path = strings.TrimPrefix(rule.Template, path)
+		for name := range rule.PathParams {
+			path = strings.Replace(path, "{"+name+"=**}", "{"+name+"}", 1)
+			path = strings.Replace(path, "{"+name+"=*}", "{"+name+"}", 1)
+		}
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+		paths[path][strings.ToLower(rule.Method)] = operation(rule, defs)
+	}
+	return paths, defs, nil
+}
+
+// MergeSwagger adds the transcoded routes to spec so they are served next to
+// the hand-documented REST API. It must be called after the host and base
+// path of spec are set.
+func MergeSwagger(spec *swag.Spec, rules []Rule) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(spec.ReadDoc()), &doc); err != nil {
+		return fmt.Errorf("failed to parse swagger spec: %w", err)
+	}
+	paths, defs, err := OpenAPI(rules, spec.BasePath)
+	if err != nil {
+		return err
+	}
+
+	docPaths, _ := doc["paths"].(map[string]interface{})
+	if docPaths == nil {
+		docPaths = map[string]interface{}{}
+	}
+	for path, ops := range paths {
+		existing, _ := docPaths[path].(map[string]interface{})
+		if existing == nil {
+			existing = map[string]interface{}{}
+		}
+		for method, op := range ops {
+			if _, ok := existing[method]; ok {
+				return fmt.Errorf("%s %s is already documented", strings.ToUpper(method), path)
+			}
+			existing[method] = op
+		}
+		docPaths[path] = existing
+	}
+	doc["paths"] = docPaths
+
+	docDefs, _ := doc["definitions"].(map[string]interface{})
+	if docDefs == nil {
+		docDefs = map[string]interface{}{}
+	}
+	for name, def := range defs {
+		docDefs[name] = def
+	}
+	doc["definitions"] = docDefs
+
+	merged, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return err
+	}
+	// ReadDoc renders SwaggerTemplate as a text/template
+	spec.SwaggerTemplate = strings.ReplaceAll(string(merged), "{{", `{{"{{"}}`)
+	return nil
+}
+
+func operation(rule *Rule, defs map[string]interface{}) map[string]interface{} {
+	name := rule.Procedure[strings.LastIndex(rule.Procedure, "/")+1:]
+	var params []interface{}
+
+	names := make([]string, 0, len(rule.PathParams))
+	for name := range rule.PathParams {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.Index(rule.Template, "{"+names[i]) < strings.Index(rule.Template, "{"+names[j])
+	})
+	for _, name := range names {
+		fields := rule.PathParams[name]
+		param := scalarParam(fields[len(fields)-1])
+		param["name"] = name
+		param["in"] = "path"
+		param["required"] = true
+		if name == rule.RestParam {
+			param["description"] = "Rest of the path, may contain /"
+		}
+		params = append(params, param)
+	}
+
+	switch {
+	case rule.BodyAll:
+		params = append(params, bodyParam(rule.Input, defs))
+	case rule.Body != nil:
+		params = append(params, bodyParam(rule.Body.Message(), defs))
+	}
+
+	if !rule.BodyAll {
+		fields := rule.Input.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if fd == rule.Body || fd.Kind() == protoreflect.MessageKind || fd.IsMap() || isPathField(rule, []protoreflect.FieldDescriptor{fd}) {
+				continue
+			}
+			param := scalarParam(fd)
+			if fd.IsList() {
+				param = map[string]interface{}{"type": "array", "items": param, "collectionFormat": "multi"}
+			}
+			param["name"] = fd.JSONName()
+			param["in"] = "query"
+			params = append(params, param)
+		}
+	}
+
+	output := rule.Output
+	if rule.ResponseBody != nil {
+		output = rule.ResponseBody.Message()
+	}
+	errorResponse := func(description string) map[string]interface{} {
+		return map[string]interface{}{"description": description, "schema": map[string]interface{}{"$ref": errorDefinition}}
+	}
+	op := map[string]interface{}{
+		"description": "Transcoded " + rule.Procedure,
+		"summary":     name,
+		"operationId": strings.ReplaceAll(strings.TrimPrefix(rule.Procedure, "/"), "/", "_"),
+		"tags":        []string{"rpc"},
+		"produces":    []string{"application/json"},
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{"description": "OK", "schema": messageRef(output, defs)},
+			"400": errorResponse("Bad Request"),
+			"401": errorResponse("Unauthorized"),
+			"403": errorResponse("Forbidden"),
+			"500": errorResponse("Internal Server Error"),
+		},
+	}
+	if rule.BodyAll || rule.Body != nil {
+		op["consumes"] = []string{"application/json"}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	return op
+}
+
+func bodyParam(md protoreflect.MessageDescriptor, defs map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     "body",
+		"in":       "body",
+		"required": true,
+		"schema":   messageRef(md, defs),
+	}
+}
+
+// scalarParam is the type of a path or query parameter.
+func scalarParam(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	default:
+		return valueSchema(fd, nil)
+	}
+}
+
+// messageRef returns a reference to the definition of md, adding it and the
+// messages it uses to defs.
+func messageRef(md protoreflect.MessageDescriptor, defs map[string]interface{}) map[string]interface{} {
+	name := string(md.FullName())
+	ref := map[string]interface{}{"$ref": "#/definitions/" + name}
+	if _, ok := defs[name]; ok {
+		return ref
+	}
+	properties := map[string]interface{}{}
+	def := map[string]interface{}{"type": "object", "properties": properties}
+	defs[name] = def
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		properties[fd.JSONName()] = fieldSchema(fd, defs)
+	}
+	return ref
+}
+
+// fieldSchema follows the protojson mapping, e.g. 64-bit integers are strings.
+func fieldSchema(fd protoreflect.FieldDescriptor, defs map[string]interface{}) map[string]interface{} {
+	if fd.IsMap() {
+		return map[string]interface{}{"type": "object", "additionalProperties": valueSchema(fd.MapValue(), defs)}
+	}
+	if fd.IsList() {
+		return map[string]interface{}{"type": "array", "items": valueSchema(fd, defs)}
+	}
+	return valueSchema(fd, defs)
+}
+
+func valueSchema(fd protoreflect.FieldDescriptor, defs map[string]interface{}) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		var names []string
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if defs == nil {
+			return map[string]interface{}{"type": "object"}
+		}
+		return messageRef(fd.Message(), defs)
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
@@ -0,0 +1,155 @@
+package transcode
+
+import (
+	"VKR_gateway_service/docs"
+	pb "VKR_gateway_service/gen/go"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/swaggo/swag"
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func TestOpenAPI(t *testing.T) {
+	rules, err := Rules(testService(t, map[string]*annotations.HttpRule{
+		"Get":    get("/api/rpc/items/{Chat_id}/filters/{filter.State}"),
+		"Paper":  get("/api/rpc/papers/{ID=**}"),
+		"Create": post("/api/rpc/items/{Chat_id=*}", "*"),
+		"Filter": post("/api/rpc/items/{Chat_id}/filter", "filter"),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, defs, err := OpenAPI(rules, "/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := func(path, method string) map[string]map[string]interface{} {
+		t.Helper()
+		op, ok := paths[path][method].(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s %s not described, paths: %v", method, path, paths)
+		}
+		out := map[string]map[string]interface{}{}
+		list, _ := op["parameters"].([]interface{})
+		for _, p := range list {
+			param := p.(map[string]interface{})
+			out[param["in"].(string)+" "+param["name"].(string)] = param
+		}
+		return out
+	}
+
+	items := params("/rpc/items/{Chat_id}/filters/{filter.State}", "get")
+	if items["path Chat_id"]["format"] != "int64" || items["path filter.State"]["type"] != "string" {
+		t.Fatalf("path parameters = %v", items)
+	}
+	if tags := items["query Tags"]; tags["type"] != "array" || tags["collectionFormat"] != "multi" {
+		t.Fatalf("repeated query parameter = %v", tags)
+	}
+	if kind := items["query kind"]; kind["type"] != "string" || len(kind["enum"].([]string)) != 3 {
+		t.Fatalf("enum query parameter = %v", kind)
+	}
+	if _, ok := items["query filter"]; ok {
+		t.Fatal("message field described as a query parameter")
+	}
+	if _, ok := items["query ChatId"]; ok {
+		t.Fatal("path field described as a query parameter")
+	}
+
+	paper := params("/rpc/papers/{ID}", "get")
+	if paper["path ID"]["description"] != "Rest of the path, may contain /" {
+		t.Fatalf("multi-segment parameter = %v", paper["path ID"])
+	}
+
+	create := params("/rpc/items/{Chat_id}", "post")
+	if ref := create["body body"]["schema"].(map[string]interface{})["$ref"]; ref != "#/definitions/transcode.test.ItemReq" || len(create) != 2 {
+		t.Fatalf("body * parameters = %v", create)
+	}
+	filter := params("/rpc/items/{Chat_id}/filter", "post")
+	if ref := filter["body body"]["schema"].(map[string]interface{})["$ref"]; ref != "#/definitions/transcode.test.Filter" {
+		t.Fatalf("body field parameters = %v", filter)
+	}
+	if _, ok := filter["query ID"]; !ok {
+		t.Fatalf("fields outside the body field are not query parameters: %v", filter)
+	}
+
+	for _, name := range []string{"transcode.test.ItemReq", "transcode.test.ItemResp", "transcode.test.Filter"} {
+		if _, ok := defs[name]; !ok {
+			t.Fatalf("definition %s missing: %v", name, defs)
+		}
+	}
+
+	if _, _, err := OpenAPI(rules, "/other"); err == nil || !strings.Contains(err.Error(), "outside of base path") {
+		t.Fatalf("OpenAPI() outside the base path = %v", err)
+	}
+}
+
+func TestMergeSwagger(t *testing.T) {
+	rules, err := Rules(pb.File_service_proto.Services().ByName("SemanticService"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := *docs.SwaggerInfo
+	spec.BasePath = "/api"
+	if err := MergeSwagger(&spec, rules); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Paths       map[string]map[string]interface{} `json:"paths"`
+		Definitions map[string]interface{}            `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(spec.ReadDoc()), &doc); err != nil {
+		t.Fatal(err)
+	}
+	// Hand-written and generated paths are served side by side
+	for path, method := range map[string]string{
+		"/chats/{chat_id}/history":        "get",
+		"/ai/papers/import":               "post",
+		"/rpc/chats/{Chat_id}/history":    "get",
+		"/rpc/papers/{ID}":                "get",
+		"/rpc/papers":                     "post",
+		"/rpc/authors/{Author_ID}/papers": "get",
+	} {
+		if _, ok := doc.Paths[path][method]; !ok {
+			t.Fatalf("%s %s missing from the merged spec", method, path)
+		}
+	}
+	for _, name := range []string{"presenters.ErrorResponse", "semantic.PaperDetail"} {
+		if _, ok := doc.Definitions[name]; !ok {
+			t.Fatalf("definition %s missing from the merged spec", name)
+		}
+	}
+
+	// A route documented twice is refused
+	if err := MergeSwagger(&spec, rules); err == nil || !strings.Contains(err.Error(), "already documented") {
+		t.Fatalf("second MergeSwagger() = %v", err)
+	}
+}
+
+func TestMergeSwaggerKeepsTemplateSyntaxLiteral(t *testing.T) {
+	spec := &swag.Spec{
+		BasePath:         "/api",
+		SwaggerTemplate:  `{"swagger":"2.0","info":{"description":"{{.Description}}"},"paths":{}}`,
+		Description:      "Braces {{ stay }}",
+		LeftDelim:        "{{",
+		RightDelim:       "}}",
+		InfoInstanceName: "transcode-test",
+	}
+	rules, err := Rules(testService(t, map[string]*annotations.HttpRule{"Get": get("/api/rpc/items/{Chat_id}")}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeSwagger(spec, rules); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(spec.ReadDoc()), &doc); err != nil {
+		t.Fatalf("merged spec is not JSON: %v\n%s", err, spec.ReadDoc())
+	}
+	if _, ok := doc["paths"].(map[string]interface{})["/rpc/items/{Chat_id}"]; !ok {
+		t.Fatalf("merged paths = %v", doc["paths"])
+	}
+}
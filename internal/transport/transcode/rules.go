@@ -0,0 +1,183 @@
+// Package transcode serves REST routes declared with google.api.http
+// annotations in the proto. Requests are bound to the input message from the
+// path, query string and body, the procedure is invoked in process and the
+// result is written with protojson.
+package transcode
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Rule is one HTTP binding of a procedure.
+type Rule struct {
+	Procedure string
+	Method    string
+	// Template is the annotated path, e.g. /api/rpc/chats/{Chat_id}.
+	Template string
+	// Route is Template in gin syntax, e.g. /api/rpc/chats/:Chat_id.
+	Route string
+	// PathParams maps gin parameter names to field paths of the input message.
+	PathParams map[string][]protoreflect.FieldDescriptor
+	// RestParam is the {field=**} variable matching the rest of the path,
+	// slashes included; it must be the last segment.
+	RestParam string
+	// Body is the message field the request body is bound to (body: "field").
+	// BodyAll binds the body to the whole input message (body: "*").
+	Body         protoreflect.FieldDescriptor
+	BodyAll      bool
+	ResponseBody protoreflect.FieldDescriptor
+	Input        protoreflect.MessageDescriptor
+	Output       protoreflect.MessageDescriptor
+}
+
+// Rules reads the HTTP annotations of every method of service, including
+// additional bindings. Methods without annotations are skipped.
+func Rules(service protoreflect.ServiceDescriptor) ([]Rule, error) {
+	var rules []Rule
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		m := methods.Get(i)
+		if m.IsStreamingClient() || m.IsStreamingServer() {
+			continue
+		}
+		httpRule, ok := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || httpRule == nil {
+			continue
+		}
+		procedure := "/" + string(service.FullName()) + "/" + string(m.Name())
+		bindings := append([]*annotations.HttpRule{httpRule}, httpRule.GetAdditionalBindings()...)
+		for _, b := range bindings {
+			rule, err := newRule(procedure, m, b)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", m.FullName(), err)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func newRule(procedure string, m protoreflect.MethodDescriptor, b *annotations.HttpRule) (Rule, error) {
+	rule := Rule{
+		Procedure:  procedure,
+		Input:      m.Input(),
+		Output:     m.Output(),
+		PathParams: map[string][]protoreflect.FieldDescriptor{},
+	}
+	switch p := b.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		rule.Method, rule.Template = http.MethodGet, p.Get
+	case *annotations.HttpRule_Post:
+		rule.Method, rule.Template = http.MethodPost, p.Post
+	case *annotations.HttpRule_Put:
+		rule.Method, rule.Template = http.MethodPut, p.Put
+	case *annotations.HttpRule_Patch:
+		rule.Method, rule.Template = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Delete:
+		rule.Method, rule.Template = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Custom:
+		rule.Method, rule.Template = strings.ToUpper(p.Custom.GetKind()), p.Custom.GetPath()
+	default:
+		return rule, fmt.Errorf("http rule has no pattern")
+	}
+	if !strings.HasPrefix(rule.Template, "/") {
+		return rule, fmt.Errorf("path %q must start with /", rule.Template)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(rule.Template, "/"), "/")
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, "{") {
+			if strings.ContainsAny(seg, "{}*:") {
+				return rule, fmt.Errorf("path %q: unsupported segment %q", rule.Template, seg)
+			}
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "}")
+		// {field=*} is the same as {field}, {field=**} takes the rest of the
+		// path (e.g. DOIs); other sub-patterns are not supported
+		rest := strings.HasSuffix(name, "=**")
+		name = strings.TrimSuffix(strings.TrimSuffix(name, "=**"), "=*")
+		if !strings.HasSuffix(seg, "}") || strings.ContainsAny(name, "{}=*/") {
+			return rule, fmt.Errorf("path %q: unsupported variable %q", rule.Template, seg)
+		}
+		if rest && i != len(segments)-1 {
+			return rule, fmt.Errorf("path %q: %s must be the last segment", rule.Template, seg)
+		}
+		fields, err := fieldPath(rule.Input, name)
+		if err != nil {
+			return rule, fmt.Errorf("path %q: %w", rule.Template, err)
+		}
+		if last := fields[len(fields)-1]; last.Kind() == protoreflect.MessageKind || last.IsList() || last.IsMap() {
+			return rule, fmt.Errorf("path %q: %s must be a scalar field", rule.Template, name)
+		}
+		rule.PathParams[name] = fields
+		segments[i] = ":" + name
+		if rest {
+			rule.RestParam = name
+			segments[i] = "*" + name
+		}
+	}
+	rule.Route = "/" + strings.Join(segments, "/")
+
+	switch body := b.GetBody(); body {
+	case "":
+	case "*":
+		rule.BodyAll = true
+	default:
+		fields, err := fieldPath(rule.Input, body)
+		if err != nil || len(fields) != 1 || fields[0].Kind() != protoreflect.MessageKind || fields[0].IsList() || fields[0].IsMap() {
+			return rule, fmt.Errorf("body %q must be a top-level message field of %s", body, rule.Input.FullName())
+		}
+		rule.Body = fields[0]
+	}
+	if responseBody := b.GetResponseBody(); responseBody != "" {
+		fields, err := fieldPath(rule.Output, responseBody)
+		if err != nil || len(fields) != 1 || fields[0].Kind() != protoreflect.MessageKind || fields[0].IsList() || fields[0].IsMap() {
+			return rule, fmt.Errorf("response_body %q must be a top-level message field of %s", responseBody, rule.Output.FullName())
+		}
+		rule.ResponseBody = fields[0]
+	}
+	return rule, nil
+}
+
+// fieldPath resolves a dotted field path such as "chat.Chat_id".
+func fieldPath(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	var fields []protoreflect.FieldDescriptor
+	for _, name := range strings.Split(path, ".") {
+		if md == nil {
+			return nil, fmt.Errorf("field %q is not a message", path)
+		}
+		fd := findField(md, name)
+		if fd == nil {
+			return nil, fmt.Errorf("field %q not found in %s", name, md.FullName())
+		}
+		fields = append(fields, fd)
+		md = fd.Message()
+	}
+	return fields, nil
+}
+
+// findField looks a field up by proto name, then JSON name, then either name
+// ignoring case, so ?query= works for a field called Query.
+func findField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	if fd := fields.ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	if fd := fields.ByJSONName(name); fd != nil {
+		return fd
+	}
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if strings.EqualFold(string(fd.Name()), name) || strings.EqualFold(fd.JSONName(), name) {
+			return fd
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package transcode
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testService builds the transcode.test.Items service:
+//
+//	enum Kind { KIND_UNSPECIFIED = 0; ARTICLE = 1; BOOK = 2; }
+//	message Filter { string State = 1; int64 Year = 2; }
+//	message ItemReq {
+//	    int64 Chat_id = 1; string ID = 2; Filter filter = 3;
+//	    repeated string Tags = 4; repeated Kind Kinds = 5; Kind kind = 6; bool Open = 7;
+//	}
+//	message ItemResp { Filter filter = 1; }
+//
+// with one method per http rule in bindings, named after the map key.
+func testService(t *testing.T, bindings map[string]*annotations.HttpRule) protoreflect.ServiceDescriptor {
+	t.Helper()
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   typ.Enum(),
+			Label:  label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	service := &descriptorpb.ServiceDescriptorProto{Name: proto.String("Items")}
+	for name, rule := range bindings {
+		opts := &descriptorpb.MethodOptions{}
+		proto.SetExtension(opts, annotations.E_Http, rule)
+		service.Method = append(service.Method, &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(".transcode.test.ItemReq"),
+			OutputType: proto.String(".transcode.test.ItemResp"),
+			Options:    opts,
+		})
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("transcode_test.proto"),
+		Package: proto.String("transcode.test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Kind"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("ARTICLE"), Number: proto.Int32(1)},
+				{Name: proto.String("BOOK"), Number: proto.Int32(2)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Filter"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("State", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("Year", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				},
+			},
+			{
+				Name: proto.String("ItemReq"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("Chat_id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					field("ID", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("filter", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".transcode.test.Filter"),
+					field("Tags", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, ""),
+					field("Kinds", 5, descriptorpb.FieldDescriptorProto_TYPE_ENUM, repeated, ".transcode.test.Kind"),
+					field("kind", 6, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".transcode.test.Kind"),
+					field("Open", 7, descriptorpb.FieldDescriptorProto_TYPE_BOOL, optional, ""),
+				},
+			},
+			{
+				Name: proto.String("ItemResp"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("filter", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".transcode.test.Filter"),
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{service},
+	}
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Services().Get(0)
+}
+
+// testRule returns the rule of the single-binding method name.
+func testRule(t *testing.T, rule *annotations.HttpRule) *Rule {
+	t.Helper()
+	rules, err := Rules(testService(t, map[string]*annotations.HttpRule{"Method": rule}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &rules[0]
+}
+
+func get(path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: path}}
+}
+
+func post(path, body string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: path}, Body: body}
+}
+
+func fieldNames(fields []protoreflect.FieldDescriptor) string {
+	names := make([]string, len(fields))
+	for i, fd := range fields {
+		names[i] = string(fd.Name())
+	}
+	return strings.Join(names, ".")
+}
+
+func TestRules(t *testing.T) {
+	withResponseBody := post("/api/rpc/items/{Chat_id}", "filter")
+	withResponseBody.ResponseBody = "filter"
+	withAdditional := get("/api/rpc/items/{Chat_id}")
+	withAdditional.AdditionalBindings = []*annotations.HttpRule{post("/api/rpc/items/{Chat_id}/copy", "*")}
+
+	tests := []struct {
+		name       string
+		rule       *annotations.HttpRule
+		method     string
+		route      string
+		params     map[string]string
+		restParam  string
+		bodyAll    bool
+		body       string
+		respBody   string
+		additional string
+	}{
+		{"no variables", get("/api/rpc/items"), http.MethodGet, "/api/rpc/items", map[string]string{}, "", false, "", "", ""},
+		{"path variable", get("/api/rpc/items/{Chat_id}"), http.MethodGet, "/api/rpc/items/:Chat_id", map[string]string{"Chat_id": "Chat_id"}, "", false, "", "", ""},
+		{"single segment pattern", get("/api/rpc/items/{Chat_id=*}"), http.MethodGet, "/api/rpc/items/:Chat_id", map[string]string{"Chat_id": "Chat_id"}, "", false, "", "", ""},
+		{"nested variable", get("/api/rpc/items/{Chat_id}/filters/{filter.State}"), http.MethodGet, "/api/rpc/items/:Chat_id/filters/:filter.State",
+			map[string]string{"Chat_id": "Chat_id", "filter.State": "filter.State"}, "", false, "", "", ""},
+		{"multi-segment variable", get("/api/rpc/papers/{ID=**}"), http.MethodGet, "/api/rpc/papers/*ID", map[string]string{"ID": "ID"}, "ID", false, "", "", ""},
+		{"body star", post("/api/rpc/items/{Chat_id}", "*"), http.MethodPost, "/api/rpc/items/:Chat_id", map[string]string{"Chat_id": "Chat_id"}, "", true, "", "", ""},
+		{"body field", withResponseBody, http.MethodPost, "/api/rpc/items/:Chat_id", map[string]string{"Chat_id": "Chat_id"}, "", false, "filter", "filter", ""},
+		{"json name and case", &annotations.HttpRule{Pattern: &annotations.HttpRule_Delete{Delete: "/api/rpc/items/{chatId}"}}, http.MethodDelete, "/api/rpc/items/:chatId",
+			map[string]string{"chatId": "Chat_id"}, "", false, "", "", ""},
+		{"custom method", &annotations.HttpRule{Pattern: &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: "head", Path: "/api/rpc/items"}}}, http.MethodHead, "/api/rpc/items",
+			map[string]string{}, "", false, "", "", ""},
+		{"additional binding", withAdditional, http.MethodGet, "/api/rpc/items/:Chat_id", map[string]string{"Chat_id": "Chat_id"}, "", false, "", "", "/api/rpc/items/:Chat_id/copy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Rules(testService(t, map[string]*annotations.HttpRule{"Method": tt.rule}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := rules[0]
+			if rule.Procedure != "/transcode.test.Items/Method" || rule.Method != tt.method || rule.Route != tt.route {
+				t.Fatalf("rule = %s %s %s, want %s %s", rule.Procedure, rule.Method, rule.Route, tt.method, tt.route)
+			}
+			if len(rule.PathParams) != len(tt.params) {
+				t.Fatalf("path params = %v, want %v", rule.PathParams, tt.params)
+			}
+			for name, path := range tt.params {
+				if got := fieldNames(rule.PathParams[name]); got != path {
+					t.Fatalf("path param %s = %q, want %q", name, got, path)
+				}
+			}
+			if rule.RestParam != tt.restParam || rule.BodyAll != tt.bodyAll {
+				t.Fatalf("RestParam = %q, BodyAll = %v", rule.RestParam, rule.BodyAll)
+			}
+			if (rule.Body == nil && tt.body != "") || (rule.Body != nil && string(rule.Body.Name()) != tt.body) {
+				t.Fatalf("Body = %v, want %q", rule.Body, tt.body)
+			}
+			if (rule.ResponseBody == nil && tt.respBody != "") || (rule.ResponseBody != nil && string(rule.ResponseBody.Name()) != tt.respBody) {
+				t.Fatalf("ResponseBody = %v, want %q", rule.ResponseBody, tt.respBody)
+			}
+			if tt.additional == "" && len(rules) != 1 || tt.additional != "" && (len(rules) != 2 || rules[1].Route != tt.additional || !rules[1].BodyAll) {
+				t.Fatalf("got %d rules, want additional binding %q", len(rules), tt.additional)
+			}
+		})
+	}
+}
+
+func TestRulesRejectInvalidAnnotations(t *testing.T) {
+	withResponseBody := get("/api/rpc/items")
+	withResponseBody.ResponseBody = "missing"
+
+	tests := []struct {
+		name string
+		rule *annotations.HttpRule
+		want string
+	}{
+		{"no pattern", &annotations.HttpRule{}, "http rule has no pattern"},
+		{"relative path", get("api/rpc/items"), "must start with /"},
+		{"unknown field", get("/api/rpc/items/{Missing}"), `field "Missing" not found`},
+		{"message variable", get("/api/rpc/items/{filter}"), "filter must be a scalar field"},
+		{"repeated variable", get("/api/rpc/items/{Tags}"), "Tags must be a scalar field"},
+		{"rest not last", get("/api/rpc/papers/{ID=**}/history"), "{ID=**} must be the last segment"},
+		{"sub-pattern", get("/api/rpc/papers/{ID=papers/*}"), "unsupported variable"},
+		{"verb", get("/api/rpc/items:search"), "unsupported segment"},
+		{"unknown body", post("/api/rpc/items", "missing"), `body "missing" must be a top-level message field`},
+		{"scalar body", post("/api/rpc/items", "ID"), `body "ID" must be a top-level message field`},
+		{"unknown response body", withResponseBody, `response_body "missing" must be a top-level message field`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Rules(testService(t, map[string]*annotations.HttpRule{"Method": tt.rule}))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Rules() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package transcode
+
+import (
+	"VKR_gateway_service/internal/app"
+	"VKR_gateway_service/internal/transport/connectrpc"
+	"VKR_gateway_service/internal/transport/http/middlewares"
+	"VKR_gateway_service/internal/transport/http/presenters"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var marshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// Register adds a route for every rule. r must authenticate the caller.
+func Register(r gin.IRoutes, a *app.App, rules []Rule) error {
+	invoker := connectrpc.NewInvoker(a)
+	for i := range rules {
+		rule := &rules[i]
+		input, err := protoregistry.GlobalTypes.FindMessageByName(rule.Input.FullName())
+		if err != nil {
+			return err
+		}
+		r.Handle(rule.Method, rule.Route, func(ctx *gin.Context) { handle(ctx, a, invoker, rule, input) })
+	}
+	return nil
+}
+
+func handle(ctx *gin.Context, a *app.App, invoker *connectrpc.Invoker, rule *Rule, input protoreflect.MessageType) {
+	middlewares.AuditAction(ctx, "RPC "+rule.Procedure, "rpc")
+
+	req := input.New()
+	if err := bind(ctx, rule, req); err != nil {
+		ctx.JSON(http.StatusBadRequest, presenters.Error(err))
+		return
+	}
+
+	principal, _ := middlewares.PrincipalFrom(ctx)
+	resp, err := invoker.Invoke(ctx.Request.Context(), rule.Procedure, principal, req.Interface())
+	if err != nil {
+		status := connectrpc.HTTPStatus(err)
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			err = errors.New(connectErr.Message())
+		}
+		a.Logger.WithError(err).WithField("procedure", rule.Procedure).Warn("Transcoded RPC failed")
+		ctx.JSON(status, presenters.Error(err))
+		return
+	}
+
+	var out proto.Message = resp
+	if rule.ResponseBody != nil {
+		out = resp.ProtoReflect().Get(rule.ResponseBody).Message().Interface()
+	}
+	body, err := marshalOptions.Marshal(out)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, presenters.Error(err))
+		return
+	}
+	ctx.Data(http.StatusOK, "application/json", body)
+}
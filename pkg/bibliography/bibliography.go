@@ -0,0 +1,123 @@
+package bibliography
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatBibTeX Format = "bibtex"
+	FormatRIS    Format = "ris"
+)
+
+// Entry is a single bibliography record normalized from BibTeX or RIS.
+type Entry struct {
+	Line     int
+	Key      string
+	Title    string
+	Abstract string
+	Year     int
+	DOI      string
+	URL      string
+}
+
+// ParseError describes an entry which could not be parsed.
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ParseFormat converts user input (format name or file extension) to a Format.
+func ParseFormat(raw string) (Format, bool) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), ".")) {
+	case "bibtex", "bib":
+		return FormatBibTeX, true
+	case "ris":
+		return FormatRIS, true
+	default:
+		return "", false
+	}
+}
+
+// DetectFormat guesses the format by file name first and by content otherwise.
+func DetectFormat(filename string, data []byte) (Format, bool) {
+	if f, ok := ParseFormat(filepath.Ext(filename)); ok {
+		return f, true
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			return FormatBibTeX, true
+		}
+		if risLine.MatchString(line) {
+			return FormatRIS, true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// Parse reads all entries from r. Broken entries are skipped and reported in errs
+// so that one bad record does not prevent the rest of the file from importing.
+func Parse(format Format, r io.Reader) (entries []Entry, errs []ParseError, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch format {
+	case FormatBibTeX:
+		entries, errs = parseBibTeX(string(data))
+	case FormatRIS:
+		entries, errs = parseRIS(string(data))
+	default:
+		return nil, nil, fmt.Errorf("unsupported bibliography format %q", format)
+	}
+	return entries, errs, nil
+}
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+func parseYear(raw string) (int, bool) {
+	m := yearPattern.FindString(raw)
+	if m == "" {
+		return 0, false
+	}
+	year, err := strconv.Atoi(m)
+	if err != nil {
+		return 0, false
+	}
+	return year, true
+}
+
+// NormalizeDOI strips resolver prefixes so that DOIs compare equal regardless of source.
+func NormalizeDOI(raw string) string {
+	doi := strings.TrimSpace(raw)
+	lower := strings.ToLower(doi)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		if strings.HasPrefix(lower, prefix) {
+			doi = doi[len(prefix):]
+			break
+		}
+	}
+	return strings.TrimSpace(doi)
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,398 @@
+package bibliography
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var bibtexMonths = map[string]string{
+	"jan": "January", "feb": "February", "mar": "March", "apr": "April",
+	"may": "May", "jun": "June", "jul": "July", "aug": "August",
+	"sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+const (
+	// Longest field or @string value after macro expansion
+	maxValueLength = 64 << 10
+	// Bytes all macro references of a file may expand to, so that chained
+	// @string definitions cannot grow exponentially
+	maxExpansion = 4 << 20
+)
+
+type bibtexParser struct {
+	src    string
+	pos    int
+	line   int
+	macros map[string]string
+	// expanded counts bytes written for macro references so far
+	expanded int
+}
+
+func parseBibTeX(src string) ([]Entry, []ParseError) {
+	return newBibTeXParser(src).parse()
+}
+
+func newBibTeXParser(src string) *bibtexParser {
+	p := &bibtexParser{src: src, line: 1, macros: make(map[string]string, len(bibtexMonths))}
+	for k, v := range bibtexMonths {
+		p.macros[k] = v
+	}
+	return p
+}
+
+func (p *bibtexParser) parse() ([]Entry, []ParseError) {
+	var (
+		entries []Entry
+		errs    []ParseError
+	)
+	for p.skipToEntry() {
+		start, startPos := p.line, p.pos
+		entry, keep, err := p.parseEntry()
+		if err != nil {
+			errs = append(errs, ParseError{Line: start, Message: err.Error()})
+			// Rewind so that an unbalanced brace does not swallow the following entries.
+			p.pos, p.line = startPos+1, start
+			p.recover()
+			continue
+		}
+		if !keep {
+			continue
+		}
+		entry.Line = start
+		if entry.Title == "" {
+			errs = append(errs, ParseError{Line: start, Message: fmt.Sprintf("entry %q has no title", entry.Key)})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, errs
+}
+
+// skipToEntry moves to the next '@'. Text between entries is treated as a comment.
+func (p *bibtexParser) skipToEntry() bool {
+	for p.pos < len(p.src) {
+		if p.src[p.pos] == '@' {
+			return true
+		}
+		p.advance()
+	}
+	return false
+}
+
+// recover skips the rest of a broken entry up to the next '@' starting a line.
+func (p *bibtexParser) recover() {
+	for p.pos < len(p.src) {
+		if p.src[p.pos] == '@' && p.atLineStart() {
+			return
+		}
+		p.advance()
+	}
+}
+
+func (p *bibtexParser) atLineStart() bool {
+	for i := p.pos - 1; i >= 0; i-- {
+		switch p.src[i] {
+		case '\n':
+			return true
+		case ' ', '\t', '\r':
+			continue
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (p *bibtexParser) advance() {
+	if p.src[p.pos] == '\n' {
+		p.line++
+	}
+	p.pos++
+}
+
+func (p *bibtexParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.advance()
+	}
+}
+
+func (p *bibtexParser) peek() (byte, bool) {
+	if p.pos >= len(p.src) {
+		return 0, false
+	}
+	return p.src[p.pos], true
+}
+
+func (p *bibtexParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ',' || c == '=' || c == '{' || c == '}' || c == '(' || c == ')' || c == '"' || c == '#' || unicode.IsSpace(rune(c)) {
+			break
+		}
+		p.advance()
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *bibtexParser) expect(c byte) error {
+	p.skipSpaces()
+	got, ok := p.peek()
+	if !ok {
+		return fmt.Errorf("unexpected end of file, expected %q", c)
+	}
+	if got != c {
+		return fmt.Errorf("expected %q, got %q", c, got)
+	}
+	p.advance()
+	return nil
+}
+
+// parseEntry parses one @type{...} block. keep is false for @comment, @preamble and @string.
+func (p *bibtexParser) parseEntry() (entry Entry, keep bool, err error) {
+	p.advance() // '@'
+	kind := strings.ToLower(p.ident())
+	if kind == "" {
+		return entry, false, fmt.Errorf("missing entry type after '@'")
+	}
+	p.skipSpaces()
+	open, ok := p.peek()
+	if !ok || (open != '{' && open != '(') {
+		if kind == "comment" {
+			return entry, false, nil
+		}
+		return entry, false, fmt.Errorf("expected '{' after @%s", kind)
+	}
+	closer := byte('}')
+	if open == '(' {
+		closer = ')'
+	}
+
+	switch kind {
+	case "comment", "preamble":
+		if _, err := p.balanced(open, closer); err != nil {
+			return entry, false, err
+		}
+		return entry, false, nil
+	case "string":
+		p.advance()
+		p.skipSpaces()
+		name := strings.ToLower(p.ident())
+		if err := p.expect('='); err != nil {
+			return entry, false, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return entry, false, err
+		}
+		if err := p.expect(closer); err != nil {
+			return entry, false, err
+		}
+		p.macros[name] = value
+		return entry, false, nil
+	}
+
+	p.advance()
+	p.skipSpaces()
+	entry.Key = p.ident()
+	if entry.Key == "" {
+		return entry, false, fmt.Errorf("@%s entry has no citation key", kind)
+	}
+
+	fields := make(map[string]string)
+	for {
+		p.skipSpaces()
+		c, ok := p.peek()
+		if !ok {
+			return entry, false, fmt.Errorf("entry %q is not terminated", entry.Key)
+		}
+		if c == closer {
+			p.advance()
+			break
+		}
+		if c != ',' {
+			return entry, false, fmt.Errorf("expected ',' or %q in entry %q, got %q", closer, entry.Key, c)
+		}
+		p.advance()
+		p.skipSpaces()
+		if c, ok := p.peek(); ok && c == closer {
+			continue
+		}
+		name := strings.ToLower(p.ident())
+		if name == "" {
+			return entry, false, fmt.Errorf("expected field name in entry %q", entry.Key)
+		}
+		if err := p.expect('='); err != nil {
+			return entry, false, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return entry, false, err
+		}
+		fields[name] = value
+	}
+
+	entry.Title = fields["title"]
+	entry.Abstract = fields["abstract"]
+	entry.DOI = NormalizeDOI(fields["doi"])
+	entry.URL = fields["url"]
+	rawYear := fields["year"]
+	if rawYear == "" {
+		rawYear = fields["date"]
+	}
+	if rawYear != "" {
+		year, ok := parseYear(rawYear)
+		if !ok {
+			return entry, false, fmt.Errorf("entry %q has invalid year %q", entry.Key, rawYear)
+		}
+		entry.Year = year
+	}
+	return entry, true, nil
+}
+
+// value parses a field value: {..}, "..", a number or a @string macro, joined with '#'.
+func (p *bibtexParser) value() (string, error) {
+	var b strings.Builder
+	for {
+		p.skipSpaces()
+		c, ok := p.peek()
+		if !ok {
+			return "", fmt.Errorf("unexpected end of file in field value")
+		}
+		switch {
+		case c == '{':
+			part, err := p.braced()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(part)
+		case c == '"':
+			part, err := p.quoted()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(part)
+		default:
+			word := p.ident()
+			if word == "" {
+				return "", fmt.Errorf("expected field value, got %q", c)
+			}
+			if isDigits(word) {
+				b.WriteString(word)
+			} else if v, known := p.macros[strings.ToLower(word)]; known {
+				if p.expanded += len(v); p.expanded > maxExpansion {
+					return "", fmt.Errorf("string macros expand to more than %d bytes in total", maxExpansion)
+				}
+				if b.Len()+len(v) > maxValueLength {
+					return "", fmt.Errorf("field value exceeds %d bytes", maxValueLength)
+				}
+				b.WriteString(v)
+			} else {
+				return "", fmt.Errorf("undefined string macro %q", word)
+			}
+		}
+		if b.Len() > maxValueLength {
+			return "", fmt.Errorf("field value exceeds %d bytes", maxValueLength)
+		}
+		p.skipSpaces()
+		if c, ok := p.peek(); ok && c == '#' {
+			p.advance()
+			continue
+		}
+		return cleanLatex(b.String()), nil
+	}
+}
+
+// braced returns the content of a balanced {...} group without the outer braces.
+func (p *bibtexParser) braced() (string, error) {
+	return p.balanced('{', '}')
+}
+
+func (p *bibtexParser) balanced(open, closer byte) (string, error) {
+	line := p.line
+	p.advance()
+	start := p.pos
+	depth := 1
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.advance()
+			if p.pos >= len(p.src) {
+				continue
+			}
+		case open:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				out := p.src[start:p.pos]
+				p.advance()
+				return out, nil
+			}
+		}
+		p.advance()
+	}
+	return "", fmt.Errorf("unbalanced braces starting at line %d", line)
+}
+
+func (p *bibtexParser) quoted() (string, error) {
+	line := p.line
+	p.advance()
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.advance()
+			if p.pos >= len(p.src) {
+				continue
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"':
+			if depth == 0 {
+				out := p.src[start:p.pos]
+				p.advance()
+				return out, nil
+			}
+		}
+		p.advance()
+	}
+	return "", fmt.Errorf("unterminated quoted value starting at line %d", line)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// cleanLatex drops grouping braces and unescapes the few LaTeX specials common in titles.
+func cleanLatex(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte(`&%_$#{}`, s[i+1]) >= 0 {
+				i++
+				b.WriteByte(s[i])
+				continue
+			}
+			b.WriteByte(c)
+		case '{', '}':
+		case '~':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return collapseSpaces(b.String())
+}
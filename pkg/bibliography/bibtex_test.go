@@ -0,0 +1,68 @@
+package bibliography
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseBibTeXMacros(t *testing.T) {
+	src := `@string{ven = "Journal of " # "Tests"}
+@article{a,
+  title = {Paper} # " in " # ven,
+  year = 2020,
+  month = jan,
+}`
+	entries, errs, err := Parse(FormatBibTeX, strings.NewReader(src))
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("Parse() err = %v, errs = %v", err, errs)
+	}
+	if len(entries) != 1 || entries[0].Title != "Paper in Journal of Tests" || entries[0].Year != 2020 {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestParseBibTeXMacroExpansionIsCapped(t *testing.T) {
+	// m11 is 32 KiB, built by doubling a 16 byte string; each @string
+	// definition expands both references to the previous one
+	var b strings.Builder
+	b.WriteString("@string{m0 = \"xxxxxxxxxxxxxxxx\"}\n")
+	for i := 1; i <= 11; i++ {
+		fmt.Fprintf(&b, "@string{m%d = m%d # m%d}\n", i, i-1, i-1)
+	}
+	const macroSize = 16 << 11
+	built := 16 * (1<<12 - 2)
+	// Every copy stays under maxValueLength, together they pass maxExpansion
+	copies := (maxExpansion-built)/macroSize + 1
+	for i := 1; i <= copies; i++ {
+		fmt.Fprintf(&b, "@string{c%d = m11}\n", i)
+	}
+	b.WriteString("@article{b, title = {Still parsed}}\n")
+
+	p := newBibTeXParser(b.String())
+	entries, errs := p.parse()
+	if want := built + copies*macroSize; p.expanded != want || p.expanded <= maxExpansion {
+		t.Fatalf("expanded = %d, want %d, just over %d", p.expanded, want, maxExpansion)
+	}
+	if len(entries) != 1 || entries[0].Key != "b" {
+		t.Fatalf("entries = %+v, want only b", entries)
+	}
+	want := ParseError{Line: 12 + copies, Message: fmt.Sprintf("string macros expand to more than %d bytes in total", maxExpansion)}
+	if len(errs) != 1 || errs[0] != want {
+		t.Fatalf("errs = %+v, want %+v", errs, want)
+	}
+}
+
+func TestParseBibTeXErrorsHaveNoLinePrefix(t *testing.T) {
+	src := "@article{a,\n  title = {T}\n  year = 2020\n}\n"
+	_, errs, err := Parse(FormatBibTeX, strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("errs = %v, want 1", errs)
+	}
+	if errs[0].Line != 1 || strings.HasPrefix(errs[0].Message, "line ") {
+		t.Fatalf("errs[0] = %+v", errs[0])
+	}
+}
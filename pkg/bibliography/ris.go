@@ -0,0 +1,128 @@
+package bibliography
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// risLine matches "TY  - JOUR"; some exporters emit a single space before the dash.
+var risLine = regexp.MustCompile(`^([A-Z][A-Z0-9])\s{1,2}-(?:\s(.*))?$`)
+
+type risRecord struct {
+	line      int
+	tags      map[string][]string
+	lastTag   string
+	malformed []string
+}
+
+func parseRIS(src string) ([]Entry, []ParseError) {
+	var (
+		entries []Entry
+		errs    []ParseError
+		cur     *risRecord
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		if lineNo == 1 {
+			raw = strings.TrimPrefix(raw, "\ufeff")
+		}
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		m := risLine.FindStringSubmatch(strings.TrimSpace(raw))
+		if m == nil {
+			if cur == nil {
+				errs = append(errs, ParseError{Line: lineNo, Message: "text outside of a TY/ER record"})
+				continue
+			}
+			// Continuation of a wrapped value (long abstracts are often split).
+			if cur.lastTag == "" {
+				cur.malformed = append(cur.malformed, fmt.Sprintf("malformed tag line %d", lineNo))
+				continue
+			}
+			vals := cur.tags[cur.lastTag]
+			vals[len(vals)-1] += " " + strings.TrimSpace(raw)
+			continue
+		}
+
+		tag, value := m[1], strings.TrimSpace(m[2])
+		switch tag {
+		case "TY":
+			if cur != nil {
+				errs = append(errs, ParseError{Line: cur.line, Message: fmt.Sprintf("record is not terminated with ER before line %d", lineNo)})
+			}
+			cur = &risRecord{line: lineNo, tags: make(map[string][]string)}
+			continue
+		case "ER":
+			if cur == nil {
+				errs = append(errs, ParseError{Line: lineNo, Message: "ER without matching TY"})
+				continue
+			}
+			entry, err := cur.entry()
+			if err != nil {
+				errs = append(errs, ParseError{Line: cur.line, Message: err.Error()})
+			} else {
+				entries = append(entries, entry)
+			}
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			errs = append(errs, ParseError{Line: lineNo, Message: fmt.Sprintf("tag %s outside of a TY/ER record", tag)})
+			continue
+		}
+		cur.tags[tag] = append(cur.tags[tag], value)
+		cur.lastTag = tag
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, ParseError{Line: lineNo + 1, Message: err.Error()})
+	}
+	if cur != nil {
+		errs = append(errs, ParseError{Line: cur.line, Message: "record is not terminated with ER"})
+	}
+	return entries, errs
+}
+
+func (r *risRecord) first(tags ...string) string {
+	for _, tag := range tags {
+		for _, v := range r.tags[tag] {
+			if v = collapseSpaces(v); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+func (r *risRecord) entry() (Entry, error) {
+	if len(r.malformed) > 0 {
+		return Entry{}, fmt.Errorf("%s", strings.Join(r.malformed, "; "))
+	}
+	entry := Entry{
+		Line:     r.line,
+		Key:      r.first("ID"),
+		Title:    r.first("TI", "T1", "CT", "BT"),
+		Abstract: r.first("AB", "N2"),
+		DOI:      NormalizeDOI(r.first("DO")),
+		URL:      r.first("UR", "L2"),
+	}
+	if entry.Title == "" {
+		return Entry{}, fmt.Errorf("record has no title (TI/T1)")
+	}
+	if rawYear := r.first("PY", "Y1", "DA"); rawYear != "" {
+		year, ok := parseYear(rawYear)
+		if !ok {
+			return Entry{}, fmt.Errorf("record has invalid year %q", rawYear)
+		}
+		entry.Year = year
+	}
+	return entry, nil
+}
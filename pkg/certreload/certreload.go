@@ -0,0 +1,93 @@
+// Package certreload serves a TLS certificate from PEM files and picks up
+// renewed files without a restart.
+package certreload
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Reloader struct {
+	certFile string
+	keyFile  string
+	logger   *logrus.Logger
+
+	cert atomic.Pointer[tls.Certificate]
+	// PEM contents of the loaded pair, compared to detect changes
+	mu      sync.Mutex
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// New loads the certificate and key, failing if they cannot be used.
+func New(certFile, keyFile string, logger *logrus.Logger) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, logger: logger}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate is meant for tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Reload reads the files again and reports whether the certificate
+// changed. On error the current certificate is kept.
+func (r *Reloader) Reload() (bool, error) {
+	certPEM, err := os.ReadFile(r.certFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read TLS certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read TLS key: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if bytes.Equal(certPEM, r.certPEM) && bytes.Equal(keyPEM, r.keyPEM) {
+		return false, nil
+	}
+	// Cert and key are replaced one after the other, a mismatched pair
+	// fails here and is retried on the next poll
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("invalid TLS certificate or key: %w", err)
+	}
+	r.cert.Store(&cert)
+	r.certPEM, r.keyPEM = certPEM, keyPEM
+	return true, nil
+}
+
+// Run checks the files every interval until ctx is done.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := r.Reload()
+		if err != nil {
+			r.logger.Errorf("TLS certificate reload failed, keeping the current one: %v", err)
+			continue
+		}
+		if changed {
+			r.logger.Infof("TLS certificate reloaded from %s", r.certFile)
+		}
+	}
+}
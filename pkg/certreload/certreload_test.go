@@ -0,0 +1,203 @@
+package certreload
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newPair returns a PEM self-signed certificate for name and its key.
+func newPair(t *testing.T, name string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+type files struct {
+	t         *testing.T
+	cert, key string
+}
+
+func newFiles(t *testing.T) files {
+	dir := t.TempDir()
+	return files{t: t, cert: filepath.Join(dir, "tls.crt"), key: filepath.Join(dir, "tls.key")}
+}
+
+func (f files) write(certPEM, keyPEM []byte) {
+	f.t.Helper()
+	if err := os.WriteFile(f.cert, certPEM, 0o600); err != nil {
+		f.t.Fatal(err)
+	}
+	if err := os.WriteFile(f.key, keyPEM, 0o600); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func newLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// served returns the common name of the certificate r serves.
+func served(t *testing.T, r *Reloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil || cert == nil {
+		t.Fatalf("GetCertificate() = %v, %v", cert, err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestReloadPicksUpRenewedCertificate(t *testing.T) {
+	f := newFiles(t)
+	f.write(newPair(t, "old.example"))
+	r, err := New(f.cert, f.key, newLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := served(t, r); got != "old.example" {
+		t.Fatalf("serving %s, want old.example", got)
+	}
+
+	if changed, err := r.Reload(); changed || err != nil {
+		t.Fatalf("Reload() of unchanged files = %v, %v", changed, err)
+	}
+	f.write(newPair(t, "new.example"))
+	if changed, err := r.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() of renewed files = %v, %v", changed, err)
+	}
+	if got := served(t, r); got != "new.example" {
+		t.Fatalf("serving %s after reload, want new.example", got)
+	}
+}
+
+func TestRunReloadsOnFileChange(t *testing.T) {
+	f := newFiles(t)
+	f.write(newPair(t, "old.example"))
+	r, err := New(f.cert, f.key, newLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		r.Run(ctx, 5*time.Millisecond)
+		close(stopped)
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	f.write(newPair(t, "new.example"))
+	deadline := time.Now().Add(2 * time.Second)
+	for served(t, r) != "new.example" {
+		if time.Now().After(deadline) {
+			t.Fatal("renewed certificate not picked up by Run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBadKeyPairKeepsCurrentCertificate(t *testing.T) {
+	f := newFiles(t)
+	oldCert, oldKey := newPair(t, "old.example")
+	f.write(oldCert, oldKey)
+	r, err := New(f.cert, f.key, newLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCert, newKey := newPair(t, "new.example")
+	tests := []struct {
+		name      string
+		cert, key []byte
+		want      string
+	}{
+		// The certificate is replaced before its key
+		{"mismatched pair", newCert, oldKey, "invalid TLS certificate or key"},
+		{"garbage certificate", []byte("not a certificate"), newKey, "invalid TLS certificate or key"},
+		{"empty key", newCert, nil, "invalid TLS certificate or key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.write(tt.cert, tt.key)
+			changed, err := r.Reload()
+			if changed || err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Reload() = %v, %v, want %q", changed, err, tt.want)
+			}
+			if got := served(t, r); got != "old.example" {
+				t.Fatalf("serving %s, want old.example kept", got)
+			}
+		})
+	}
+
+	if err := os.Remove(f.key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reload(); err == nil || !strings.Contains(err.Error(), "failed to read TLS key") {
+		t.Fatalf("Reload() with missing key = %v", err)
+	}
+	if got := served(t, r); got != "old.example" {
+		t.Fatalf("serving %s, want old.example kept", got)
+	}
+
+	// Once the key is written too the new pair is served
+	f.write(newCert, newKey)
+	if changed, err := r.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() of the completed pair = %v, %v", changed, err)
+	}
+	if got := served(t, r); got != "new.example" {
+		t.Fatalf("serving %s, want new.example", got)
+	}
+}
+
+func TestNewFailsForInvalidPair(t *testing.T) {
+	f := newFiles(t)
+	certPEM, _ := newPair(t, "a.example")
+	_, otherKey := newPair(t, "b.example")
+	f.write(certPEM, otherKey)
+	if _, err := New(f.cert, f.key, newLogger()); err == nil {
+		t.Fatal("New() accepted a mismatched pair")
+	}
+	if _, err := New(filepath.Join(t.TempDir(), "missing.crt"), f.key, newLogger()); err == nil {
+		t.Fatal("New() accepted a missing certificate")
+	}
+}
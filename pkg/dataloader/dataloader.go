@@ -0,0 +1,101 @@
+// Package dataloader collects keys requested while a GraphQL level is being
+// resolved and fetches them in one batch. Every key is fetched at most once
+// per Loader, so a Loader must live no longer than a single request.
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc fetches values for keys. values and errs must be aligned with
+// keys; errs may be nil when every key succeeded.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (values []V, errs []error)
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type Loader[K comparable, V any] struct {
+	batch BatchFunc[K, V]
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending []K
+}
+
+func New[K comparable, V any](batch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		batch: batch,
+		cache: make(map[K]*result[V]),
+	}
+}
+
+// Load queues key and returns a thunk. The first thunk called fetches all
+// keys queued so far; repeated keys share one result.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	r, ok := l.cache[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.cache[key] = r
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.dispatch(ctx)
+		<-r.done
+		return r.value, r.err
+	}
+}
+
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	results := make([]*result[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.cache[key]
+	}
+	l.mu.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+
+	values, errs := l.batch(ctx, keys)
+	for i, r := range results {
+		if i < len(values) {
+			r.value = values[i]
+		}
+		if i < len(errs) {
+			r.err = errs[i]
+		}
+		close(r.done)
+	}
+}
+
+// ForEach is a BatchFunc helper for backends without batch endpoints: it
+// calls fetch for every key with at most parallelism calls in flight.
+func ForEach[K comparable, V any](ctx context.Context, keys []K, parallelism int, fetch func(ctx context.Context, key K) (V, error)) ([]V, []error) {
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, key K) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			values[i], errs[i] = fetch(ctx, key)
+		}(i, key)
+	}
+	wg.Wait()
+	return values, errs
+}
@@ -0,0 +1,187 @@
+// Package lifecycle coordinates the shutdown of the gateway: readiness is
+// reported as failing first so load balancers stop sending traffic, then
+// long-lived streams are told to finish and the registered stop hooks run
+// in reverse order of registration, like deferred calls.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+type Manager struct {
+	logger *logrus.Logger
+
+	ready    atomic.Bool
+	draining chan struct{}
+	drain    sync.Once
+
+	mu      sync.Mutex
+	hooks   []hook
+	streams sync.WaitGroup
+	active  atomic.Int64
+
+	workersCtx  context.Context
+	stopWorkers context.CancelFunc
+	workers     sync.WaitGroup
+}
+
+func New(logger *logrus.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		logger:      logger,
+		draining:    make(chan struct{}),
+		workersCtx:  ctx,
+		stopWorkers: cancel,
+	}
+}
+
+// SetReady marks the service as ready to receive traffic.
+func (m *Manager) SetReady() {
+	m.ready.Store(true)
+}
+
+// Ready reports whether the service accepts traffic. It is false before
+// start and from the moment shutdown begins.
+func (m *Manager) Ready() bool {
+	return m != nil && m.ready.Load()
+}
+
+// Draining is closed when long-lived streams must finish. A nil manager
+// never drains.
+func (m *Manager) Draining() <-chan struct{} {
+	if m == nil {
+		return nil
+	}
+	return m.draining
+}
+
+// OnStop registers a hook run on shutdown. Hooks run in reverse order of
+// registration, so register them as the resources are created.
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, stop: stop})
+}
+
+// Go runs a background worker until the workers are stopped.
+func (m *Manager) Go(run func(ctx context.Context)) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		run(m.workersCtx)
+	}()
+}
+
+// StopWorkers cancels the workers started with Go and waits for them.
+func (m *Manager) StopWorkers(ctx context.Context) error {
+	m.stopWorkers()
+	return wait(ctx, &m.workers)
+}
+
+// Stream registers a long-lived connection such as a WebSocket or an SSE
+// response. The returned context is cancelled when draining starts; done
+// must be called when the connection ends. ok is false when the service
+// is already draining and the stream should be refused.
+func (m *Manager) Stream(parent context.Context) (ctx context.Context, done func(), ok bool) {
+	// mu orders Add before the drain so WaitStreams never misses a stream
+	m.mu.Lock()
+	select {
+	case <-m.draining:
+		m.mu.Unlock()
+		return parent, func() {}, false
+	default:
+	}
+	m.streams.Add(1)
+	m.active.Add(1)
+	m.mu.Unlock()
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-m.draining:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			m.active.Add(-1)
+			m.streams.Done()
+		})
+	}, true
+}
+
+// Streams returns the number of open streams.
+func (m *Manager) Streams() int64 {
+	return m.active.Load()
+}
+
+// WaitStreams waits until every stream has finished.
+func (m *Manager) WaitStreams(ctx context.Context) error {
+	return wait(ctx, &m.streams)
+}
+
+// Shutdown fails readiness, waits drainDelay for load balancers to notice,
+// closes streams and runs the stop hooks. Each hook runs even if an
+// earlier one failed; the errors are joined.
+func (m *Manager) Shutdown(ctx context.Context, drainDelay time.Duration) error {
+	m.ready.Store(false)
+	if drainDelay > 0 {
+		m.logger.Infof("Readiness is failing, draining for %s", drainDelay)
+		timer := time.NewTimer(drainDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+	m.mu.Lock()
+	m.drain.Do(func() { close(m.draining) })
+	hooks := m.hooks
+	m.hooks = nil
+	m.mu.Unlock()
+	if n := m.Streams(); n > 0 {
+		m.logger.Infof("Closing %d open streams", n)
+	}
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		started := time.Now()
+		if err := h.stop(ctx); err != nil {
+			m.logger.WithError(err).Errorf("Failed to stop %s", h.name)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		m.logger.Infof("Stopped %s in %s", h.name, time.Since(started).Round(time.Millisecond))
+	}
+	return errors.Join(errs...)
+}
+
+// wait returns when wg is done or ctx expires.
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,162 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestManager() *Manager {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return New(logger)
+}
+
+func TestReadinessFailsBeforeDraining(t *testing.T) {
+	m := newTestManager()
+	if m.Ready() {
+		t.Fatal("Ready() = true before SetReady")
+	}
+	m.SetReady()
+	if !m.Ready() {
+		t.Fatal("Ready() = false after SetReady")
+	}
+
+	_, done, ok := m.Stream(context.Background())
+	if !ok {
+		t.Fatal("Stream() refused before shutdown")
+	}
+	defer done()
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- m.Shutdown(context.Background(), 100*time.Millisecond) }()
+
+	// During the drain delay readiness fails but streams are not closed yet
+	deadline := time.Now().Add(time.Second)
+	for m.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatal("Ready() still true after Shutdown started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-m.Draining():
+		t.Fatal("draining started before the drain delay passed")
+	default:
+	}
+
+	if err := <-shutdown; err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	select {
+	case <-m.Draining():
+	default:
+		t.Fatal("Draining() not closed after Shutdown")
+	}
+}
+
+func TestStreamsAreDrained(t *testing.T) {
+	m := newTestManager()
+	ctx, done, ok := m.Stream(context.Background())
+	if !ok {
+		t.Fatal("Stream() refused")
+	}
+	if got := m.Streams(); got != 1 {
+		t.Fatalf("Streams() = %d, want 1", got)
+	}
+
+	// The stream ends when its context is cancelled by the drain
+	closed := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		done()
+		close(closed)
+	}()
+	m.OnStop("HTTP server", func(ctx context.Context) error { return m.WaitStreams(ctx) })
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Shutdown(stopCtx, 0); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+	select {
+	case <-closed:
+	default:
+		t.Fatal("Shutdown returned before the stream finished")
+	}
+	if got := m.Streams(); got != 0 {
+		t.Fatalf("Streams() = %d after drain, want 0", got)
+	}
+	if _, _, ok := m.Stream(context.Background()); ok {
+		t.Fatal("Stream() accepted while draining")
+	}
+}
+
+func TestWaitStreamsTimesOut(t *testing.T) {
+	m := newTestManager()
+	_, done, _ := m.Stream(context.Background())
+	defer done()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.WaitStreams(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitStreams() = %v, want deadline exceeded", err)
+	}
+}
+
+func TestHooksRunInReverseOrder(t *testing.T) {
+	m := newTestManager()
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return err
+		}
+	}
+
+	workerStopped := make(chan struct{})
+	m.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerStopped)
+	})
+	m.OnStop("postgres pool", record("postgres pool", nil))
+	m.OnStop("AI gRPC connection", record("AI gRPC connection", errors.New("close failed")))
+	m.OnStop("workers", func(ctx context.Context) error {
+		if err := m.StopWorkers(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-workerStopped:
+		default:
+			t.Error("StopWorkers returned before the worker exited")
+		}
+		return record("workers", nil)(ctx)
+	})
+	m.OnStop("HTTP server", record("HTTP server", nil))
+
+	err := m.Shutdown(context.Background(), 0)
+	want := []string{"HTTP server", "workers", "AI gRPC connection", "postgres pool"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("hooks ran in order %v, want %v", order, want)
+	}
+	if err == nil || err.Error() != "AI gRPC connection: close failed" {
+		t.Fatalf("Shutdown() = %v, want the failed hook joined", err)
+	}
+
+	// Hooks run once
+	order = nil
+	if err := m.Shutdown(context.Background(), 0); err != nil || len(order) != 0 {
+		t.Fatalf("second Shutdown() = %v, ran %v", err, order)
+	}
+}
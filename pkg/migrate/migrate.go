@@ -0,0 +1,346 @@
+// Package migrate applies versioned SQL migrations to Postgres. Migrations
+// are <version>_<name>.up.sql and <version>_<name>.down.sql files; the
+// applied version is kept in schema_migrations in the layout used by
+// golang-migrate, so databases migrated by tools/migrator carry over.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+// NilVersion is the version of a database without applied migrations.
+const NilVersion int64 = -1
+
+// migrationLock serializes migrations of gateway replicas starting together.
+const migrationLock = 0x6d696772 // "migr"
+
+var ErrNoChange = errors.New("no change")
+
+// DirtyError is returned when a previous migration failed half way. The
+// schema has to be fixed by hand and the version set with Force.
+type DirtyError struct {
+	Version int64
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("database is dirty at version %d, fix it and run migrate force", e.Version)
+}
+
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+// Status describes a migration and whether it is applied.
+type Status struct {
+	Migration
+	Applied bool
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	fsys       fs.FS
+	dir        string
+	migrations []Migration
+	logger     *logrus.Logger
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// New reads the migrations in dir of fsys.
+func New(pool *pgxpool.Pool, fsys fs.FS, dir string, logger *logrus.Logger) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		match := fileName.FindStringSubmatch(e.Name())
+		if e.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", e.Name(), err)
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.up = e.Name()
+		} else {
+			m.down = e.Name()
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return &Migrator{pool: pool, fsys: fsys, dir: dir, migrations: migrations, logger: logger}, nil
+}
+
+// Latest returns the version of the newest migration, NilVersion if there
+// are none.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return NilVersion
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the applied version and whether it is dirty.
+func (m *Migrator) Version(ctx context.Context) (version int64, dirty bool, err error) {
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err = readVersion(ctx, conn)
+		return err
+	})
+	return version, dirty, err
+}
+
+// Status lists all migrations with their state, and the applied version.
+func (m *Migrator) Status(ctx context.Context) ([]Status, int64, bool, error) {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	out := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		out = append(out, Status{Migration: mig, Applied: mig.Version <= version})
+	}
+	return out, version, dirty, nil
+}
+
+// Up applies up to steps pending migrations, all of them if steps <= 0.
+// It returns ErrNoChange if the database is up to date.
+func (m *Migrator) Up(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		version, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		applied := 0
+		for _, mig := range m.migrations {
+			if mig.Version <= version {
+				continue
+			}
+			if steps > 0 && applied == steps {
+				break
+			}
+			if err := m.apply(ctx, conn, mig.up, mig.Version, mig); err != nil {
+				return err
+			}
+			applied++
+		}
+		if applied == 0 {
+			return ErrNoChange
+		}
+		return nil
+	})
+}
+
+// Down reverts the given number of applied migrations, at least one.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		steps = 1
+	}
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		version, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.down(ctx, conn, version, steps, NilVersion)
+	})
+}
+
+// To migrates up or down to target, NilVersion reverting all migrations.
+func (m *Migrator) To(ctx context.Context, target int64) error {
+	if target != NilVersion && m.index(target) < 0 {
+		return fmt.Errorf("no migration with version %d", target)
+	}
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		version, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		switch {
+		case target == version:
+			return ErrNoChange
+		case target < version:
+			return m.down(ctx, conn, version, len(m.migrations), target)
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > version && mig.Version <= target {
+				if err := m.apply(ctx, conn, mig.up, mig.Version, mig); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Force records version as applied and clean without running migrations,
+// after a failed migration was fixed by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != NilVersion && m.index(version) < 0 {
+		return fmt.Errorf("no migration with version %d", version)
+	}
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+		if err := setVersion(ctx, tx, version); err != nil {
+			return err
+		}
+		return tx.Commit(ctx)
+	})
+}
+
+// down reverts migrations from version downwards, at most steps of them
+// and none at or below target.
+func (m *Migrator) down(ctx context.Context, conn *pgxpool.Conn, version int64, steps int, target int64) error {
+	i := m.index(version)
+	if version == NilVersion {
+		return ErrNoChange
+	}
+	if i < 0 {
+		return fmt.Errorf("applied version %d has no migration file", version)
+	}
+	for ; i >= 0 && steps > 0 && m.migrations[i].Version > target; i, steps = i-1, steps-1 {
+		mig := m.migrations[i]
+		if mig.down == "" {
+			return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+		}
+		previous := NilVersion
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+		if err := m.apply(ctx, conn, mig.down, previous, mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply runs one migration file and records version in one transaction, so
+// a failing migration leaves neither changes nor a dirty version behind.
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, file string, version int64, mig Migration) error {
+	body, err := fs.ReadFile(m.fsys, path.Join(m.dir, file))
+	if err != nil {
+		return fmt.Errorf("read migration %s: %w", file, err)
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if len(body) > 0 {
+		if _, err := tx.Exec(ctx, string(body)); err != nil {
+			return fmt.Errorf("migration %s: %w", file, err)
+		}
+	}
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("migration %s: %w", file, err)
+	}
+	m.logger.WithFields(logrus.Fields{"migration": mig.Version, "name": mig.Name, "file": file}).Info("Migration applied")
+	return nil
+}
+
+func (m *Migrator) index(version int64) int {
+	for i, mig := range m.migrations {
+		if mig.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// locked runs fn on one connection holding the migration advisory lock.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLock); err != nil {
+		return fmt.Errorf("lock migrations: %w", err)
+	}
+	defer func() {
+		// A connection still holding the session lock must not go back to the pool
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLock); err != nil {
+			conn.Hijack().Close(context.Background())
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func readVersion(ctx context.Context, conn *pgxpool.Conn) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NilVersion, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+func cleanVersion(ctx context.Context, conn *pgxpool.Conn) (int64, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, &DirtyError{Version: version}
+	}
+	return version, nil
+}
+
+func setVersion(ctx context.Context, tx pgx.Tx, version int64) error {
+	if _, err := tx.Exec(ctx, `TRUNCATE schema_migrations`); err != nil {
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	if version == NilVersion {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version); err != nil {
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package securetoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+)
+
+// DefaultSize is the number of random bytes in a token (256 bits).
+const DefaultSize = 32
+
+// Generate returns a URL-safe random token. Only its Hash should be persisted.
+func Generate(size int) (string, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Hash returns the SHA-256 digest used to look tokens up without storing them.
+func Hash(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// Equal compares two hashes in constant time.
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
@@ -3,37 +3,140 @@ package storage
 import (
 	"VKR_gateway_service/internal/config"
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
 )
 
-func PostgresConnect(ctx context.Context, cfg config.PostgresConfig) (pool *pgxpool.Pool, err error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+// DB is the primary pool and an optional read replica pool.
+type DB struct {
+	Primary *pgxpool.Pool
+	Replica *pgxpool.Pool
+}
 
-	parseConfig, err := pgxpool.ParseConfig(dsn)
-	if err != nil {
-		err = fmt.Errorf("failed to parse PostgreSQL connection string: %v", err)
-		return nil, err
+// Reader returns the pool for read-only queries that tolerate replication
+// lag: the replica if one is configured, the primary otherwise.
+func (db *DB) Reader() *pgxpool.Pool {
+	if db.Replica != nil {
+		return db.Replica
+	}
+	return db.Primary
+}
+
+// Reset replaces idle connections now and busy ones when they are
+// released, e.g. after credentials were rotated.
+func (db *DB) Reset() {
+	db.Primary.Reset()
+	if db.Replica != nil {
+		db.Replica.Reset()
 	}
+}
 
-	parseConfig.MaxConns = 10
-	parseConfig.MaxConnIdleTime = 30 * time.Minute
+func (db *DB) Close() {
+	db.Primary.Close()
+	if db.Replica != nil {
+		db.Replica.Close()
+	}
+}
 
-	pool, err = pgxpool.NewWithConfig(ctx, parseConfig)
+// PostgresConnect opens the primary pool and, if configured, the replica
+// pool, retrying until cfg.StartupTimeout while Postgres is not ready.
+// current, if not nil, returns the config whose credentials are used for
+// new connections, so a rotated password applies without recreating the
+// pools.
+func PostgresConnect(ctx context.Context, cfg config.PostgresConfig, current func() config.PostgresConfig, logger *logrus.Logger) (*DB, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.StartupTimeout)
+	defer cancel()
 
+	primary, err := connectPool(ctx, cfg, cfg.Host, cfg.Port, current, logger)
 	if err != nil {
-		err = fmt.Errorf("failed to connect to PostgreSQL: %v", err)
 		return nil, err
 	}
+	db := &DB{Primary: primary}
+	if cfg.ReplicaHost != "" {
+		port := cfg.ReplicaPort
+		if port == 0 {
+			port = cfg.Port
+		}
+		db.Replica, err = connectPool(ctx, cfg, cfg.ReplicaHost, port, current, logger)
+		if err != nil {
+			primary.Close()
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+	}
+	return db, nil
+}
 
-	if err := pool.Ping(ctx); err != nil {
-		err = fmt.Errorf("failed to ping PostgreSQL: %v", err)
-		pool.Close()
+func connectPool(ctx context.Context, cfg config.PostgresConfig, host string, port int, current func() config.PostgresConfig, logger *logrus.Logger) (*pgxpool.Pool, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		host, port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+
+	parseConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		err = fmt.Errorf("failed to parse PostgreSQL connection string: %v", err)
 		return nil, err
 	}
 
-	return pool, nil
+	parseConfig.MinConns = int32(cfg.MinConns)
+	parseConfig.MaxConns = int32(cfg.MaxConns)
+	parseConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	parseConfig.MaxConnLifetimeJitter = cfg.MaxConnLifetimeJitter
+	parseConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	parseConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	parseConfig.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+	if cfg.ApplicationName != "" {
+		parseConfig.ConnConfig.RuntimeParams["application_name"] = cfg.ApplicationName
+	}
+	if cfg.StatementTimeout > 0 {
+		parseConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if current != nil {
+		parseConfig.BeforeConnect = func(_ context.Context, cc *pgx.ConnConfig) error {
+			c := current()
+			cc.User = c.User
+			cc.Password = c.Password
+			return nil
+		}
+	}
+
+	backoff := cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		pool, err := pgxpool.NewWithConfig(ctx, parseConfig)
+		if err != nil {
+			// The config was validated, so this is not worth retrying
+			return nil, fmt.Errorf("failed to connect to PostgreSQL: %v", err)
+		}
+		err = pool.Ping(ctx)
+		if err == nil {
+			return pool, nil
+		}
+		pool.Close()
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, "28") {
+			// Wrong credentials do not fix themselves
+			return nil, fmt.Errorf("failed to ping PostgreSQL: %v", err)
+		}
+
+		// Up to half of the backoff is random so replicas do not retry in step
+		wait := backoff/2 + rand.N(backoff/2+1)
+		logger.WithError(err).WithFields(logrus.Fields{
+			"host":    host,
+			"attempt": attempt,
+			"retry":   wait.String(),
+		}).Warn("PostgreSQL is not ready")
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to ping PostgreSQL after %d attempts: %w", attempt, errors.Join(err, ctx.Err()))
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, cfg.RetryMaxBackoff)
+	}
 }
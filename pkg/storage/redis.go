@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"VKR_gateway_service/internal/config"
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConnect opens a client. current, if not nil, returns the config
+// whose password is used for new connections, so a rotated password
+// applies without recreating the client.
+func RedisConnect(ctx context.Context, cfg config.RedisConfig, current func() config.RedisConfig) (*redis.Client, error) {
+	opts := &redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	}
+	if current != nil {
+		opts.CredentialsProvider = func() (string, string) {
+			return "", current().Password
+		}
+	}
+	client := redis.NewClient(opts)
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping Redis: %v", err)
+	}
+
+	return client, nil
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/url"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -13,7 +14,7 @@ import (
 func main() {
 	// Параметры подключения к БД
 	var (
-		user, password, host, port, dbname, migrationsPath string
+		user, password, host, port, dbname, sslmode, migrationsPath string
 	)
 
 	flag.StringVar(&user, "user", "", "PostgreSQL username")
@@ -21,6 +22,7 @@ func main() {
 	flag.StringVar(&host, "host", "localhost", "PostgreSQL host")
 	flag.StringVar(&port, "port", "5432", "PostgreSQL port")
 	flag.StringVar(&dbname, "dbname", "", "PostgreSQL database name")
+	flag.StringVar(&sslmode, "sslmode", "disable", "PostgreSQL sslmode")
 	flag.StringVar(&migrationsPath, "migrations-path", "", "Path to migration files")
 	flag.Parse()
 
@@ -34,8 +36,8 @@ func main() {
 
 	// Формируем строку подключения
 	dsn := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		user, password, host, port, dbname,
+		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
+		user, password, host, port, dbname, url.QueryEscape(sslmode),
 	)
 
 	// Создаем объект мигратора
@@ -1,3 +1,3 @@
 #!/bin/sh
 echo "Running migrations..."
-go run main.go --user="$DB_USER" --password="$DB_PASSWORD" --host="$DB_HOST" --port="$DB_PORT" --dbname="$DB_NAME" --migrations-path=./db/migrations
\ No newline at end of file
+go run main.go --user="$DB_USER" --password="$DB_PASSWORD" --host="$DB_HOST" --port="$DB_PORT" --dbname="$DB_NAME" --sslmode="${DB_SSL:-disable}" --migrations-path=./db/migrations
\ No newline at end of file